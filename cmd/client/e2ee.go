@@ -0,0 +1,21 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/ITheCorgi/grpc-chat-room/pkg/e2ee"
+)
+
+// prekeysAmount is an amount of prekeys uploaded on start
+const prekeysAmount = 20
+
+// loadKeyring loads the keyring of the file or creates a new one when there is no file yet
+func loadKeyring(path string) (*e2ee.Keyring, error) {
+	k, err := e2ee.LoadKeyring(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return e2ee.NewKeyring()
+	}
+
+	return k, err
+}
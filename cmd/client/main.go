@@ -3,150 +3,336 @@ package main
 import (
 	"bufio"
 	"context"
-	"errors"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/ITheCorgi/grpc-chat-room/internal/certs"
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/tracing"
 	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"github.com/ITheCorgi/grpc-chat-room/pkg/chatclient"
 	"github.com/manifoldco/promptui"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/metadata"
-	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 var (
 	port, user string
+	traceCfg   config.Tracing
+
+	useTLS                                bool
+	tlsCA, tlsCert, tlsKey, tlsServerName string
+
+	useE2EE  bool
+	keysFile string
 )
 
 func init() {
 	flag.StringVar(&port, "port", "8270", "--port 6666")
 	flag.StringVar(&user, "user", "testuser", "--user testuser123")
+	flag.StringVar(&traceCfg.Exporter, "trace-exporter", tracing.ExporterNone, "--trace-exporter none|stdout|file|otlp")
+	flag.StringVar(&traceCfg.Endpoint, "trace-endpoint", "localhost:4317", "--trace-endpoint localhost:4317")
+	flag.BoolVar(&traceCfg.Insecure, "trace-insecure", true, "--trace-insecure=false")
+	flag.StringVar(&traceCfg.File, "trace-file", "client-traces.jsonl", "--trace-file ./traces.jsonl")
+	flag.BoolVar(&useTLS, "tls", false, "--tls")
+	flag.StringVar(&tlsCA, "tls-ca", "", "--tls-ca ./ca.pem, system CA is used when empty")
+	flag.StringVar(&tlsCert, "tls-cert", "", "--tls-cert ./client.pem, client certificate for mutual TLS")
+	flag.StringVar(&tlsKey, "tls-key", "", "--tls-key ./client-key.pem")
+	flag.StringVar(&tlsServerName, "tls-server-name", "localhost", "--tls-server-name chat.example.com")
+	flag.BoolVar(&useE2EE, "e2ee", false, "--e2ee, enables end-to-end encrypted direct messages")
+	flag.StringVar(&keysFile, "keys-file", "", "--keys-file ./testuser.keys.json, <user>.keys.json by default")
 }
 
 func main() {
+	flag.Parse()
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	ctx, cancelFunc := context.WithCancel(context.Background())
 
-	conn, err := grpc.Dial(fmt.Sprintf(":%s", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	shutdownTracing, err := tracing.New(ctx, traceCfg, "client")
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	log.Println("grpc conn established")
+	go func() {
+		<-sigChan
+		log.Println("start graceful shutdown, caught sig")
+		cancelFunc()
+		shutdownTracing(context.Background())
+		os.Exit(0)
+	}()
 
-	chatClient := chatApi.NewChatClient(conn)
+	opts := []chatclient.Option{
+		chatclient.WithDialOptions(
+			grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+			grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+		),
+	}
 
-	go func() {
-		err = receive(ctx, chatClient)
+	if useTLS {
+		tlsConfig, err := certs.ClientConfig(tlsCA, tlsCert, tlsKey, tlsServerName)
 		if err != nil {
 			log.Fatalln(err)
 		}
-	}()
 
-	md := metadata.New(map[string]string{"authorization": user})
-	ctx = metadata.NewOutgoingContext(ctx, md)
+		opts = append(opts, chatclient.WithTLS(tlsConfig))
+	}
+
+	if useE2EE {
+		if keysFile == "" {
+			keysFile = user + ".keys.json"
+		}
+
+		keyring, err := loadKeyring(keysFile)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		opts = append(opts, chatclient.WithKeyring(keyring, keysFile))
+	}
+
+	client, err := chatclient.Dial(fmt.Sprintf(":%s", port), user, opts...)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer client.Close()
+
+	log.Println("grpc conn established")
+
+	if useE2EE {
+		if err = client.UploadPrekeys(ctx, prekeysAmount); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	go func() {
+		if err := client.Listen(ctx, printEvent); err != nil {
+			log.Printf("message stream is closed: %v", err)
+		}
+	}()
 
 	menu := promptui.Select{
 		Label: "choose an action",
-		Items: []string{"Create chat group", "Join chat group", "Join chat group", "Leave chat group", "Get list of channels", "Send Message"},
+		Items: []string{"Create chat group", "Join chat group", "Leave chat group", "Get list of channels", "Send Message",
+			"Block user", "Unblock user", "Get list of blocked users", "Upload attachment", "Download attachment", "Send encrypted message"},
 	}
 
 	for {
-		idx, _, err := menu.Run()
+		idx, _, _ := menu.Run()
 
 		switch idx {
 		case 0:
-			reader := bufio.NewReader(os.Stdin)
-			fmt.Print("enter chat group name: ")
-			chatName, _ := reader.ReadString('\n')
+			chatName := readLine("enter chat group name: ")
 
-			_, err := chatClient.CreateGroupChat(ctx, &chatApi.GroupChannelNameRequest{GroupChannelName: chatName})
-			if err != nil {
+			if err := client.Create(ctx, chatName); err != nil {
 				log.Println(err)
 			}
 		case 1:
-			reader := bufio.NewReader(os.Stdin)
-			fmt.Print("enter chat group name: ")
-			chatName, _ := reader.ReadString('\n')
+			chatName := readLine("enter chat group name: ")
 
-			_, err := chatClient.JoinGroupChat(ctx, &chatApi.GroupChannelNameRequest{GroupChannelName: chatName})
-			if err != nil {
+			if err := client.Join(ctx, chatName); err != nil {
 				log.Println(err)
 			}
 		case 2:
-			reader := bufio.NewReader(os.Stdin)
-			fmt.Print("enter chat group name: ")
-			chatName, _ := reader.ReadString('\n')
+			chatName := readLine("enter chat group name: ")
 
-			_, err := chatClient.LeaveGroupChat(ctx, &chatApi.GroupChannelNameRequest{GroupChannelName: chatName})
-			if err != nil {
+			if err := client.Leave(ctx, chatName); err != nil {
 				log.Println(err)
 			}
 		case 3:
-			_, err = chatClient.ListChannels(ctx, &emptypb.Empty{})
+			channels, err := client.List(ctx)
 			if err != nil {
 				log.Println(err)
+				continue
+			}
+
+			for _, channel := range channels {
+				log.Printf("%s (%s)", channel.GetGroupChannelName(), channel.GetType())
 			}
 		case 4:
-			reader := bufio.NewReader(os.Stdin)
-			fmt.Print("enter destination name, to user or group chat (1 or 2) and message. Each 3 must be separated ',': ")
-			input, _ := reader.ReadString('\n')
+			input := readLine("enter destination name, to user or group chat (1 or 2) and message. Each 3 must be separated ',': ")
+
+			el := strings.SplitN(input, ",", 3)
+			if len(el) != 3 {
+				log.Println("wrong input")
+				continue
+			}
+
+			if err := client.Send(ctx, destination(el[0], el[1]), el[2]); err != nil {
+				log.Println(err)
+			}
+		case 5:
+			userName := readLine("enter user name: ")
+
+			if err := client.Block(ctx, userName); err != nil {
+				log.Println(err)
+			}
+		case 6:
+			userName := readLine("enter user name: ")
+
+			if err := client.Unblock(ctx, userName); err != nil {
+				log.Println(err)
+			}
+		case 7:
+			blocked, err := client.Blocked(ctx)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			log.Printf("blocked users: %v", blocked)
+		case 8:
+			input := readLine("enter destination name, to user or group chat (1 or 2), file path and media type. Each 4 must be separated ',': ")
 
 			el := strings.Split(input, ",")
+			if len(el) != 4 {
+				log.Println("wrong input")
+				continue
+			}
 
-			msg := &chatApi.ChatMessage{Message: el[2]}
-			if el[1] == "1" {
-				msg.Destination = &chatApi.ChatMessage_Username{Username: el[0]}
-			} else {
-				msg.Destination = &chatApi.ChatMessage_GroupChannelName{GroupChannelName: el[0]}
+			id, err := uploadAttachment(ctx, client, destination(el[0], el[1]), el[2], el[3])
+			if err != nil {
+				log.Println(err)
+				continue
 			}
 
-			_, err = chatClient.SendMessage(context.Background(), msg)
+			log.Printf("attachment uploaded, id: %s", id)
+		case 9:
+			id := readLine("enter attachment id: ")
+
+			fileName, err := downloadAttachment(ctx, client, id)
 			if err != nil {
 				log.Println(err)
+				continue
+			}
+
+			log.Printf("attachment saved to %s", fileName)
+		case 10:
+			input := readLine("enter user name and message separated ',': ")
+
+			el := strings.SplitN(input, ",", 2)
+			if len(el) != 2 {
+				log.Println("wrong input")
+				continue
+			}
+
+			if err := client.SendEncrypted(ctx, el[0], el[1]); err != nil {
+				log.Println(err)
 			}
 		}
 	}
+}
 
-	<-sigChan
-	log.Println("start graceful shutdown, caught sig")
-	cancelFunc()
+// printEvent logs a message stream event
+func printEvent(e chatclient.Event) {
+	switch e.Type {
+	case chatclient.EventConnected:
+		log.Println("message stream is open")
+	case chatclient.EventReconnecting:
+		log.Printf("message stream is lost: %v, reconnecting in %s", e.Err, e.Delay.Round(time.Millisecond))
+	case chatclient.EventAnnouncement:
+		log.Printf("announcement: %s", e.Message.GetMessage())
+	case chatclient.EventActivitySummary:
+		log.Printf("while you were away: %s", e.Message.GetMessage())
+	case chatclient.EventBacklog:
+		log.Printf("missed message in %s: %s", e.Message.GetGroupChannelName(), formatContent(e.Message))
+	case chatclient.EventMessage:
+		printMessage(e)
+	}
 }
 
-func receive(ctx context.Context, client chatApi.ChatClient) error {
-	stream, err := client.Connect(context.Background(), &chatApi.ConnectRequest{
-		Username: user,
-	})
+func printMessage(e chatclient.Event) {
+	if e.IdentityChanged {
+		log.Printf("WARNING: identity key of %s has changed", e.Message.GetUsername())
+	}
+
+	if e.Err != nil {
+		log.Println(e.Err)
+	}
+
+	if e.Encrypted {
+		log.Printf("got encrypted message from %s: %s", e.Message.GetUsername(), formatContent(e.Message))
+		return
+	}
+
+	if e.Message.GetCiphertext() != nil {
+		return
+	}
+
+	log.Printf("got message %s", formatContent(e.Message))
+	if e.Message.GetAttachmentId() != "" {
+		log.Printf("message has an attachment, id: %s", e.Message.GetAttachmentId())
+	}
+}
+
+// destination is a user when chatType is "1", a group channel otherwise
+func destination(name, chatType string) chatclient.Destination {
+	if chatType == "1" {
+		return chatclient.ToUser(name)
+	}
+
+	return chatclient.ToGroup(name)
+}
+
+// readLine prints prompt and reads user input without surrounding spaces and trailing newline
+func readLine(prompt string) string {
+	fmt.Print(prompt)
+
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+
+	return strings.TrimSpace(line)
+}
+
+func uploadAttachment(ctx context.Context, client *chatclient.Client, to chatclient.Destination, path, mimeType string) (string, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		return err
+		return "", err
 	}
+	defer f.Close()
 
-	done := make(chan struct{})
-	go func(stream chatApi.Chat_ConnectClient) {
-		for {
-			in, err := stream.Recv()
-			if err != nil {
-				if errors.Is(err, io.EOF) {
-					close(done)
-					return
-				}
+	return client.Upload(ctx, to, filepath.Base(path), mimeType, f)
+}
 
-				log.Fatalf("failed to recieve a message: %v", err)
-			}
+// downloadAttachment saves attachment content into the working directory under its file name
+func downloadAttachment(ctx context.Context, client *chatclient.Client, id string) (string, error) {
+	tmp, err := os.CreateTemp(".", ".download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
 
-			log.Printf("got message %s", in.Message)
-		}
-	}(stream)
+	info, err := client.Download(ctx, id, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	fileName := filepath.Base(info.GetFileName())
+
+	return fileName, os.Rename(tmp.Name(), fileName)
+}
+
+func formatContent(msg *chatApi.ChatMessage) string {
+	switch v := msg.Content.(type) {
+	case *chatApi.ChatMessage_Markdown:
+		return v.Markdown.GetText()
+	case *chatApi.ChatMessage_Code:
+		return fmt.Sprintf("```%s\n%s\n```", v.Code.GetLanguage(), v.Code.GetCode())
+	case *chatApi.ChatMessage_LinkPreview:
+		return fmt.Sprintf("%s (%s)", v.LinkPreview.GetUrl(), v.LinkPreview.GetTitle())
+	}
 
-	<-done
-	return nil
+	return msg.GetMessage()
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/envelope"
+)
+
+var (
+	configPath string
+	newKeyFile string
+)
+
+func init() {
+	flag.StringVar(&configPath, "config", "config.yaml", "--config ./file_name.yaml")
+	flag.StringVar(&newKeyFile, "new-key-file", "", "file with base64 encoded master key data keys are encrypted by")
+}
+
+// rotatekeys re-encrypts data keys of the config by a new master key, data encrypted by them is not rewritten.
+// Server must be stopped meanwhile and started with the new master key afterwards
+func main() {
+	flag.Parse()
+
+	if newKeyFile == "" {
+		log.Fatalln("new key file is required")
+	}
+
+	cfg, err := config.New(configPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if cfg.Encryption.KeysFile == "" {
+		log.Fatalln("keys file is not configured")
+	}
+
+	oldKey, err := envelope.LoadMasterKey(cfg.Encryption)
+	if err != nil {
+		log.Fatalln("failed to load current master key:", err)
+	}
+
+	newKey, err := envelope.ReadKeyFile(newKeyFile)
+	if err != nil {
+		log.Fatalln("failed to load new master key:", err)
+	}
+
+	if err = envelope.Rotate(cfg.Encryption.KeysFile, oldKey, newKey); err != nil {
+		log.Fatalln("failed to rotate master key:", err)
+	}
+
+	log.Println("data keys are encrypted by the new master key")
+}
@@ -0,0 +1,141 @@
+package announcement
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/fsutil"
+)
+
+// channel is a channel announcements are encrypted for
+const channel = "announcements"
+
+// file keeps announcements as a json array, the whole file is replaced on every change. The array is
+// encrypted when sealer is set
+type file struct {
+	mu     sync.Mutex
+	path   string
+	sealer fsutil.ISealer
+}
+
+// NewFile creates announcements storage of path, nil sealer keeps the file in plaintext
+func NewFile(path string, sealer fsutil.ISealer) (*file, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+
+	return &file{path: path, sealer: sealer}, nil
+}
+
+// List provides all stored announcements
+func (f *file) List(ctx context.Context) ([]entity.Announcement, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	res, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return res, ctx.Err()
+}
+
+// Save stores an announcement, an announcement with the same id is replaced
+func (f *file) Save(ctx context.Context, a entity.Announcement) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	items, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	isReplaced := false
+	for i := range items {
+		if items[i].ID == a.ID {
+			items[i] = a
+			isReplaced = true
+		}
+	}
+
+	if !isReplaced {
+		items = append(items, a)
+	}
+
+	if err = f.write(items); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// Delete removes an announcement, missing announcement is not an error
+func (f *file) Delete(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	items, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	res := items[:0]
+	for _, item := range items {
+		if item.ID != id {
+			res = append(res, item)
+		}
+	}
+
+	if err = f.write(res); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func (f *file) read() ([]entity.Announcement, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []entity.Announcement{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// a file written before encryption was enabled is read within plaintext migration window and encrypted on
+	// the next change
+	if f.sealer != nil {
+		if data, err = f.sealer.Open(channel, data); err != nil {
+			return nil, err
+		}
+	}
+
+	res := []entity.Announcement{}
+	if err = json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// write replaces the file via rename, so a crash never leaves it partially written
+func (f *file) write(items []entity.Announcement) error {
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if f.sealer != nil {
+		if data, err = f.sealer.Seal(channel, data); err != nil {
+			return err
+		}
+	}
+
+	return fsutil.WriteAtomic(f.path, data)
+}
@@ -0,0 +1,131 @@
+//go:build unit_tests
+// +build unit_tests
+
+package announcement
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/envelope"
+	"github.com/ITheCorgi/grpc-chat-room/internal/fsutil"
+)
+
+func Test_File(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	stores := []struct {
+		name   string
+		sealer func(t *testing.T) fsutil.ISealer
+	}{
+		{name: "plaintext", sealer: func(*testing.T) fsutil.ISealer { return nil }},
+		{name: "encrypted", sealer: newKeyring},
+	}
+
+	for _, store := range stores {
+		t.Run("test "+store.name+" announcements are saved, replaced and deleted", func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "announcements", "announcements.json")
+			sealer := store.sealer(t)
+
+			f, err := NewFile(path, sealer)
+			if err != nil {
+				t.Fatalf("failed to create store: %v", err)
+			}
+
+			items, err := f.List(ctx)
+			if err != nil || len(items) != 0 {
+				t.Fatalf("expected no announcements in a new store, got %v, %v", items, err)
+			}
+
+			a1 := entity.Announcement{ID: "a1", Message: "maintenance", Author: "admin", StartAt: start, ExpireAt: start.Add(time.Hour)}
+			a2 := entity.Announcement{ID: "a2", Message: "release", Author: "admin", StartAt: start, ExpireAt: start}
+
+			for _, a := range []entity.Announcement{a1, a2} {
+				if err = f.Save(ctx, a); err != nil {
+					t.Fatalf("failed to save announcement: %v", err)
+				}
+			}
+
+			a1.Message = "maintenance is postponed"
+			if err = f.Save(ctx, a1); err != nil {
+				t.Fatalf("failed to replace announcement: %v", err)
+			}
+
+			if err = f.Delete(ctx, a2.ID); err != nil {
+				t.Fatalf("failed to delete announcement: %v", err)
+			}
+
+			if err = f.Delete(ctx, "missing"); err != nil {
+				t.Errorf("expected deletion of missing announcement to succeed, got %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+
+			if isPlaintext := bytes.Contains(data, []byte("postponed")); isPlaintext != (sealer == nil) {
+				t.Errorf("plaintext in file mismatch: exp: %t, act: %t", sealer == nil, isPlaintext)
+			}
+
+			reopened, err := NewFile(path, sealer)
+			if err != nil {
+				t.Fatalf("failed to reopen store: %v", err)
+			}
+
+			items, err = reopened.List(ctx)
+			if err != nil {
+				t.Fatalf("failed to list announcements: %v", err)
+			}
+
+			if len(items) != 1 || items[0] != a1 {
+				t.Errorf("announcements mismatch: exp: [%v], act: %v", a1, items)
+			}
+		})
+	}
+
+	t.Run("test temp files are not left", func(t *testing.T) {
+		dir := t.TempDir()
+
+		f, err := NewFile(filepath.Join(dir, "announcements.json"), nil)
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+
+		if err = f.Save(ctx, entity.Announcement{ID: "a1"}); err != nil {
+			t.Fatalf("failed to save announcement: %v", err)
+		}
+
+		if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+			t.Errorf("expected a single file, got %d", len(entries))
+		}
+	})
+}
+
+func newKeyring(t *testing.T) fsutil.ISealer {
+	t.Helper()
+
+	key := make([]byte, envelope.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	k, err := envelope.New(config.Encryption{
+		MasterKey: base64.StdEncoding.EncodeToString(key),
+		KeysFile:  filepath.Join(t.TempDir(), "keys.json"),
+	})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	return k
+}
@@ -0,0 +1,17 @@
+package app
+
+import (
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+)
+
+// newAdminServer creates a server of ChatAdmin only. Admin role is checked by user name of the call, so the server
+// is expected to listen on an address reachable by operators only or to require client certificates. Health server
+// of the admin server is returned to be shut down along with the one of grpc server
+func newAdminServer(admin chatApi.ChatAdminServer, opts ...grpc.ServerOption) (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(opts...)
+	chatApi.RegisterChatAdminServer(server, admin)
+
+	return server, registerHealth(server, chatApi.ChatAdmin_ServiceDesc.ServiceName)
+}
@@ -0,0 +1,49 @@
+//go:build unit_tests
+// +build unit_tests
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	healthApi "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func Test_NewAdminServer(t *testing.T) {
+	adminServer, adminHealth := newAdminServer(chatApi.UnimplementedChatAdminServer{})
+
+	services := adminServer.GetServiceInfo()
+	if _, ok := services[chatApi.ChatAdmin_ServiceDesc.ServiceName]; !ok {
+		t.Error("admin service is not registered")
+	}
+
+	if _, ok := services[chatApi.Chat_ServiceDesc.ServiceName]; ok {
+		t.Error("chat service must not be served by admin server")
+	}
+
+	conn := serve(t, adminServer)
+
+	res, err := healthApi.NewHealthClient(conn).Check(context.Background(),
+		&healthApi.HealthCheckRequest{Service: chatApi.ChatAdmin_ServiceDesc.ServiceName})
+	if err != nil {
+		t.Fatalf("failed to check health: %v", err)
+	}
+
+	if res.GetStatus() != healthApi.HealthCheckResponse_SERVING {
+		t.Errorf("status mismatch: exp: SERVING, act: %s", res.GetStatus())
+	}
+
+	adminHealth.Shutdown()
+
+	res, err = healthApi.NewHealthClient(conn).Check(context.Background(),
+		&healthApi.HealthCheckRequest{Service: chatApi.ChatAdmin_ServiceDesc.ServiceName})
+	if err != nil {
+		t.Fatalf("failed to check health: %v", err)
+	}
+
+	if res.GetStatus() != healthApi.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status mismatch: exp: NOT_SERVING, act: %s", res.GetStatus())
+	}
+}
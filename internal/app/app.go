@@ -2,29 +2,55 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/ITheCorgi/grpc-chat-room/internal/announcement"
+	"github.com/ITheCorgi/grpc-chat-room/internal/audit"
+	"github.com/ITheCorgi/grpc-chat-room/internal/backlog"
+	"github.com/ITheCorgi/grpc-chat-room/internal/blocklist"
+	"github.com/ITheCorgi/grpc-chat-room/internal/broker"
+	"github.com/ITheCorgi/grpc-chat-room/internal/certs"
 	"github.com/ITheCorgi/grpc-chat-room/internal/config"
 	"github.com/ITheCorgi/grpc-chat-room/internal/controller"
+	"github.com/ITheCorgi/grpc-chat-room/internal/gateway"
+	"github.com/ITheCorgi/grpc-chat-room/internal/metrics"
+	"github.com/ITheCorgi/grpc-chat-room/internal/registry"
+	"github.com/ITheCorgi/grpc-chat-room/internal/tracing"
 	"github.com/ITheCorgi/grpc-chat-room/internal/usecase"
+	"github.com/ITheCorgi/grpc-chat-room/internal/usecase/blobstore"
 	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	grpcPrometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/status"
 )
 
+const prodEnvironment = "prod"
+
 func Run(cfg *config.Config, log *zap.Logger) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	_, cancelFunc := context.WithCancel(context.Background())
+	ctx, cancelFunc := context.WithCancel(context.Background())
+
+	shutdownTracing, err := tracing.New(ctx, cfg.Tracing, cfg.App.Name)
+	if err != nil {
+		log.Fatal("error creating tracer provider", zap.Error(err))
+	}
 
 	opts := []recovery.Option{
 		recovery.WithRecoveryHandler(func(p interface{}) (err error) {
@@ -32,26 +58,260 @@ func Run(cfg *config.Config, log *zap.Logger) {
 		}),
 	}
 
-	grpcServer := grpc.NewServer(
-		middleware.WithUnaryServerChain(recovery.UnaryServerInterceptor(opts...)),
-		middleware.WithStreamServerChain(recovery.StreamServerInterceptor(opts...)),
+	rateLimiter := controller.NewRateLimiter(cfg.RateLimit)
+
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		otelgrpc.UnaryServerInterceptor(),
+		grpcPrometheus.UnaryServerInterceptor,
+		recovery.UnaryServerInterceptor(opts...),
+		controller.RequestIDUnaryServerInterceptor(),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		otelgrpc.StreamServerInterceptor(),
+		grpcPrometheus.StreamServerInterceptor,
+		recovery.StreamServerInterceptor(opts...),
+		controller.RequestIDStreamServerInterceptor(),
+	}
+
+	var (
+		serverOpts []grpc.ServerOption
+		tlsConfig  *tls.Config
+	)
+
+	if cfg.App.TLS.CertFile != "" {
+		if cfg.App.TLS.UsernameFromCert && cfg.App.TLS.ClientCAFile == "" {
+			log.Fatal("username from certificate requires client CA")
+		}
+
+		certReloader, err := certs.NewReloader(cfg.App.TLS, log)
+		if err != nil {
+			log.Fatal("error loading certificates", zap.Error(err))
+		}
+
+		go func() {
+			if err := certReloader.Watch(ctx); err != nil {
+				log.Error("failed to watch certificates", zap.Error(err))
+			}
+		}()
+
+		tlsConfig = certReloader.Config()
+		serverOpts = append(serverOpts, grpc.Creds(certs.NewServerCredentials(tlsConfig)))
+
+		// user name is taken from certificate before rate limiter counts calls of the user
+		if cfg.App.TLS.UsernameFromCert {
+			unaryInterceptors = append(unaryInterceptors, controller.CertUserUnaryServerInterceptor())
+			streamInterceptors = append(streamInterceptors, controller.CertUserStreamServerInterceptor())
+		}
+	}
+
+	// admin server shares credentials and interceptors of grpc server except rate limiter, options are copied, so
+	// options appended to grpc server later never leak into admin ones
+	adminOpts := append(append([]grpc.ServerOption{}, serverOpts...),
+		middleware.WithUnaryServerChain(unaryInterceptors...),
+		middleware.WithStreamServerChain(streamInterceptors...),
 	)
 
+	unaryInterceptors = append(unaryInterceptors, rateLimiter.UnaryServerInterceptor())
+	streamInterceptors = append(streamInterceptors, rateLimiter.StreamServerInterceptor())
+
+	grpcServer := grpc.NewServer(append(serverOpts,
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    cfg.App.Keepalive.Time,
+			Timeout: cfg.App.Keepalive.Timeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.App.Keepalive.MinTime,
+			PermitWithoutStream: cfg.App.Keepalive.PermitWithoutStream,
+		}),
+		middleware.WithUnaryServerChain(unaryInterceptors...),
+		middleware.WithStreamServerChain(streamInterceptors...),
+	)...)
+
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.App.Port))
 	if err != nil {
 		log.Fatal("error creating tcp listener", zap.Error(err))
 	}
 
-	chat := controller.New(usecase.New(log))
+	keys, err := newSealer(cfg.Encryption)
+	if err != nil {
+		log.Fatal("error loading encryption keys", zap.Error(err))
+	}
+
+	var blobs usecase.IBlobStore
+	if cfg.Chat.Attachments.Dir != "" {
+		blobs, err = blobstore.NewLocal(cfg.Chat.Attachments.Dir, keys)
+		if err != nil {
+			log.Fatal("error creating attachments storage", zap.Error(err))
+		}
+	}
+
+	var auditLog usecase.IAuditLog
+	if cfg.Audit.File != "" {
+		auditFile, err := audit.NewFile(cfg.Audit, keys)
+		if err != nil {
+			log.Fatal("error creating audit log", zap.Error(err))
+		}
+		defer auditFile.Close()
+
+		auditLog = auditFile
+	}
+
+	var announcements usecase.IAnnouncementStore
+	if cfg.Chat.Announcements.File != "" {
+		announcements, err = announcement.NewFile(cfg.Chat.Announcements.File, keys)
+		if err != nil {
+			log.Fatal("error creating announcements storage", zap.Error(err))
+		}
+	}
+
+	var blocklists usecase.IBlocklistStore
+	if cfg.Chat.Blocklists.File != "" {
+		blocklists, err = blocklist.NewFile(cfg.Chat.Blocklists.File, keys)
+		if err != nil {
+			log.Fatal("error creating blocklists storage", zap.Error(err))
+		}
+	}
+
+	var missed usecase.IBacklogStore
+	if cfg.Chat.Backlog.Limit > 0 {
+		// backlog is kept in memory of the replica which has sent the messages, users of other replicas would
+		// never get it
+		if cfg.Registry.Driver == registry.DriverRaft {
+			log.Fatal("backlog is supported by a single replica only, disable it with raft registry")
+		}
+
+		missed = backlog.NewMemory(cfg.Chat.Backlog.Limit)
+	}
+
+	if cfg.Registry.NodeID == "" {
+		cfg.Registry.NodeID = cfg.App.Name
+	}
+
+	// replicas of raft registry trust each other by certificates of a dedicated CA
+	var registryCerts registry.ICertificates
+	if cfg.Registry.TLS.CertFile != "" {
+		registryReloader, err := certs.NewReloader(config.TLS{
+			CertFile:     cfg.Registry.TLS.CertFile,
+			KeyFile:      cfg.Registry.TLS.KeyFile,
+			ClientCAFile: cfg.Registry.TLS.CAFile,
+		}, log)
+		if err != nil {
+			log.Fatal("error loading registry certificates", zap.Error(err))
+		}
+
+		go func() {
+			if err := registryReloader.Watch(ctx); err != nil {
+				log.Error("failed to watch registry certificates", zap.Error(err))
+			}
+		}()
+
+		registryCerts = registryReloader
+	}
+
+	chatRegistry, err := registry.New(cfg.Registry, registryCerts)
+	if err != nil {
+		log.Fatal("error creating registry", zap.Error(err))
+	}
+
+	chatBroker, err := broker.New(cfg.Broker, cfg.App.Name)
+	if err != nil {
+		log.Fatal("error creating message broker", zap.Error(err))
+	}
+
+	chatUsecase, err := usecase.New(cfg.Chat, chatRegistry, chatBroker, blobs,
+		metrics.NewChat(prometheus.DefaultRegisterer), auditLog, announcements, blocklists, missed, log)
+	if err != nil {
+		log.Fatal("error creating chat usecase", zap.Error(err))
+	}
+
+	prometheus.MustRegister(metrics.NewQueueDepth(chatUsecase.QueueDepths))
+
+	go chatUsecase.ReapStaleSessions(ctx)
+
+	chat := controller.New(chatUsecase, cfg.Chat.Attachments.ChunkSize)
 
 	chatApi.RegisterChatServer(grpcServer, chat)
+
+	healthServer := registerHealth(grpcServer, chatApi.Chat_ServiceDesc.ServiceName)
+	registerReflection(grpcServer, cfg.App)
+
+	grpcPrometheus.EnableHandlingTimeHistogram()
+	grpcPrometheus.Register(grpcServer)
 	go grpcServer.Serve(listener)
 
+	running := servers{grpc: grpcServer}
+	healthServers := []*health.Server{healthServer}
+
+	if cfg.App.AdminAddr != "" {
+		adminListener, err := net.Listen("tcp", cfg.App.AdminAddr)
+		if err != nil {
+			log.Fatal("error creating admin listener", zap.Error(err))
+		}
+
+		var adminHealth *health.Server
+		running.admin, adminHealth = newAdminServer(controller.NewAdmin(chatUsecase, cfg.App.Admins), adminOpts...)
+		healthServers = append(healthServers, adminHealth)
+		grpcPrometheus.Register(running.admin)
+		go running.admin.Serve(adminListener)
+
+		log.Info("admin service started", zap.String("addr", cfg.App.AdminAddr))
+	}
+
+	if cfg.App.HTTP.Port != "" {
+		// rest and websocket gateways call grpc server through in-process connections, so their requests pass
+		// the same interceptors
+		pipe := gateway.NewPipeListener()
+		go grpcServer.Serve(pipe)
+
+		gatewayConn, err := grpc.DialContext(ctx, "in-process",
+			grpc.WithContextDialer(pipe.Dial),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Fatal("error connecting rest gateway", zap.Error(err))
+		}
+		defer gatewayConn.Close()
+
+		rest, err := gateway.New(ctx, gatewayConn, cfg.App.TLS.UsernameFromCert)
+		if err != nil {
+			log.Fatal("error creating rest gateway", zap.Error(err))
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle("/ws", controller.NewWebSocket(gatewayConn, cfg.App.HTTP.AllowedOrigins, cfg.App.TLS.UsernameFromCert, log))
+		mux.Handle("/", rest)
+
+		// grpc-web requests are served by grpc server itself, the rest are routed by mux
+		web := controller.NewGrpcWeb(grpcServer, cfg.App.HTTP.AllowedOrigins, cfg.App.HTTP.AllowedHeaders, mux)
+
+		running.gateway = runHTTPServer(cfg.App.HTTP.Port, web, tlsConfig, log)
+	}
+
+	if cfg.App.MetricsPort != "" {
+		running.metrics = runMetricsServer(cfg.App.MetricsPort, log)
+	}
+
 	log.Info("http service started", zap.String("host", cfg.App.Host), zap.String("port", cfg.App.Port))
 
 	sig := <-sigChan
 	log.Info("start graceful shutdown, caught sig", zap.String("signal", sig.String()))
 
-	grpcServer.GracefulStop()
+	for _, h := range healthServers {
+		h.Shutdown()
+	}
+
+	shutdown(ctx, cfg.App.ShutdownTimeout, running, chatUsecase, log)
+
+	if err = chatBroker.Close(); err != nil {
+		log.Error("failed to close message broker", zap.Error(err))
+	}
+
+	if err = chatRegistry.Close(); err != nil {
+		log.Error("failed to close registry", zap.Error(err))
+	}
+
+	if err = shutdownTracing(ctx); err != nil {
+		log.Error("failed to flush traces", zap.Error(err))
+	}
+
 	cancelFunc()
 }
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/envelope"
+	"github.com/ITheCorgi/grpc-chat-room/internal/fsutil"
+)
+
+// newSealer loads data keys of the config, nil sealer keeps stored data in plaintext when master key is not set
+func newSealer(cfg config.Encryption) (fsutil.IStreamSealer, error) {
+	if cfg.MasterKey == "" && cfg.MasterKeyFile == "" {
+		return nil, nil
+	}
+
+	keyring, err := envelope.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return keyring, nil
+}
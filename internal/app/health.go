@@ -0,0 +1,31 @@
+package app
+
+import (
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthApi "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// registerHealth reports services of the server as serving until the health server is shut down
+func registerHealth(grpcServer *grpc.Server, services ...string) *health.Server {
+	healthServer := health.NewServer()
+	for _, service := range services {
+		healthServer.SetServingStatus(service, healthApi.HealthCheckResponse_SERVING)
+	}
+	healthApi.RegisterHealthServer(grpcServer, healthServer)
+
+	return healthServer
+}
+
+// registerReflection lets tools list services, it's never enabled in prod environment
+func registerReflection(grpcServer *grpc.Server, cfg config.App) bool {
+	if !cfg.Reflection || cfg.Environment == prodEnvironment {
+		return false
+	}
+
+	reflection.Register(grpcServer)
+
+	return true
+}
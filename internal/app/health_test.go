@@ -0,0 +1,100 @@
+//go:build unit_tests
+// +build unit_tests
+
+package app
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthApi "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func Test_RegisterHealth(t *testing.T) {
+	grpcServer := grpc.NewServer()
+	healthServer := registerHealth(grpcServer, chatApi.Chat_ServiceDesc.ServiceName, chatApi.ChatAdmin_ServiceDesc.ServiceName)
+
+	conn := serve(t, grpcServer)
+	client := healthApi.NewHealthClient(conn)
+
+	check := func(service string) healthApi.HealthCheckResponse_ServingStatus {
+		res, err := client.Check(context.Background(), &healthApi.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("failed to check %q health: %v", service, err)
+		}
+
+		return res.GetStatus()
+	}
+
+	services := []string{"", chatApi.Chat_ServiceDesc.ServiceName, chatApi.ChatAdmin_ServiceDesc.ServiceName}
+
+	for _, service := range services {
+		if act := check(service); act != healthApi.HealthCheckResponse_SERVING {
+			t.Errorf("status of %q mismatch: exp: SERVING, act: %s", service, act)
+		}
+	}
+
+	// shutdown starts with health server, so load balancers stop routing new calls first
+	healthServer.Shutdown()
+
+	for _, service := range services {
+		if act := check(service); act != healthApi.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("status of %q mismatch after shutdown: exp: NOT_SERVING, act: %s", service, act)
+		}
+	}
+}
+
+func Test_RegisterReflection(t *testing.T) {
+	tcs := []struct {
+		name string
+		cfg  config.App
+		exp  bool
+	}{
+		{name: "test enabled in dev", cfg: config.App{Reflection: true, Environment: "dev"}, exp: true},
+		{name: "test disabled", cfg: config.App{Environment: "dev"}},
+		{name: "test never enabled in prod", cfg: config.App{Reflection: true, Environment: prodEnvironment}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			grpcServer := grpc.NewServer()
+
+			if act := registerReflection(grpcServer, tc.cfg); act != tc.exp {
+				t.Errorf("registration mismatch: exp: %t, act: %t", tc.exp, act)
+			}
+
+			_, isRegistered := grpcServer.GetServiceInfo()["grpc.reflection.v1alpha.ServerReflection"]
+			if isRegistered != tc.exp {
+				t.Errorf("reflection service registration mismatch: exp: %t, act: %t", tc.exp, isRegistered)
+			}
+		})
+	}
+}
+
+// serve starts grpc server on in-memory listener, returns connection to it
+func serve(t *testing.T, grpcServer *grpc.Server) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
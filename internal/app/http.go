@@ -0,0 +1,39 @@
+package app
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// runHTTPServer serves browser clients, websocket connections are hijacked, so shutdown doesn't wait for them.
+// Nil tlsConfig serves plain http
+func runHTTPServer(port string, handler http.Handler, tlsConfig *tls.Config, log *zap.Logger) *http.Server {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%s", port),
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			// certificates are served by tlsConfig
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("http server stopped", zap.Error(err))
+		}
+	}()
+
+	log.Info("http gateway started", zap.String("port", port))
+
+	return server
+}
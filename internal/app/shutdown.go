@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+const defaultShutdownTimeout = 10 * time.Second
+
+type (
+	// drainer ends open message streams, it's implemented by chat usecase
+	drainer interface {
+		Drain(ctx context.Context) error
+	}
+
+	// servers are stopped on shutdown, http servers are optional
+	servers struct {
+		grpc *grpc.Server
+		// admin serves operators, it's stopped along with grpc server
+		admin *grpc.Server
+		// gateway serves rest, websocket and grpc-web clients through grpc server
+		gateway *http.Server
+		metrics *http.Server
+	}
+)
+
+// shutdown drains open streams and stops servers gracefully within timeout, servers still running by then are
+// stopped forcibly. Gateway is stopped before grpc server, since its requests are served by grpc server, metrics
+// are served until the end
+func shutdown(ctx context.Context, timeout time.Duration, s servers, chat drainer, log *zap.Logger) {
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := chat.Drain(drainCtx); err != nil {
+		log.Warn("failed to drain message streams", zap.Error(err))
+	}
+
+	stopHTTPServer(drainCtx, s.gateway, "http gateway", log)
+
+	stopGRPCServer(drainCtx, s.grpc, "grpc server", log)
+	stopGRPCServer(drainCtx, s.admin, "admin server", log)
+
+	stopHTTPServer(drainCtx, s.metrics, "metrics server", log)
+}
+
+// stopGRPCServer waits for active calls until ctx is done, then closes connections left
+func stopGRPCServer(ctx context.Context, server *grpc.Server, name string, log *zap.Logger) {
+	if server == nil {
+		return
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		log.Info(name + " stopped gracefully")
+	case <-ctx.Done():
+		log.Warn("shutdown timeout exceeded, stopping " + name + " forcibly")
+		server.Stop()
+	}
+}
+
+// stopHTTPServer waits for active requests until ctx is done, then closes connections left
+func stopHTTPServer(ctx context.Context, server *http.Server, name string, log *zap.Logger) {
+	if server == nil {
+		return
+	}
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Warn("shutdown timeout exceeded, closing "+name+" forcibly", zap.Error(err))
+		server.Close()
+		return
+	}
+
+	log.Info(name + " stopped gracefully")
+}
@@ -0,0 +1,119 @@
+//go:build unit_tests
+// +build unit_tests
+
+package app
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	healthApi "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// fakeDrainer counts drains, ctx of a drain must not be done yet
+type fakeDrainer struct {
+	drains atomic.Int32
+}
+
+func (d *fakeDrainer) Drain(ctx context.Context) error {
+	d.drains.Add(1)
+
+	return ctx.Err()
+}
+
+func Test_Shutdown(t *testing.T) {
+	t.Run("test in-flight http requests are served before servers stop", func(t *testing.T) {
+		grpcServer := grpc.NewServer()
+		registerHealth(grpcServer, chatApi.Chat_ServiceDesc.ServiceName)
+		serve(t, grpcServer)
+
+		gateway, gatewayAddr := startHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		metrics, metricsAddr := startHTTPServer(t, http.NotFoundHandler())
+
+		res := make(chan error, 1)
+		go func() {
+			resp, err := http.Get("http://" + gatewayAddr)
+			if err == nil {
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					err = errors.New(resp.Status)
+				}
+			}
+
+			res <- err
+		}()
+
+		// request reaches the handler before shutdown starts
+		time.Sleep(20 * time.Millisecond)
+
+		chat := &fakeDrainer{}
+		shutdown(context.Background(), time.Second, servers{grpc: grpcServer, gateway: gateway, metrics: metrics}, chat, zap.NewNop())
+
+		if err := <-res; err != nil {
+			t.Errorf("in-flight request failed: %v", err)
+		}
+
+		if chat.drains.Load() != 1 {
+			t.Errorf("drains mismatch: exp: 1, act: %d", chat.drains.Load())
+		}
+
+		for _, addr := range []string{gatewayAddr, metricsAddr} {
+			if _, err := http.Get("http://" + addr); err == nil {
+				t.Errorf("server of %s is still running", addr)
+			}
+		}
+	})
+
+	t.Run("test grpc server is stopped forcibly after timeout", func(t *testing.T) {
+		grpcServer := grpc.NewServer()
+		registerHealth(grpcServer, chatApi.Chat_ServiceDesc.ServiceName)
+		conn := serve(t, grpcServer)
+
+		// watch stream stays open until server stops it
+		stream, err := healthApi.NewHealthClient(conn).Watch(context.Background(), &healthApi.HealthCheckRequest{})
+		if err != nil {
+			t.Fatalf("failed to watch health: %v", err)
+		}
+
+		if _, err = stream.Recv(); err != nil {
+			t.Fatalf("failed to receive health: %v", err)
+		}
+
+		start := time.Now()
+		shutdown(context.Background(), 100*time.Millisecond, servers{grpc: grpcServer}, &fakeDrainer{}, zap.NewNop())
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("shutdown took %s, exp: about 100ms", elapsed)
+		}
+
+		if _, err = stream.Recv(); err == nil {
+			t.Error("expected stream to be closed by forced stop")
+		}
+	})
+}
+
+func startHTTPServer(t *testing.T, handler http.Handler) (*http.Server, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: readHeaderTimeout}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return server, listener.Addr().String()
+}
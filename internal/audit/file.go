@@ -0,0 +1,230 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/fsutil"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// channel is a channel audit records are encrypted for
+const channel = "audit"
+
+// snapshot is a file opened to query, it's read up to size
+type snapshot struct {
+	*os.File
+	size int64
+}
+
+// file writes audit records as append-only json lines, files are rotated by size. When sealer is set, every
+// line is an encrypted record encoded in base64
+type file struct {
+	mu     sync.Mutex
+	path   string
+	writer *lumberjack.Logger
+	sealer fsutil.ISealer
+}
+
+// NewFile creates audit log of the config, nil sealer keeps records in plaintext
+func NewFile(cfg config.Audit, sealer fsutil.ISealer) (*file, error) {
+	if err := os.MkdirAll(filepath.Dir(cfg.File), 0o750); err != nil {
+		return nil, err
+	}
+
+	return &file{
+		path: cfg.File,
+		writer: &lumberjack.Logger{
+			Filename:   cfg.File,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+		},
+		sealer: sealer,
+	}, nil
+}
+
+// Record appends a record to the current file
+func (f *file) Record(ctx context.Context, rec entity.AuditRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	if f.sealer != nil {
+		sealed, err := f.sealer.Seal(channel, line)
+		if err != nil {
+			return err
+		}
+
+		line = []byte(base64.StdEncoding.EncodeToString(sealed))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err = f.writer.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// Query reads current and rotated files, returns records matching filter ordered by time. Files are opened under
+// the writer lock and read without it, so records are written meanwhile
+func (f *file) Query(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error) {
+	files, err := f.open()
+	if err != nil {
+		return nil, err
+	}
+	defer closeAll(files)
+
+	res := []entity.AuditRecord{}
+	for _, file := range files {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		records, err := f.readFile(file, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, records...)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Time.Before(res[j].Time)
+	})
+
+	if filter.Limit > 0 && len(res) > filter.Limit {
+		res = res[len(res)-filter.Limit:]
+	}
+
+	return res, nil
+}
+
+func (f *file) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.writer.Close()
+}
+
+// files lists rotated backups named by lumberjack as <name>-<timestamp><ext> and the current file
+func (f *file) files() ([]string, error) {
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(f.path, ext) + "-"
+
+	backups, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(backups)
+
+	return append(backups, f.path), nil
+}
+
+// open opens files to query, the current file is read up to its size at the moment, so a record written after
+// that is never read partially. Opened files stay readable when they are rotated or removed meanwhile
+func (f *file) open() (res []snapshot, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	defer func() {
+		if err != nil {
+			closeAll(res)
+		}
+	}()
+
+	paths, err := f.files()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		r, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return res, err
+		}
+
+		res = append(res, snapshot{File: r})
+
+		info, err := r.Stat()
+		if err != nil {
+			return res, err
+		}
+
+		res[len(res)-1].size = info.Size()
+	}
+
+	return res, nil
+}
+
+func closeAll(files []snapshot) {
+	for _, file := range files {
+		file.Close()
+	}
+}
+
+func (f *file) readFile(file snapshot, filter entity.AuditFilter) ([]entity.AuditRecord, error) {
+	path := file.Name()
+
+	var res []entity.AuditRecord
+
+	scanner := bufio.NewScanner(io.LimitReader(file, file.size))
+	for n := 1; scanner.Scan(); n++ {
+		line, err := f.decode(scanner.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", path, n, err)
+		}
+
+		var rec entity.AuditRecord
+		if err = json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", path, n, err)
+		}
+
+		if filter.Match(rec) {
+			res = append(res, rec)
+		}
+	}
+
+	return res, scanner.Err()
+}
+
+// decode decrypts an encrypted line. Json lines written before encryption was enabled are opened by sealer as
+// they are, so sealer decides whether plaintext is still allowed
+func (f *file) decode(line []byte) ([]byte, error) {
+	if f.sealer == nil {
+		return line, nil
+	}
+
+	if bytes.HasPrefix(line, []byte("{")) {
+		return f.sealer.Open(channel, line)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(string(line))
+	if err != nil {
+		return nil, err
+	}
+
+	return f.sealer.Open(channel, sealed)
+}
@@ -0,0 +1,184 @@
+//go:build unit_tests
+// +build unit_tests
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/envelope"
+)
+
+func Test_Query(t *testing.T) {
+	t.Run("test query records by actor and time range", func(t *testing.T) {
+		f, err := NewFile(config.Audit{File: filepath.Join(t.TempDir(), "audit.jsonl")}, nil)
+		if err != nil {
+			t.Fatalf("failed to create audit file: %v", err)
+		}
+		defer f.Close()
+
+		start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		records := []entity.AuditRecord{
+			{Time: start, Actor: "user1", Action: entity.AuditGroupCreate, Target: "group1"},
+			{Time: start.Add(time.Hour), Actor: "user2", Action: entity.AuditGroupJoin, Target: "group1"},
+			{Time: start.Add(2 * time.Hour), Actor: "user1", Action: entity.AuditGroupLeave, Target: "group1"},
+		}
+
+		for _, rec := range records {
+			if err = f.Record(context.Background(), rec); err != nil {
+				t.Fatalf("failed to record: %v", err)
+			}
+		}
+
+		act, err := f.Query(context.Background(), entity.AuditFilter{Actor: "user1"})
+		if err != nil {
+			t.Fatalf("failed to query: %v", err)
+		}
+		if len(act) != 2 {
+			t.Errorf("records amount mismatch: exp: 2, act: %d", len(act))
+		}
+
+		act, err = f.Query(context.Background(), entity.AuditFilter{From: start.Add(time.Minute), To: start.Add(time.Hour)})
+		if err != nil {
+			t.Fatalf("failed to query: %v", err)
+		}
+		if len(act) != 1 || act[0].Actor != "user2" {
+			t.Errorf("got wrong records: %v", act)
+		}
+	})
+	t.Run("test encrypted records are queried along with plaintext ones written before", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := config.Audit{File: filepath.Join(dir, "audit.jsonl")}
+		start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		plain, err := NewFile(cfg, nil)
+		if err != nil {
+			t.Fatalf("failed to create audit file: %v", err)
+		}
+
+		if err = plain.Record(context.Background(), entity.AuditRecord{Time: start, Actor: "user1", Target: "group1"}); err != nil {
+			t.Fatalf("failed to record: %v", err)
+		}
+		plain.Close()
+
+		encryption := config.Encryption{
+			MasterKey:           base64.StdEncoding.EncodeToString(make([]byte, envelope.KeySize)),
+			KeysFile:            filepath.Join(dir, "keys.json"),
+			AllowPlaintextUntil: time.Now().Add(time.Hour),
+		}
+
+		keys, err := envelope.New(encryption)
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		f, err := NewFile(cfg, keys)
+		if err != nil {
+			t.Fatalf("failed to create audit file: %v", err)
+		}
+		defer f.Close()
+
+		if err = f.Record(context.Background(), entity.AuditRecord{Time: start.Add(time.Hour), Actor: "user1", Target: "secret"}); err != nil {
+			t.Fatalf("failed to record: %v", err)
+		}
+
+		data, err := os.ReadFile(cfg.File)
+		if err != nil {
+			t.Fatalf("failed to read audit file: %v", err)
+		}
+		if bytes.Contains(data, []byte("secret")) {
+			t.Error("audit file contains plaintext of encrypted record")
+		}
+
+		act, err := f.Query(context.Background(), entity.AuditFilter{Actor: "user1"})
+		if err != nil {
+			t.Fatalf("failed to query: %v", err)
+		}
+		if len(act) != 2 || act[1].Target != "secret" {
+			t.Errorf("got wrong records: %v", act)
+		}
+
+		encryption.AllowPlaintextUntil = time.Now().Add(-time.Hour)
+		if keys, err = envelope.New(encryption); err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		expired, err := NewFile(cfg, keys)
+		if err != nil {
+			t.Fatalf("failed to create audit file: %v", err)
+		}
+		defer expired.Close()
+
+		if _, err = expired.Query(context.Background(), entity.AuditFilter{}); !errors.Is(err, envelope.ErrPlaintext) {
+			t.Errorf("error mismatch: exp: %v, act: %v", envelope.ErrPlaintext, err)
+		}
+	})
+	t.Run("test records are written while files are queried", func(t *testing.T) {
+		f, err := NewFile(config.Audit{File: filepath.Join(t.TempDir(), "audit.jsonl")}, nil)
+		if err != nil {
+			t.Fatalf("failed to create audit file: %v", err)
+		}
+		defer f.Close()
+
+		for i := 0; i < 1000; i++ {
+			if err = f.Record(context.Background(), entity.AuditRecord{Time: time.Now(), Actor: "user1"}); err != nil {
+				t.Fatalf("failed to record: %v", err)
+			}
+		}
+
+		done := make(chan error)
+		go func() {
+			for i := 0; i < 100; i++ {
+				if err := f.Record(context.Background(), entity.AuditRecord{Time: time.Now(), Actor: "user2"}); err != nil {
+					done <- err
+					return
+				}
+			}
+			close(done)
+		}()
+
+		for i := 0; i < 10; i++ {
+			act, err := f.Query(context.Background(), entity.AuditFilter{Actor: "user1"})
+			if err != nil {
+				t.Fatalf("failed to query: %v", err)
+			}
+			if len(act) != 1000 {
+				t.Fatalf("records amount mismatch: exp: 1000, act: %d", len(act))
+			}
+		}
+
+		if err = <-done; err != nil {
+			t.Fatalf("failed to record: %v", err)
+		}
+	})
+	t.Run("test query fails on a broken record", func(t *testing.T) {
+		cfg := config.Audit{File: filepath.Join(t.TempDir(), "audit.jsonl")}
+
+		f, err := NewFile(cfg, nil)
+		if err != nil {
+			t.Fatalf("failed to create audit file: %v", err)
+		}
+		defer f.Close()
+
+		if err = f.Record(context.Background(), entity.AuditRecord{Time: time.Now(), Actor: "user1"}); err != nil {
+			t.Fatalf("failed to record: %v", err)
+		}
+
+		if _, err = f.writer.Write([]byte("{\"time\":\n")); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+
+		if _, err = f.Query(context.Background(), entity.AuditFilter{}); err == nil {
+			t.Error("expected a broken record to fail the query")
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package backlog
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/shard"
+)
+
+type (
+	// memory keeps group messages missed by offline members in memory of the replica which has sent them
+	memory struct {
+		// limit is a max amount of messages kept for every member of a group
+		limit int
+		// users keeps backlog of each user (map[user_name]map[group_name]group)
+		users *shard.Map[map[string]*group]
+	}
+
+	group struct {
+		unread int
+		lastAt time.Time
+		// items keep up to twice the limit, so old messages are not shifted out on every append
+		items []item
+	}
+
+	item struct {
+		at  time.Time
+		msg entity.Message
+	}
+)
+
+func NewMemory(limit int) *memory {
+	return &memory{
+		limit: limit,
+		users: shard.New[map[string]*group](),
+	}
+}
+
+// Append keeps a group message missed by the user, message destination is the group
+func (m *memory) Append(ctx context.Context, user string, msg entity.Message) error {
+	m.users.With(user, entity.SafeWrite, func(users map[string]map[string]*group) error {
+		// time is read under lock and keeps monotonic clock reading, so items stay ordered across groups
+		now := time.Now()
+
+		groups, ok := users[user]
+		if !ok {
+			groups = make(map[string]*group)
+			users[user] = groups
+		}
+
+		g, ok := groups[msg.To]
+		if !ok {
+			g = new(group)
+			groups[msg.To] = g
+		}
+
+		g.unread++
+		g.lastAt = now.UTC()
+		g.items = append(g.items, item{at: now, msg: msg})
+
+		if len(g.items) >= 2*m.limit {
+			g.items = append(g.items[:0], g.items[len(g.items)-m.limit:]...)
+		}
+
+		return nil
+	})
+
+	return ctx.Err()
+}
+
+// Take removes the user backlog, returns activity of groups ordered by name along with the latest missed
+// messages within limit ordered by time
+func (m *memory) Take(ctx context.Context, user string) ([]entity.GroupActivity, []entity.Message, error) {
+	var groups map[string]*group
+
+	m.users.With(user, entity.SafeWrite, func(users map[string]map[string]*group) error {
+		groups = users[user]
+		delete(users, user)
+
+		return nil
+	})
+
+	activity := make([]entity.GroupActivity, 0, len(groups))
+	items := make([]item, 0)
+
+	for name, g := range groups {
+		activity = append(activity, entity.GroupActivity{Group: name, Unread: g.unread, LastAt: g.lastAt})
+		items = append(items, g.items...)
+	}
+
+	sort.Slice(activity, func(i, j int) bool { return activity[i].Group < activity[j].Group })
+	sort.SliceStable(items, func(i, j int) bool { return items[i].at.Before(items[j].at) })
+
+	if len(items) > m.limit {
+		items = items[len(items)-m.limit:]
+	}
+
+	messages := make([]entity.Message, len(items))
+	for i := range items {
+		messages[i] = items[i].msg
+	}
+
+	return activity, messages, ctx.Err()
+}
+
+// Restore puts back a backlog taken by a session which has failed to start, restored messages precede the ones
+// missed since the backlog was taken
+func (m *memory) Restore(ctx context.Context, user string, activity []entity.GroupActivity, msgs []entity.Message) error {
+	// restored messages are older than any message kept now, zero time keeps them first while Take orders by time
+	restored := make(map[string][]item, len(activity))
+	for i, msg := range msgs {
+		restored[msg.To] = append(restored[msg.To], item{at: time.Time{}.Add(time.Duration(i)), msg: msg})
+	}
+
+	m.users.With(user, entity.SafeWrite, func(users map[string]map[string]*group) error {
+		groups, ok := users[user]
+		if !ok {
+			groups = make(map[string]*group, len(activity))
+			users[user] = groups
+		}
+
+		for _, a := range activity {
+			g, ok := groups[a.Group]
+			if !ok {
+				g = new(group)
+				groups[a.Group] = g
+			}
+
+			g.unread += a.Unread
+			if a.LastAt.After(g.lastAt) {
+				g.lastAt = a.LastAt
+			}
+
+			g.items = append(restored[a.Group], g.items...)
+			if len(g.items) >= 2*m.limit {
+				g.items = append(g.items[:0], g.items[len(g.items)-m.limit:]...)
+			}
+		}
+
+		return nil
+	})
+
+	return ctx.Err()
+}
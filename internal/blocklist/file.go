@@ -0,0 +1,109 @@
+package blocklist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/fsutil"
+)
+
+// channel is a channel blocklists are encrypted for
+const channel = "blocklists"
+
+// file keeps blocklists as a json object of users and users they blocked, the whole file is replaced on every
+// change. The object is encrypted when sealer is set
+type file struct {
+	mu     sync.Mutex
+	path   string
+	sealer fsutil.ISealer
+}
+
+// NewFile creates blocklists storage of path, nil sealer keeps the file in plaintext
+func NewFile(path string, sealer fsutil.ISealer) (*file, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+
+	return &file{path: path, sealer: sealer}, nil
+}
+
+// List provides users blocked by each user
+func (f *file) List(ctx context.Context) (map[string][]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	res, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return res, ctx.Err()
+}
+
+// Save replaces users blocked by the user, empty blocked removes the user
+func (f *file) Save(ctx context.Context, user string, blocked []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	items, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	if len(blocked) == 0 {
+		delete(items, user)
+	} else {
+		items[user] = blocked
+	}
+
+	if err = f.write(items); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func (f *file) read() (map[string][]string, error) {
+	res := make(map[string][]string)
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return res, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if f.sealer != nil {
+		if data, err = f.sealer.Open(channel, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// write replaces the file via rename, so a crash never leaves it partially written
+func (f *file) write(items map[string][]string) error {
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if f.sealer != nil {
+		if data, err = f.sealer.Seal(channel, data); err != nil {
+			return err
+		}
+	}
+
+	return fsutil.WriteAtomic(f.path, data)
+}
@@ -0,0 +1,34 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+)
+
+const (
+	DriverLocal = "local"
+	DriverNATS  = "nats"
+)
+
+// Broker routes chat messages to subscribers of a topic
+type Broker interface {
+	Publish(ctx context.Context, topic string, msg entity.Message) error
+	Subscribe(topic string, handler func(entity.Message)) (func(), error)
+	Close() error
+}
+
+// New creates a broker of the configured driver, local broker is used by default
+func New(cfg config.Broker, name string) (Broker, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocal(), nil
+
+	case DriverNATS:
+		return NewNATS(cfg, name)
+	}
+
+	return nil, fmt.Errorf("unknown broker driver %q", cfg.Driver)
+}
@@ -0,0 +1,109 @@
+//go:build unit_tests
+// +build unit_tests
+
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+func Test_PublishSubscribe(t *testing.T) {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+
+	go ns.Start()
+	defer ns.Shutdown()
+
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server is not ready")
+	}
+
+	cfg := config.Broker{Driver: DriverNATS, URL: ns.ClientURL(), SubjectPrefix: "test", Timeout: time.Second}
+
+	tests := []struct {
+		name string
+		// publisher and subscriber are different replicas, unless broker is local
+		newBrokers func(t *testing.T) (publisher, subscriber Broker)
+	}{
+		{
+			name: "test local broker",
+			newBrokers: func(t *testing.T) (Broker, Broker) {
+				b := NewLocal()
+				return b, b
+			},
+		},
+		{
+			name: "test nats broker",
+			newBrokers: func(t *testing.T) (Broker, Broker) {
+				publisher, err := NewNATS(cfg, "publisher")
+				if err != nil {
+					t.Fatalf("failed to connect publisher: %v", err)
+				}
+
+				subscriber, err := NewNATS(cfg, "subscriber")
+				if err != nil {
+					t.Fatalf("failed to connect subscriber: %v", err)
+				}
+
+				return publisher, subscriber
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher, subscriber := tt.newBrokers(t)
+			defer publisher.Close()
+			defer subscriber.Close()
+
+			ctx := context.Background()
+			// topic contains nats wildcard and separator characters which must not be interpreted
+			topic := "user.a*b>c"
+
+			if err := publisher.Publish(ctx, topic, entity.Message{Message: "lost"}); !errors.Is(err, entity.ErrNotFound) {
+				t.Fatalf("expected not found error without subscribers, got %v", err)
+			}
+
+			received := make(chan entity.Message, 1)
+			unsubscribe, err := subscriber.Subscribe(topic, func(msg entity.Message) { received <- msg })
+			if err != nil {
+				t.Fatalf("failed to subscribe: %v", err)
+			}
+
+			if _, err = subscriber.Subscribe("user.other", func(entity.Message) {
+				t.Error("message is delivered to a wrong topic")
+			}); err != nil {
+				t.Fatalf("failed to subscribe: %v", err)
+			}
+
+			exp := entity.Message{To: "sender", Message: "hello", ChatType: entity.OneToOne, ContentType: entity.PlainText}
+			if err = publisher.Publish(ctx, topic, exp); err != nil {
+				t.Fatalf("failed to publish: %v", err)
+			}
+
+			select {
+			case act := <-received:
+				if act.To != exp.To || act.Message != exp.Message || act.ChatType != exp.ChatType {
+					t.Errorf("expected %+v, got %+v", exp, act)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("message is not received")
+			}
+
+			unsubscribe()
+
+			if err = publisher.Publish(ctx, topic, exp); !errors.Is(err, entity.ErrNotFound) {
+				t.Errorf("expected not found error after unsubscribe, got %v", err)
+			}
+		})
+	}
+}
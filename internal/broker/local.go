@@ -0,0 +1,73 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+)
+
+var errNoSubscribers = fmt.Errorf("%w: topic has no subscribers", entity.ErrNotFound)
+
+// local routes messages between subscribers of a single replica, handlers are called synchronously
+type local struct {
+	mu     sync.RWMutex
+	nextID uint64
+	// subscribers is a list of handlers by topic (map[topic]map[subscription_id]handler)
+	subscribers map[string]map[uint64]func(entity.Message)
+}
+
+func NewLocal() *local {
+	return &local{
+		subscribers: make(map[string]map[uint64]func(entity.Message)),
+	}
+}
+
+// Publish calls handlers subscribed to the topic
+func (l *local) Publish(ctx context.Context, topic string, msg entity.Message) error {
+	l.mu.RLock()
+	handlers := make([]func(entity.Message), 0, len(l.subscribers[topic]))
+	for _, handler := range l.subscribers[topic] {
+		handlers = append(handlers, handler)
+	}
+	l.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		return errNoSubscribers
+	}
+
+	for _, handler := range handlers {
+		handler(msg)
+	}
+
+	return ctx.Err()
+}
+
+// Subscribe registers handler for messages published to the topic
+func (l *local) Subscribe(topic string, handler func(entity.Message)) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextID++
+	id := l.nextID
+
+	if _, ok := l.subscribers[topic]; !ok {
+		l.subscribers[topic] = make(map[uint64]func(entity.Message))
+	}
+	l.subscribers[topic][id] = handler
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		delete(l.subscribers[topic], id)
+		if len(l.subscribers[topic]) == 0 {
+			delete(l.subscribers, topic)
+		}
+	}, nil
+}
+
+func (l *local) Close() error {
+	return nil
+}
@@ -0,0 +1,104 @@
+package broker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/nats-io/nats.go"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// natsBroker routes messages between replicas through a nats server. Every delivery is a request, a subscriber
+// replies once the message is queued, so publisher knows whether the topic has subscribers at all
+type natsBroker struct {
+	conn    *nats.Conn
+	prefix  string
+	timeout time.Duration
+}
+
+func NewNATS(cfg config.Broker, name string) (*natsBroker, error) {
+	conn, err := nats.Connect(cfg.URL, nats.Name(name), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &natsBroker{
+		conn:    conn,
+		prefix:  cfg.SubjectPrefix,
+		timeout: timeout,
+	}, nil
+}
+
+// Publish delivers a message to subscribers of the topic on any replica
+func (b *natsBroker) Publish(ctx context.Context, topic string, msg entity.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	_, err = b.conn.RequestWithContext(ctx, b.subject(topic), data)
+	if errors.Is(err, nats.ErrNoResponders) {
+		return errNoSubscribers
+	}
+
+	return err
+}
+
+// Subscribe registers handler for messages published to the topic
+func (b *natsBroker) Subscribe(topic string, handler func(entity.Message)) (func(), error) {
+	sub, err := b.conn.Subscribe(b.subject(topic), func(m *nats.Msg) {
+		var msg entity.Message
+		// malformed message is not acknowledged, publisher gets a timeout error
+		if err := json.Unmarshal(m.Data, &msg); err != nil {
+			return
+		}
+
+		handler(msg)
+		m.Respond(nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the round trips make sure the server knows about subscription changes, otherwise messages published
+	// right after them may find no responders or wait for a subscriber which is gone
+	if err = b.conn.FlushTimeout(b.timeout); err != nil {
+		sub.Unsubscribe()
+		return nil, err
+	}
+
+	return func() {
+		if sub.Unsubscribe() == nil {
+			b.conn.FlushTimeout(b.timeout)
+		}
+	}, nil
+}
+
+// Close waits for pending messages to be handled, then closes the connection
+func (b *natsBroker) Close() error {
+	return b.conn.Drain()
+}
+
+// subject encodes a topic, so user and group names never clash with nats wildcards and token separators
+func (b *natsBroker) subject(topic string) string {
+	subject := base64.RawURLEncoding.EncodeToString([]byte(topic))
+	if b.prefix == "" {
+		return subject
+	}
+
+	return b.prefix + "." + subject
+}
@@ -0,0 +1,52 @@
+package certs
+
+import (
+	"crypto/tls"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+)
+
+type (
+	// InProcessInfo is auth info of in-process connections, they are trusted to pass user name in metadata
+	InProcessInfo struct {
+		credentials.CommonAuthInfo
+	}
+
+	// serverCredentials secures network connections with TLS, while in-process pipe connections are passed as is
+	serverCredentials struct {
+		credentials.TransportCredentials
+	}
+)
+
+func (InProcessInfo) AuthType() string {
+	return "in-process"
+}
+
+func NewServerCredentials(cfg *tls.Config) credentials.TransportCredentials {
+	return serverCredentials{TransportCredentials: credentials.NewTLS(cfg)}
+}
+
+func (c serverCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	// pipe connections can't come from network, they are created by net.Pipe in the same process only
+	if conn.LocalAddr().Network() == "pipe" {
+		return conn, InProcessInfo{CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}}, nil
+	}
+
+	return c.TransportCredentials.ServerHandshake(conn)
+}
+
+func (c serverCredentials) Clone() credentials.TransportCredentials {
+	return serverCredentials{TransportCredentials: c.TransportCredentials.Clone()}
+}
+
+// Username provides common name of verified client certificate subject
+func Username(state *tls.ConnectionState) (string, bool) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+
+	name := state.VerifiedChains[0][0].Subject.CommonName
+
+	return name, name != ""
+}
@@ -0,0 +1,218 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// dataDir is a symlink kubernetes swaps to update mounted secret files at once
+const dataDir = "..data"
+
+// reloader keeps server TLS config, certificates are loaded again once their files change, so they are rotated
+// without restart. Handshakes started before a reload keep the config they have started with
+type reloader struct {
+	cfg     config.TLS
+	current atomic.Pointer[tls.Config]
+	log     *zap.Logger
+}
+
+func NewReloader(cfg config.TLS, log *zap.Logger) (*reloader, error) {
+	r := &reloader{
+		cfg: cfg,
+		log: log,
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Config provides TLS config serving the latest loaded certificates. Client certificates are required and
+// verified when client CA is set
+func (r *reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+		// http server requires a certificate source in the config it starts with
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.current.Load().Certificates[0], nil
+		},
+	}
+}
+
+// PeerConfig provides TLS config of a client presenting the latest loaded certificate, server certificate is
+// verified by the latest loaded client CA. It's used by replicas sharing a CA to connect to each other
+func (r *reloader) PeerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return &r.current.Load().Certificates[0], nil
+		},
+		// roots of a config can't be replaced, so server certificate is verified by VerifyConnection instead
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			return verifyServer(state, r.current.Load().ClientCAs)
+		},
+	}
+}
+
+// Watch reloads certificates once their files change until ctx is done, a failed reload keeps certificates
+// loaded before. Directories of the files are watched, so files replaced by rename are noticed too
+func (r *reloader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	files := make(map[string]struct{})
+	for _, file := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+
+		files[filepath.Clean(file)] = struct{}{}
+		files[filepath.Join(filepath.Dir(file), dataDir)] = struct{}{}
+
+		if err = watcher.Add(filepath.Dir(file)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if _, ok = files[filepath.Clean(event.Name)]; !ok || event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			// certificate and key are written one by one, a reload between them fails and the next one succeeds
+			if err = r.reload(); err != nil {
+				r.log.Error("failed to reload certificates", zap.Error(err))
+				continue
+			}
+
+			r.log.Info("certificates are reloaded", zap.String("file", event.Name))
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			r.log.Error("failed to watch certificates", zap.Error(err))
+		}
+	}
+}
+
+func (r *reloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+
+	if r.cfg.ClientCAFile != "" {
+		if cfg.ClientCAs, err = loadCertPool(r.cfg.ClientCAFile); err != nil {
+			return fmt.Errorf("load client CA: %w", err)
+		}
+
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	r.current.Store(cfg)
+
+	return nil
+}
+
+// ClientConfig creates TLS config of a client verifying server by CA of caFile, or by system CA when it is empty.
+// Certificate of certFile and keyFile is presented to server when they are set
+func ClientConfig(caFile, certFile, keyFile, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+
+	if caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("load CA: %w", err)
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load certificate: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// verifyServer verifies server certificate of a connection by roots, as a client with RootCAs set does
+func verifyServer(state tls.ConnectionState, roots *x509.CertPool) error {
+	if roots == nil {
+		return errors.New("client CA is not configured")
+	}
+
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("server certificate is required")
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       state.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(opts)
+
+	return err
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("no certificates found in " + file)
+	}
+
+	return pool, nil
+}
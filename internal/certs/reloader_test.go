@@ -0,0 +1,138 @@
+//go:build unit_tests
+// +build unit_tests
+
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"go.uber.org/zap"
+)
+
+func Test_Reloader(t *testing.T) {
+	t.Run("test certificate is reloaded once its files change", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := config.TLS{
+			CertFile: filepath.Join(dir, "server.pem"),
+			KeyFile:  filepath.Join(dir, "server-key.pem"),
+		}
+
+		writeCert(t, cfg, 1)
+
+		r, err := NewReloader(cfg, zap.NewNop())
+		if err != nil {
+			t.Fatalf("failed to create reloader: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go r.Watch(ctx)
+
+		if act := servedSerial(t, r); act != 1 {
+			t.Fatalf("serial mismatch: exp: 1, act: %d", act)
+		}
+
+		// watcher is started asynchronously, files are rewritten until the change is noticed
+		deadline := time.Now().Add(5 * time.Second)
+		for servedSerial(t, r) != 2 {
+			if time.Now().After(deadline) {
+				t.Fatal("certificate is not reloaded")
+			}
+
+			writeCert(t, cfg, 2)
+			time.Sleep(50 * time.Millisecond)
+		}
+	})
+
+	t.Run("test broken files keep certificate loaded before", func(t *testing.T) {
+		dir := t.TempDir()
+		cfg := config.TLS{
+			CertFile: filepath.Join(dir, "server.pem"),
+			KeyFile:  filepath.Join(dir, "server-key.pem"),
+		}
+
+		writeCert(t, cfg, 1)
+
+		r, err := NewReloader(cfg, zap.NewNop())
+		if err != nil {
+			t.Fatalf("failed to create reloader: %v", err)
+		}
+
+		if err = os.WriteFile(cfg.CertFile, []byte("broken"), 0o600); err != nil {
+			t.Fatalf("failed to write certificate: %v", err)
+		}
+
+		if err = r.reload(); err == nil {
+			t.Error("expected reload error")
+		}
+
+		if act := servedSerial(t, r); act != 1 {
+			t.Errorf("serial mismatch: exp: 1, act: %d", act)
+		}
+	})
+}
+
+func servedSerial(t *testing.T, r *reloader) int64 {
+	t.Helper()
+
+	cfg, err := r.Config().GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return cert.SerialNumber.Int64()
+}
+
+func writeCert(t *testing.T, cfg config.TLS, serial int64) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	// key is written first, so a reload noticing the certificate finds the matching key
+	if err = os.WriteFile(cfg.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	if err = os.WriteFile(cfg.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+}
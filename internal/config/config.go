@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"gopkg.in/yaml.v3"
@@ -11,7 +12,14 @@ import (
 
 type (
 	Config struct {
-		App App `yaml:"app"`
+		App        App        `yaml:"app"`
+		Chat       Chat       `yaml:"chat"`
+		RateLimit  RateLimit  `yaml:"rate_limit"`
+		Tracing    Tracing    `yaml:"tracing"`
+		Audit      Audit      `yaml:"audit"`
+		Broker     Broker     `yaml:"broker"`
+		Registry   Registry   `yaml:"registry"`
+		Encryption Encryption `yaml:"encryption"`
 	}
 
 	App struct {
@@ -19,6 +27,220 @@ type (
 		Host        string `yaml:"host" env:"HOST"`
 		Port        string `yaml:"port" env:"PORT"`
 		Environment string `yaml:"environment" env:"ENVIRONMENT"`
+		// MetricsPort is a port of http /metrics endpoint, empty disables it
+		MetricsPort string `yaml:"metrics_port" env:"METRICS_PORT"`
+		// AdminAddr is a host:port of grpc listener serving ChatAdmin service, e.g. 127.0.0.1:8271. Admin service
+		// is never served on the public port nor through http gateway, empty disables it
+		AdminAddr string `yaml:"admin_addr" env:"ADMIN_ADDR"`
+		// Reflection registers grpc server reflection service, it's never enabled in prod environment
+		Reflection bool `yaml:"reflection" env:"REFLECTION"`
+		// ShutdownTimeout is a time given to open streams to drain before server is stopped forcibly
+		ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+		// Admins is a list of user names granted admin role
+		Admins    []string  `yaml:"admins" env:"ADMINS" env-separator:","`
+		Keepalive Keepalive `yaml:"keepalive"`
+		HTTP      HTTP      `yaml:"http"`
+		TLS       TLS       `yaml:"tls"`
+	}
+
+	// TLS secures grpc and http listeners, certificates are reloaded once their files change
+	TLS struct {
+		// CertFile is a path of PEM encoded server certificate, empty disables TLS
+		CertFile string `yaml:"cert_file" env:"TLS_CERT_FILE"`
+		// KeyFile is a path of PEM encoded server key
+		KeyFile string `yaml:"key_file" env:"TLS_KEY_FILE"`
+		// ClientCAFile is a path of PEM encoded CA client certificates are verified by, it makes client
+		// certificates required. Empty disables mutual TLS
+		ClientCAFile string `yaml:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+		// UsernameFromCert takes user name from common name of client certificate subject instead of metadata,
+		// it requires mutual TLS
+		UsernameFromCert bool `yaml:"username_from_cert" env:"TLS_USERNAME_FROM_CERT"`
+	}
+
+	// HTTP is a listener serving browser clients
+	HTTP struct {
+		// Port is a port of http listener, empty disables it
+		Port string `yaml:"port" env:"HTTP_PORT"`
+		// AllowedOrigins is a list of origins browsers may connect from, "*" allows any origin
+		AllowedOrigins []string `yaml:"allowed_origins" env:"HTTP_ALLOWED_ORIGINS" env-separator:","`
+		// AllowedHeaders is a list of headers grpc-web requests may carry from other origins besides grpc-web ones
+		AllowedHeaders []string `yaml:"allowed_headers" env:"HTTP_ALLOWED_HEADERS" env-separator:","`
+	}
+
+	Keepalive struct {
+		// Time is a period of connection inactivity after which server pings the client, 0 keeps grpc default
+		Time time.Duration `yaml:"time" env:"KEEPALIVE_TIME"`
+		// Timeout is a time server waits for a ping ack before closing the connection, 0 keeps grpc default
+		Timeout time.Duration `yaml:"timeout" env:"KEEPALIVE_TIMEOUT"`
+		// MinTime is a min period clients are allowed to ping server with, connections of clients pinging
+		// more often are closed. 0 keeps grpc default
+		MinTime time.Duration `yaml:"min_time" env:"KEEPALIVE_MIN_TIME"`
+		// PermitWithoutStream allows clients to ping server when there are no active streams
+		PermitWithoutStream bool `yaml:"permit_without_stream" env:"KEEPALIVE_PERMIT_WITHOUT_STREAM"`
+	}
+
+	Chat struct {
+		// RejectBlocked makes direct messages to a user who blocked the sender fail, otherwise they are dropped silently
+		RejectBlocked bool `yaml:"reject_blocked" env:"CHAT_REJECT_BLOCKED"`
+		// DeliveryWorkers is an amount of goroutines delivering group messages, 0 means default
+		DeliveryWorkers int `yaml:"delivery_workers" env:"CHAT_DELIVERY_WORKERS"`
+		// StaleSessionTimeout is a time a stream may stay not writable before its session is closed, 0 disables it
+		StaleSessionTimeout time.Duration `yaml:"stale_session_timeout" env:"CHAT_STALE_SESSION_TIMEOUT"`
+		// QueueSize is a max amount of messages waiting for a connected user, group messages waiting for delivery
+		// to a single member are bounded by it as well. 0 means default
+		QueueSize int `yaml:"queue_size" env:"CHAT_QUEUE_SIZE"`
+		// SlowConsumerPolicy is one of: drop, disconnect. It tells whether a message for a user whose queue is full
+		// is dropped or the user session is closed, so the user reconnects. Messages are dropped by default
+		SlowConsumerPolicy string        `yaml:"slow_consumer_policy" env:"CHAT_SLOW_CONSUMER_POLICY"`
+		Attachments        Attachments   `yaml:"attachments"`
+		Limits             Limits        `yaml:"limits"`
+		Announcements      Announcements `yaml:"announcements"`
+		Blocklists         Blocklists    `yaml:"blocklists"`
+		Backlog            Backlog       `yaml:"backlog"`
+	}
+
+	Backlog struct {
+		// Limit is a max amount of missed group messages delivered on connect, older ones are only counted
+		// in the activity summary. 0 disables backlog. Backlog is kept in memory, so it's lost on restart and
+		// can't be used along with raft registry
+		Limit int `yaml:"limit" env:"BACKLOG_LIMIT"`
+	}
+
+	Announcements struct {
+		// File is a path scheduled and active announcements are kept in, empty keeps them in memory only
+		File string `yaml:"file" env:"ANNOUNCEMENTS_FILE"`
+	}
+
+	Blocklists struct {
+		// File is a path users blocked by each user are kept in, empty keeps them in registry only. Every replica
+		// stores blocklists changed through it, the file seeds registry with users registry doesn't know yet
+		File string `yaml:"file" env:"BLOCKLISTS_FILE"`
+	}
+
+	// Limits are stricter than api limits of proto validation, which are applied first: 65536 characters of
+	// a message, 128 characters of a group channel name and 64 characters of a user name
+	Limits struct {
+		// MaxMessageLength is a max amount of characters in a message along with its link preview, 0 means
+		// api limit
+		MaxMessageLength int `yaml:"max_message_length" env:"LIMITS_MAX_MESSAGE_LENGTH"`
+		// MaxGroupNameLength is a max amount of characters in a group channel name, 0 means api limit
+		MaxGroupNameLength int `yaml:"max_group_name_length" env:"LIMITS_MAX_GROUP_NAME_LENGTH"`
+		// UsernamePattern is a regular expression user names must match, empty means any
+		UsernamePattern string `yaml:"username_pattern" env:"LIMITS_USERNAME_PATTERN"`
+		// GroupNamePattern is a regular expression group channel names must match, empty means any
+		GroupNamePattern string `yaml:"group_name_pattern" env:"LIMITS_GROUP_NAME_PATTERN"`
+	}
+
+	Attachments struct {
+		// Dir is a local directory attachment files are stored in
+		Dir string `yaml:"dir" env:"ATTACHMENTS_DIR"`
+		// MaxSize is a max attachment size in bytes, 10 MiB is used when it's not set
+		MaxSize int64 `yaml:"max_size" env:"ATTACHMENTS_MAX_SIZE"`
+		// ChunkSize is a size of download stream chunks in bytes
+		ChunkSize int `yaml:"chunk_size" env:"ATTACHMENTS_CHUNK_SIZE"`
+		// AllowedMimeTypes is a list of accepted attachment media types
+		AllowedMimeTypes []string `yaml:"allowed_mime_types" env:"ATTACHMENTS_ALLOWED_MIME_TYPES" env-separator:","`
+	}
+
+	RateLimit struct {
+		// Methods maps a full grpc method name (/package.Service/Method) to its limits
+		Methods map[string]MethodLimit `yaml:"methods"`
+	}
+
+	MethodLimit struct {
+		// User limits calls of an authenticated user
+		User Limit `yaml:"user"`
+		// Channel limits calls targeting a group channel or a user
+		Channel Limit `yaml:"channel"`
+	}
+
+	// Limit is a token bucket, zero rate means no limit
+	Limit struct {
+		// Rate is an amount of tokens added per second
+		Rate float64 `yaml:"rate"`
+		// Burst is a bucket size
+		Burst int `yaml:"burst"`
+	}
+
+	Tracing struct {
+		// Exporter is one of: none, stdout, file, otlp
+		Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+		// Endpoint is an otlp grpc collector address
+		Endpoint string `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+		// Insecure disables TLS for otlp collector connection
+		Insecure bool `yaml:"insecure" env:"TRACING_INSECURE"`
+		// File is a path spans are appended to by file exporter
+		File string `yaml:"file" env:"TRACING_FILE"`
+		// SampleRatio is a share of sampled root spans, 0 means all
+		SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+	}
+
+	Audit struct {
+		// File is a path of json lines audit log, empty disables audit
+		File string `yaml:"file" env:"AUDIT_FILE"`
+		// MaxSizeMB is a size the file is rotated at
+		MaxSizeMB int `yaml:"max_size_mb" env:"AUDIT_MAX_SIZE_MB"`
+		// MaxBackups is an amount of rotated files kept, 0 means all
+		MaxBackups int `yaml:"max_backups" env:"AUDIT_MAX_BACKUPS"`
+		// MaxAgeDays is a period rotated files are kept for, 0 means forever
+		MaxAgeDays int `yaml:"max_age_days" env:"AUDIT_MAX_AGE_DAYS"`
+	}
+
+	// Encryption encrypts data written to disk by per-channel data keys, data keys are kept in a file encrypted
+	// by master key
+	Encryption struct {
+		// MasterKey is a base64 encoded 32 byte key, empty MasterKey and MasterKeyFile disable encryption
+		MasterKey string `yaml:"master_key" env:"ENCRYPTION_MASTER_KEY"`
+		// MasterKeyFile is a path of a file with base64 encoded master key, it's used when MasterKey is empty
+		MasterKeyFile string `yaml:"master_key_file" env:"ENCRYPTION_MASTER_KEY_FILE"`
+		// KeysFile is a path data keys are kept in
+		KeysFile string `yaml:"keys_file" env:"ENCRYPTION_KEYS_FILE"`
+		// AllowPlaintextUntil is an RFC 3339 time data written before encryption was enabled is read until, it's
+		// a migration window. Plaintext data fails to read after that, empty value never allows it
+		AllowPlaintextUntil time.Time `yaml:"allow_plaintext_until" env:"ENCRYPTION_ALLOW_PLAINTEXT_UNTIL"`
+	}
+
+	Broker struct {
+		// Driver is one of: local, nats. Local broker routes messages inside a single replica only
+		Driver string `yaml:"driver" env:"BROKER_DRIVER"`
+		// URL is a nats server address
+		URL string `yaml:"url" env:"BROKER_URL"`
+		// SubjectPrefix is prepended to nats subjects, so several deployments can share a nats server
+		SubjectPrefix string `yaml:"subject_prefix" env:"BROKER_SUBJECT_PREFIX"`
+		// Timeout is a time a delivery to another replica waits for acknowledgement
+		Timeout time.Duration `yaml:"timeout" env:"BROKER_TIMEOUT"`
+	}
+
+	Registry struct {
+		// Driver is one of: local, raft. Local registry keeps groups of a single replica only
+		Driver string `yaml:"driver" env:"REGISTRY_DRIVER"`
+		// NodeID is a unique id of the replica, app name is used by default
+		NodeID string `yaml:"node_id" env:"REGISTRY_NODE_ID"`
+		// Peers is a list of all raft cluster replicas including this one
+		Peers []RegistryPeer `yaml:"peers"`
+		// Timeout is a time a registry change waits to be committed
+		Timeout time.Duration `yaml:"timeout" env:"REGISTRY_TIMEOUT"`
+		// TLS secures raft transport and forwarded changes by mutual TLS, raft driver requires it
+		TLS RegistryTLS `yaml:"tls"`
+	}
+
+	RegistryTLS struct {
+		// CertFile is a path of PEM encoded replica certificate, it's presented to other replicas both as server
+		// and client one
+		CertFile string `yaml:"cert_file" env:"REGISTRY_TLS_CERT_FILE"`
+		// KeyFile is a path of PEM encoded replica key
+		KeyFile string `yaml:"key_file" env:"REGISTRY_TLS_KEY_FILE"`
+		// CAFile is a path of PEM encoded CA replica certificates are verified by, it should issue replica
+		// certificates only
+		CAFile string `yaml:"ca_file" env:"REGISTRY_TLS_CA_FILE"`
+	}
+
+	RegistryPeer struct {
+		ID string `yaml:"id"`
+		// RaftAddr is an address of raft transport
+		RaftAddr string `yaml:"raft_addr"`
+		// APIAddr is an http address followers forward registry changes to while the replica is a leader
+		APIAddr string `yaml:"api_addr"`
 	}
 )
 
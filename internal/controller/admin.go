@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type adminController struct {
+	chatApi.UnimplementedChatAdminServer
+	admin IChatAdmin
+	// admins is a set of user names granted admin role
+	admins map[string]struct{}
+}
+
+func NewAdmin(adminService IChatAdmin, admins []string) adminController {
+	return adminController{
+		admin:  adminService,
+		admins: newSet(admins),
+	}
+}
+
+func (c adminController) ListSessions(ctx context.Context, _ *emptypb.Empty) (*chatApi.Sessions, error) {
+	if _, err := getAdminFromMD(ctx, c.admins); err != nil {
+		return nil, err
+	}
+
+	sessions, err := c.admin.ListSessions(ctx)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	items := make([]*chatApi.Sessions_Session, len(sessions))
+	for i := range sessions {
+		items[i] = &chatApi.Sessions_Session{
+			Username:   sessions[i].User,
+			QueueDepth: uint32(sessions[i].QueueDepth),
+			Node:       sessions[i].Node,
+		}
+
+		// connection time of sessions on other replicas is unknown
+		if !sessions[i].ConnectedAt.IsZero() {
+			items[i].ConnectedAt = timestamppb.New(sessions[i].ConnectedAt)
+		}
+	}
+
+	return &chatApi.Sessions{Items: items}, nil
+}
+
+func (c adminController) DisconnectUser(ctx context.Context, req *chatApi.UsernameRequest) (*emptypb.Empty, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	adminName, err := getAdminFromMD(ctx, c.admins)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.admin.DisconnectUser(ctx, req.GetUsername(), adminName); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (c adminController) GetGroup(ctx context.Context, req *chatApi.GroupChannelNameRequest) (*chatApi.Group, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := getAdminFromMD(ctx, c.admins); err != nil {
+		return nil, err
+	}
+
+	group, err := c.admin.GetGroup(ctx, req.GetGroupChannelName())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	members := make([]*chatApi.Group_Member, len(group.Members))
+	for i := range group.Members {
+		members[i] = &chatApi.Group_Member{
+			Username:   group.Members[i].User,
+			Connected:  group.Members[i].Connected,
+			QueueDepth: uint32(group.Members[i].QueueDepth),
+			Node:       group.Members[i].Node,
+		}
+	}
+
+	return &chatApi.Group{GroupChannelName: group.Name, Members: members}, nil
+}
+
+func (c adminController) DeleteGroup(ctx context.Context, req *chatApi.GroupChannelNameRequest) (*emptypb.Empty, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	adminName, err := getAdminFromMD(ctx, c.admins)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.admin.DeleteGroup(ctx, req.GetGroupChannelName(), adminName); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (c adminController) Broadcast(ctx context.Context, req *chatApi.BroadcastRequest) (*chatApi.Announcement, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	adminName, err := getAdminFromMD(ctx, c.admins)
+	if err != nil {
+		return nil, err
+	}
+
+	a := entity.Announcement{
+		Message: req.GetMessage(),
+		Author:  adminName,
+	}
+
+	if req.GetStartAt() != nil {
+		a.StartAt = req.GetStartAt().AsTime()
+	}
+
+	if req.GetExpireAt() != nil {
+		a.ExpireAt = req.GetExpireAt().AsTime()
+	}
+
+	a, err = c.admin.Broadcast(ctx, a)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return convertOutAnnouncement(a), nil
+}
+
+func (c adminController) ListAnnouncements(ctx context.Context, _ *emptypb.Empty) (*chatApi.Announcements, error) {
+	if _, err := getAdminFromMD(ctx, c.admins); err != nil {
+		return nil, err
+	}
+
+	announcements, err := c.admin.ListAnnouncements(ctx)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	items := make([]*chatApi.Announcement, len(announcements))
+	for i := range announcements {
+		items[i] = convertOutAnnouncement(announcements[i])
+	}
+
+	return &chatApi.Announcements{Items: items}, nil
+}
+
+func (c adminController) CancelAnnouncement(ctx context.Context, req *chatApi.AnnouncementRequest) (*emptypb.Empty, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	adminName, err := getAdminFromMD(ctx, c.admins)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.admin.CancelAnnouncement(ctx, req.GetId(), adminName); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func convertOutAnnouncement(a entity.Announcement) *chatApi.Announcement {
+	return &chatApi.Announcement{
+		Id:       a.ID,
+		Message:  a.Message,
+		Author:   a.Author,
+		StartAt:  timestamppb.New(a.StartAt),
+		ExpireAt: timestamppb.New(a.ExpireAt),
+	}
+}
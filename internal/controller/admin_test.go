@@ -0,0 +1,77 @@
+//go:build unit_tests
+// +build unit_tests
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// fakeAdmin serves admin calls reached past the role check, the rest of IChatAdmin panics
+type fakeAdmin struct {
+	IChatAdmin
+	calls int
+}
+
+func (a *fakeAdmin) ListSessions(context.Context) ([]entity.SessionInfo, error) {
+	a.calls++
+
+	return nil, nil
+}
+
+func (a *fakeAdmin) QueryAuditLog(context.Context, entity.AuditFilter) ([]entity.AuditRecord, error) {
+	a.calls++
+
+	return nil, nil
+}
+
+func Test_AdminGuard(t *testing.T) {
+	tcs := []struct {
+		name    string
+		user    string
+		expCode codes.Code
+	}{
+		{name: "test admin", user: "root", expCode: codes.OK},
+		{name: "test not admin", user: "alice", expCode: codes.PermissionDenied},
+		{name: "test unauthenticated", expCode: codes.Unauthenticated},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			admin := &fakeAdmin{}
+			c := NewAdmin(admin, []string{"root"})
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{})
+			if tc.user != "" {
+				ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", tc.user))
+			}
+
+			_, err := c.ListSessions(ctx, &emptypb.Empty{})
+			if act := status.Code(err); act != tc.expCode {
+				t.Errorf("list sessions code mismatch: exp: %s, act: %s", tc.expCode, act)
+			}
+
+			_, err = c.QueryAuditLog(ctx, &chatApi.AuditLogQuery{})
+			if act := status.Code(err); act != tc.expCode {
+				t.Errorf("query audit log code mismatch: exp: %s, act: %s", tc.expCode, act)
+			}
+
+			expCalls := 0
+			if tc.expCode == codes.OK {
+				expCalls = 2
+			}
+
+			if admin.calls != expCalls {
+				t.Errorf("calls mismatch: exp: %d, act: %d", expCalls, admin.calls)
+			}
+		})
+	}
+}
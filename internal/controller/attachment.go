@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"errors"
+	"io"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (c controller) UploadAttachment(stream chatApi.Chat_UploadAttachmentServer) error {
+	userName, err := getAuthorizationFromMD(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	info := first.GetInfo()
+	if info == nil {
+		return status.Error(codes.InvalidArgument, "first chunk must carry attachment info")
+	}
+
+	if err = info.ValidateAll(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	attachment := convertInAttachment(info)
+
+	id, err := c.chat.UploadAttachment(stream.Context(), attachment, userName, &chunkReader{stream: stream})
+	if err != nil {
+		return statusFromError(err)
+	}
+
+	return stream.SendAndClose(&chatApi.Attachment{Id: id})
+}
+
+func (c controller) DownloadAttachment(req *chatApi.AttachmentRequest, stream chatApi.Chat_DownloadAttachmentServer) error {
+	if err := req.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	userName, err := getAuthorizationFromMD(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	attachment, content, err := c.chat.DownloadAttachment(stream.Context(), req.GetId(), userName)
+	if err != nil {
+		return statusFromError(err)
+	}
+	defer content.Close()
+
+	err = stream.Send(&chatApi.AttachmentChunk{
+		Payload: &chatApi.AttachmentChunk_Info{Info: convertOutAttachment(attachment)},
+	})
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, c.chunkSize)
+	for {
+		n, err := content.Read(buf)
+		if n > 0 {
+			sendErr := stream.Send(&chatApi.AttachmentChunk{
+				Payload: &chatApi.AttachmentChunk_Data{Data: buf[:n]},
+			})
+			if sendErr != nil {
+				return sendErr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+}
+
+// chunkReader reads attachment data chunks of upload stream as a continuous byte stream
+type chunkReader struct {
+	stream chatApi.Chat_UploadAttachmentServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+
+		if err = chunk.Validate(); err != nil {
+			return 0, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		data := chunk.GetData()
+		if data == nil {
+			return 0, status.Error(codes.InvalidArgument, "attachment info is sent twice")
+		}
+
+		r.buf = data
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+func convertInAttachment(info *chatApi.AttachmentInfo) entity.Attachment {
+	attachment := entity.Attachment{
+		FileName: info.GetFileName(),
+		MimeType: info.GetMimeType(),
+	}
+
+	switch v := info.Destination.(type) {
+	case *chatApi.AttachmentInfo_GroupChannelName:
+		attachment.To = v.GroupChannelName
+		attachment.ChatType = entity.OneToMany
+
+	case *chatApi.AttachmentInfo_Username:
+		attachment.To = v.Username
+		attachment.ChatType = entity.OneToOne
+	}
+
+	return attachment
+}
+
+func convertOutAttachment(attachment entity.Attachment) *chatApi.AttachmentInfo {
+	info := &chatApi.AttachmentInfo{
+		FileName: attachment.FileName,
+		MimeType: attachment.MimeType,
+		Size:     attachment.Size,
+	}
+
+	switch attachment.ChatType {
+	case entity.OneToMany:
+		info.Destination = &chatApi.AttachmentInfo_GroupChannelName{GroupChannelName: attachment.To}
+	case entity.OneToOne:
+		info.Destination = &chatApi.AttachmentInfo_Username{Username: attachment.To}
+	}
+
+	return info
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (c adminController) QueryAuditLog(ctx context.Context, req *chatApi.AuditLogQuery) (*chatApi.AuditRecords, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := getAdminFromMD(ctx, c.admins); err != nil {
+		return nil, err
+	}
+
+	filter := entity.AuditFilter{
+		Actor: req.GetActor(),
+		Limit: int(req.GetLimit()),
+	}
+
+	if req.GetFrom() != nil {
+		filter.From = req.GetFrom().AsTime()
+	}
+
+	if req.GetTo() != nil {
+		filter.To = req.GetTo().AsTime()
+	}
+
+	records, err := c.admin.QueryAuditLog(ctx, filter)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	items := make([]*chatApi.AuditRecords_Record, len(records))
+	for i := range records {
+		items[i] = &chatApi.AuditRecords_Record{
+			Time:      timestamppb.New(records[i].Time),
+			RequestId: records[i].RequestID,
+			Actor:     records[i].Actor,
+			Action:    records[i].Action,
+			Target:    records[i].Target,
+		}
+	}
+
+	return &chatApi.AuditRecords{Items: items}, nil
+}
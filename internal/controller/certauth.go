@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/certs"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// certUserStream passes the user name of client certificate to the handler, the name replaces user name
+// of Connect request
+type certUserStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	userName string
+}
+
+func (s *certUserStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *certUserStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if req, ok := m.(*chatApi.ConnectRequest); ok {
+		req.Username = s.userName
+	}
+
+	return nil
+}
+
+// CertUserUnaryServerInterceptor replaces user name of metadata with common name of client certificate subject.
+// In-process connections of rest and websocket gateways keep metadata, gateways take user name from certificate
+// themselves
+func CertUserUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		userName, inProcess, err := certUserName(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if inProcess {
+			return handler(ctx, req)
+		}
+
+		return handler(withAuthorization(ctx, userName), req)
+	}
+}
+
+// CertUserStreamServerInterceptor replaces user name of metadata and Connect request with common name
+// of client certificate subject
+func CertUserStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		userName, inProcess, err := certUserName(ss.Context())
+		if err != nil {
+			return err
+		}
+
+		if inProcess {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &certUserStream{
+			ServerStream: ss,
+			ctx:          withAuthorization(ss.Context(), userName),
+			userName:     userName,
+		})
+	}
+}
+
+func certUserName(ctx context.Context) (userName string, inProcess bool, err error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false, status.Error(codes.Unauthenticated, "client certificate is required")
+	}
+
+	switch info := p.AuthInfo.(type) {
+	case certs.InProcessInfo:
+		return "", true, nil
+
+	case credentials.TLSInfo:
+		if userName, ok = certs.Username(&info.State); ok {
+			return userName, false, nil
+		}
+	}
+
+	return "", false, status.Error(codes.Unauthenticated, "client certificate is required")
+}
+
+func withAuthorization(ctx context.Context, userName string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Set("authorization", userName)
+
+	return metadata.NewIncomingContext(ctx, md)
+}
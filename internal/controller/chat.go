@@ -9,6 +9,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func (c controller) Connect(req *chatApi.ConnectRequest, stream chatApi.Chat_ConnectServer) error {
@@ -16,25 +17,50 @@ func (c controller) Connect(req *chatApi.ConnectRequest, stream chatApi.Chat_Con
 		return status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	queue, err := c.chat.Connect(stream.Context(), req.GetUsername())
+	session, err := c.chat.Connect(stream.Context(), req.GetUsername())
 	if err != nil {
+		return statusFromError(err)
+	}
+
+	// stream context is already done once the stream is closed
+	defer c.chat.Disconnect(context.Background(), session)
+
+	// headers tell the client its session is registered before any message is sent
+	if err = stream.SendHeader(metadata.MD{}); err != nil {
 		return err
 	}
 
+	// send marks the session not writable while the client doesn't read, so the session can be reaped
+	send := func(msg entity.Message) error {
+		session.StartWrite()
+		defer session.EndWrite()
+
+		return stream.Send(convertOutMessage(msg))
+	}
+
 	for {
 		select {
 		case <-stream.Context().Done():
 			return nil
 
-		case msg, _ := <-queue:
-			err = stream.Send(convertOutMessage(msg))
+		case <-session.Done():
+			return send(entity.Message{
+				Message:     "session is closed by server",
+				ContentType: entity.PlainText,
+				Kind:        entity.Disconnected,
+			})
+
+		case msg, _ := <-session.Queue:
+			err = send(msg)
 			if err != nil {
 				return err
 			}
+
+			if msg.Kind == entity.ServerGoingAway {
+				return nil
+			}
 		}
 	}
-
-	return nil
 }
 
 func (c controller) CreateGroupChat(ctx context.Context, req *chatApi.GroupChannelNameRequest) (*emptypb.Empty, error) {
@@ -48,7 +74,7 @@ func (c controller) CreateGroupChat(ctx context.Context, req *chatApi.GroupChann
 	}
 
 	if err = c.chat.CreateGroupChat(ctx, req.GetGroupChannelName(), userName); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusFromError(err)
 	}
 
 	return &emptypb.Empty{}, nil
@@ -66,7 +92,7 @@ func (c controller) JoinGroupChat(ctx context.Context, req *chatApi.GroupChannel
 
 	err = c.chat.JoinGroupChat(ctx, req.GetGroupChannelName(), userName)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusFromError(err)
 	}
 
 	return &emptypb.Empty{}, nil
@@ -84,7 +110,7 @@ func (c controller) LeaveGroupChat(ctx context.Context, req *chatApi.GroupChanne
 
 	err = c.chat.LeaveGroupChat(ctx, req.GetGroupChannelName(), userName)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusFromError(err)
 	}
 
 	return &emptypb.Empty{}, nil
@@ -93,7 +119,7 @@ func (c controller) LeaveGroupChat(ctx context.Context, req *chatApi.GroupChanne
 func (c controller) ListChannels(ctx context.Context, _ *emptypb.Empty) (*chatApi.Channels, error) {
 	channels, err := c.chat.ListChannels(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusFromError(err)
 	}
 
 	items := make([]*chatApi.Channels_Channel, len(channels))
@@ -125,12 +151,62 @@ func (c controller) SendMessage(ctx context.Context, req *chatApi.ChatMessage) (
 
 	err = c.chat.SendMessage(ctx, msg, userName)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (c controller) BlockUser(ctx context.Context, req *chatApi.UsernameRequest) (*emptypb.Empty, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	userName, err := getAuthorizationFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chat.BlockUser(ctx, userName, req.GetUsername())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (c controller) UnblockUser(ctx context.Context, req *chatApi.UsernameRequest) (*emptypb.Empty, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	userName, err := getAuthorizationFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chat.UnblockUser(ctx, userName, req.GetUsername())
+	if err != nil {
+		return nil, statusFromError(err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
+func (c controller) ListBlocked(ctx context.Context, _ *emptypb.Empty) (*chatApi.Usernames, error) {
+	userName, err := getAuthorizationFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := c.chat.ListBlocked(ctx, userName)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &chatApi.Usernames{Items: users}, nil
+}
+
 func getAuthorizationFromMD(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -146,10 +222,13 @@ func getAuthorizationFromMD(ctx context.Context) (string, error) {
 }
 
 func convertInMessage(req *chatApi.ChatMessage) (entity.Message, error) {
-	msg := entity.Message{
-		Message: req.GetMessage(),
+	msg, err := convertInContent(req)
+	if err != nil {
+		return entity.Message{}, err
 	}
 
+	msg.AttachmentID = req.GetAttachmentId()
+
 	switch req.Destination.(type) {
 	case *chatApi.ChatMessage_GroupChannelName:
 		v := req.Destination.(*chatApi.ChatMessage_GroupChannelName)
@@ -177,9 +256,106 @@ func convertInMessage(req *chatApi.ChatMessage) (entity.Message, error) {
 	return entity.Message{}, status.Error(codes.Internal, "wrong destination")
 }
 
+func convertInContent(req *chatApi.ChatMessage) (entity.Message, error) {
+	switch v := req.Content.(type) {
+	case *chatApi.ChatMessage_Message:
+		return entity.Message{
+			Message:     v.Message,
+			ContentType: entity.PlainText,
+		}, nil
+
+	case *chatApi.ChatMessage_Markdown:
+		return entity.Message{
+			Message:     v.Markdown.GetText(),
+			ContentType: entity.Markdown,
+		}, nil
+
+	case *chatApi.ChatMessage_Code:
+		return entity.Message{
+			Message:     v.Code.GetCode(),
+			ContentType: entity.Code,
+			Language:    v.Code.GetLanguage(),
+		}, nil
+
+	case *chatApi.ChatMessage_LinkPreview:
+		return entity.Message{
+			Message:     v.LinkPreview.GetUrl(),
+			ContentType: entity.Link,
+			Preview: &entity.LinkPreview{
+				Title:       v.LinkPreview.GetTitle(),
+				Description: v.LinkPreview.GetDescription(),
+				ImageURL:    v.LinkPreview.GetImageUrl(),
+			},
+		}, nil
+
+	case *chatApi.ChatMessage_Ciphertext:
+		return entity.Message{
+			ContentType: entity.Encrypted,
+			Ciphertext: &entity.Ciphertext{
+				SenderIdentityKey: v.Ciphertext.GetSenderIdentityKey(),
+				EphemeralKey:      v.Ciphertext.GetEphemeralKey(),
+				PrekeyID:          v.Ciphertext.GetPrekeyId(),
+				Nonce:             v.Ciphertext.GetNonce(),
+				Payload:           v.Ciphertext.GetPayload(),
+			},
+		}, nil
+	}
+
+	return entity.Message{}, status.Error(codes.Internal, "wrong content")
+}
+
+func setOutContent(msg *chatApi.ChatMessage, req entity.Message) {
+	switch req.ContentType {
+	case entity.Markdown:
+		msg.Content = &chatApi.ChatMessage_Markdown{
+			Markdown: &chatApi.Markdown{Text: req.Message},
+		}
+
+	case entity.Code:
+		msg.Content = &chatApi.ChatMessage_Code{
+			Code: &chatApi.CodeBlock{Language: req.Language, Code: req.Message},
+		}
+
+	case entity.Link:
+		preview := &chatApi.LinkPreview{Url: req.Message}
+		if req.Preview != nil {
+			preview.Title = req.Preview.Title
+			preview.Description = req.Preview.Description
+			preview.ImageUrl = req.Preview.ImageURL
+		}
+
+		msg.Content = &chatApi.ChatMessage_LinkPreview{LinkPreview: preview}
+
+	case entity.Encrypted:
+		ct := &chatApi.Ciphertext{}
+		if req.Ciphertext != nil {
+			ct.SenderIdentityKey = req.Ciphertext.SenderIdentityKey
+			ct.EphemeralKey = req.Ciphertext.EphemeralKey
+			ct.PrekeyId = req.Ciphertext.PrekeyID
+			ct.Nonce = req.Ciphertext.Nonce
+			ct.Payload = req.Ciphertext.Payload
+		}
+
+		msg.Content = &chatApi.ChatMessage_Ciphertext{Ciphertext: ct}
+
+	default:
+		msg.Content = &chatApi.ChatMessage_Message{Message: req.Message}
+	}
+}
+
 func convertOutMessage(req entity.Message) *chatApi.ChatMessage {
 	msg := &chatApi.ChatMessage{
-		Message: req.Message,
+		AttachmentId: req.AttachmentID,
+		Kind:         chatApi.MessageKind(req.Kind),
+	}
+	setOutContent(msg, req)
+
+	for _, a := range req.Activity {
+		msg.Activity = append(msg.Activity, &chatApi.GroupActivity{
+			GroupChannelName: a.Group,
+			Unread:           uint32(a.Unread),
+			LastMessageAt:    timestamppb.New(a.LastAt),
+		})
 	}
 
 	switch req.ChatType {
@@ -198,5 +374,6 @@ func convertOutMessage(req entity.Message) *chatApi.ChatMessage {
 		return msg
 	}
 
-	return nil
+	// server events have no destination
+	return msg
 }
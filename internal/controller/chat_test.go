@@ -0,0 +1,45 @@
+//go:build unit_tests
+// +build unit_tests
+
+package controller
+
+import (
+	"testing"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+)
+
+func Test_MarkdownValidation(t *testing.T) {
+	tcs := []struct {
+		name    string
+		text    string
+		isValid bool
+	}{
+		{name: "test quote", text: "> quoted line\n> another one", isValid: true},
+		{name: "test comparison", text: "a < b and b > c, 1<2, <3", isValid: true},
+		{name: "test code span", text: "run `if a < b && !ok {}` first", isValid: true},
+		{name: "test link", text: "see [docs](https://example.com/a?b=c)!", isValid: true},
+		{name: "test exclamations", text: "wow!! really!!!", isValid: true},
+		{name: "test trailing signs", text: "is it <", isValid: true},
+		{name: "test html tag", text: "hello <b>world</b>"},
+		{name: "test closing html tag", text: "hello </script>"},
+		{name: "test html comment", text: "hello <!-- hidden -->"},
+		{name: "test processing instruction", text: "<?xml version=\"1.0\"?>"},
+		{name: "test image", text: "look ![cat](https://example.com/cat.png)"},
+		{name: "test image after exclamation", text: "look!![cat](https://example.com/cat.png)"},
+		{name: "test tag after signs", text: "!<<b>"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := &chatApi.ChatMessage{
+				Destination: &chatApi.ChatMessage_Username{Username: "user1"},
+				Content:     &chatApi.ChatMessage_Markdown{Markdown: &chatApi.Markdown{Text: tc.text}},
+			}
+
+			if err := msg.Validate(); (err == nil) != tc.isValid {
+				t.Errorf("validation mismatch of %q: exp valid: %t, act error: %v", tc.text, tc.isValid, err)
+			}
+		})
+	}
+}
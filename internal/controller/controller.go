@@ -1,14 +1,52 @@
 package controller
 
 import (
+	"context"
+
 	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+const defaultChunkSize = 64 * 1024
+
 type controller struct {
 	chatApi.UnimplementedChatServer
 	chat IChat
+	// chunkSize is a max size of attachment download chunk
+	chunkSize int
+}
+
+func New(chatService IChat, chunkSize int) controller {
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	return controller{
+		chat:      chatService,
+		chunkSize: chunkSize,
+	}
 }
 
-func New(chatService IChat) controller {
-	return controller{chat: chatService}
+func newSet(items []string) map[string]struct{} {
+	res := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		res[item] = struct{}{}
+	}
+
+	return res
+}
+
+// getAdminFromMD authorizes user and checks the user has admin role
+func getAdminFromMD(ctx context.Context, admins map[string]struct{}) (string, error) {
+	userName, err := getAuthorizationFromMD(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := admins[userName]; !ok {
+		return "", status.Error(codes.PermissionDenied, "admin role is required")
+	}
+
+	return userName, nil
 }
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+)
+
+// grpcWebHeaders are request headers of grpc-web protocol, the wrapper doesn't allow them cross origin itself
+var grpcWebHeaders = []string{"content-type", "x-grpc-web", "x-user-agent", "grpc-timeout"}
+
+// grpcWebController serves grpc-web requests of browser clients by the grpc server, so they pass the same
+// interceptors as grpc ones. The rest of requests are passed to the next handler
+type grpcWebController struct {
+	server *grpcweb.WrappedGrpcServer
+	next   http.Handler
+}
+
+// NewGrpcWeb wraps the grpc server with a grpc-web handler. Cross origin requests are allowed from allowedOrigins
+// only and may carry allowedHeaders besides grpc-web ones
+func NewGrpcWeb(server *grpc.Server, allowedOrigins, allowedHeaders []string, next http.Handler) grpcWebController {
+	return grpcWebController{
+		server: grpcweb.WrapServer(server,
+			grpcweb.WithOriginFunc(originAllowed(allowedOrigins)),
+			grpcweb.WithAllowedRequestHeaders(append(grpcWebHeaders, allowedHeaders...)),
+		),
+		next: next,
+	}
+}
+
+func (c grpcWebController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if c.server.IsGrpcWebRequest(r) || c.server.IsAcceptableGrpcCorsRequest(r) {
+		c.server.ServeHTTP(w, r)
+		return
+	}
+
+	c.next.ServeHTTP(w, r)
+}
+
+// originAllowed checks origin of a cross origin request, "*" allows any origin
+func originAllowed(allowedOrigins []string) func(origin string) bool {
+	origins := newSet(allowedOrigins)
+
+	return func(origin string) bool {
+		if _, ok := origins["*"]; ok {
+			return true
+		}
+
+		_, ok := origins[origin]
+		return ok
+	}
+}
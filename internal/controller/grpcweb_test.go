@@ -0,0 +1,117 @@
+//go:build unit_tests
+// +build unit_tests
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+type unimplementedChat struct {
+	chatApi.UnimplementedChatServer
+}
+
+func Test_GrpcWeb(t *testing.T) {
+	grpcServer := grpc.NewServer()
+	chatApi.RegisterChatServer(grpcServer, unimplementedChat{})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	handler := NewGrpcWeb(grpcServer, []string{"https://chat.example.com"}, []string{"authorization"}, next)
+
+	preflight := func(origin, headers string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/b2bchatapi.Chat/ListChannels", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", headers)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	t.Run("allowed origin", func(t *testing.T) {
+		rec := preflight("https://chat.example.com", "content-type,x-grpc-web,authorization")
+
+		if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "https://chat.example.com" {
+			t.Fatalf("expected origin to be allowed, got %q", origin)
+		}
+
+		allowed := strings.ToLower(rec.Header().Get("Access-Control-Allow-Headers"))
+		for _, header := range []string{"content-type", "x-grpc-web", "authorization"} {
+			if !strings.Contains(allowed, header) {
+				t.Fatalf("expected header %s to be allowed, got %q", header, allowed)
+			}
+		}
+	})
+
+	t.Run("unknown origin", func(t *testing.T) {
+		rec := preflight("https://evil.example.com", "content-type,x-grpc-web")
+
+		if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "" {
+			t.Fatalf("expected origin to be rejected, got %q", origin)
+		}
+	})
+
+	t.Run("header is not allowed", func(t *testing.T) {
+		rec := preflight("https://chat.example.com", "content-type,x-grpc-web,x-secret")
+
+		if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "" {
+			t.Fatalf("expected preflight to be rejected, got origin %q", origin)
+		}
+	})
+
+	t.Run("grpc-web request is served by grpc server", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/b2bchatapi.Chat/ListChannels", strings.NewReader("\x00\x00\x00\x00\x00"))
+		req.Header.Set("Content-Type", "application/grpc-web+proto")
+		req.Header.Set("X-Grpc-Web", "1")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if code := rec.Header().Get("Grpc-Status"); code != "12" {
+			t.Fatalf("expected status %d, got %q", codes.Unimplemented, code)
+		}
+	})
+
+	t.Run("other requests are passed to next handler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/blocked", nil))
+
+		if rec.Code != http.StatusTeapot {
+			t.Fatalf("expected request to reach next handler, got %d", rec.Code)
+		}
+	})
+}
+
+func Test_OriginAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		want    bool
+	}{
+		{name: "listed", origins: []string{"https://a.example.com", "https://b.example.com"}, origin: "https://b.example.com", want: true},
+		{name: "not listed", origins: []string{"https://a.example.com"}, origin: "https://b.example.com"},
+		{name: "any", origins: []string{"*"}, origin: "https://b.example.com", want: true},
+		{name: "none", origin: "https://a.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := originAllowed(tt.origins)(tt.origin); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -2,13 +2,16 @@ package controller
 
 import (
 	"context"
+	"io"
 
 	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
 )
 
 type IChat interface {
 	// Connect establishes connection with server, returns stream of messages
-	Connect(ctx context.Context, userName string) (chan entity.Message, error)
+	Connect(ctx context.Context, userName string) (*entity.Session, error)
+	// Disconnect ends the session once its stream is closed
+	Disconnect(ctx context.Context, session *entity.Session) error
 	// CreateGroupChat creates a group chat, in case there is one it returns an error
 	CreateGroupChat(ctx context.Context, channelName, userName string) error
 	// JoinGroupChat checks whether chat exists, then subscribes user to chat room
@@ -19,4 +22,38 @@ type IChat interface {
 	ListChannels(ctx context.Context) (entity.Channels, error)
 	// SendMessage pushes a message to private or public chats
 	SendMessage(ctx context.Context, message entity.Message, userName string) error
+	// BlockUser stops delivering direct messages from blockedUser to userName
+	BlockUser(ctx context.Context, userName, blockedUser string) error
+	// UnblockUser resumes delivering direct messages from blockedUser to userName
+	UnblockUser(ctx context.Context, userName, blockedUser string) error
+	// ListBlocked provides a list of users blocked by userName
+	ListBlocked(ctx context.Context, userName string) ([]string, error)
+	// UploadAttachment stores attachment content read from r, returns attachment id
+	UploadAttachment(ctx context.Context, attachment entity.Attachment, userName string, r io.Reader) (string, error)
+	// DownloadAttachment checks user access to the attachment channel, returns attachment info and its content
+	DownloadAttachment(ctx context.Context, id, userName string) (entity.Attachment, io.ReadCloser, error)
+	// UploadKeys replaces public keys other users encrypt direct messages to userName with
+	UploadKeys(ctx context.Context, userName string, keys entity.KeyBundle) error
+	// GetKeys provides public keys of userName, a prekey handed out is removed
+	GetKeys(ctx context.Context, userName string) (entity.KeyBundle, error)
+}
+
+type IChatAdmin interface {
+	// ListSessions provides open sessions of connected users ordered by user name
+	ListSessions(ctx context.Context) ([]entity.SessionInfo, error)
+	// DisconnectUser ends the user session, the user is able to connect again
+	DisconnectUser(ctx context.Context, userName, adminName string) error
+	// GetGroup provides group members along with their connection state
+	GetGroup(ctx context.Context, channelName string) (entity.GroupInfo, error)
+	// DeleteGroup removes a group channel regardless of its members
+	DeleteGroup(ctx context.Context, channelName, adminName string) error
+	// Broadcast schedules an announcement for every connected user, users connecting within its validity window
+	// receive it as well
+	Broadcast(ctx context.Context, a entity.Announcement) (entity.Announcement, error)
+	// ListAnnouncements provides scheduled and active announcements ordered by start time
+	ListAnnouncements(ctx context.Context) ([]entity.Announcement, error)
+	// CancelAnnouncement stops a scheduled announcement or ends validity window of an active one
+	CancelAnnouncement(ctx context.Context, id, adminName string) error
+	// QueryAuditLog provides audit records matching filter
+	QueryAuditLog(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error)
 }
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (c controller) UploadKeys(ctx context.Context, req *chatApi.KeyBundle) (*emptypb.Empty, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	userName, err := getAuthorizationFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := entity.KeyBundle{
+		IdentityKey: req.GetIdentityKey(),
+		Prekeys:     make([]entity.Prekey, len(req.GetPrekeys())),
+	}
+
+	for i, prekey := range req.GetPrekeys() {
+		keys.Prekeys[i] = entity.Prekey{ID: prekey.GetId(), PublicKey: prekey.GetPublicKey()}
+	}
+
+	if err = c.chat.UploadKeys(ctx, userName, keys); err != nil {
+		return nil, statusFromError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (c controller) GetKeys(ctx context.Context, req *chatApi.UsernameRequest) (*chatApi.KeyBundle, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := getAuthorizationFromMD(ctx); err != nil {
+		return nil, err
+	}
+
+	keys, err := c.chat.GetKeys(ctx, req.GetUsername())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	res := &chatApi.KeyBundle{IdentityKey: keys.IdentityKey}
+	for _, prekey := range keys.Prekeys {
+		res.Prekeys = append(res.Prekeys, &chatApi.Prekey{Id: prekey.ID, PublicKey: prekey.PublicKey})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	retryAfterKey = "retry-after"
+	// limiterIdleTTL is a period of inactivity after which a bucket is refilled anyway and can be dropped
+	limiterIdleTTL = 10 * time.Minute
+)
+
+type (
+	// RateLimiter applies token bucket limits per authenticated user and per target channel of each configured method
+	RateLimiter struct {
+		methods map[string]config.MethodLimit
+
+		mu        sync.Mutex
+		buckets   map[string]*bucket
+		lastSweep time.Time
+		// now provides current time, it's replaced by tests
+		now func() time.Time
+	}
+
+	bucket struct {
+		limiter  *rate.Limiter
+		lastSeen time.Time
+	}
+
+	// channelRequest is implemented by requests targeting a group channel
+	channelRequest interface {
+		GetGroupChannelName() string
+	}
+
+	// userRequest is implemented by requests targeting a user
+	userRequest interface {
+		GetUsername() string
+	}
+
+	rateLimitedStream struct {
+		grpc.ServerStream
+		limiter *RateLimiter
+		method  string
+	}
+)
+
+func NewRateLimiter(cfg config.RateLimit) *RateLimiter {
+	return &RateLimiter{
+		methods:   cfg.Methods,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (l *RateLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, ok := l.methods[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+
+		if delay := l.reserve(info.FullMethod, userFromMD(ctx), targetChannel(req)); delay > 0 {
+			grpc.SetHeader(ctx, retryAfterMD(delay))
+			return nil, exhaustedError(delay)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (l *RateLimiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := l.methods[info.FullMethod]; !ok {
+			return handler(srv, ss)
+		}
+
+		if delay := l.reserve(info.FullMethod, userFromMD(ss.Context()), ""); delay > 0 {
+			ss.SetHeader(retryAfterMD(delay))
+			return exhaustedError(delay)
+		}
+
+		return handler(srv, &rateLimitedStream{ServerStream: ss, limiter: l, method: info.FullMethod})
+	}
+}
+
+// RecvMsg applies channel limit to every received message which targets a channel
+func (s *rateLimitedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if delay := s.limiter.reserve(s.method, "", targetChannel(m)); delay > 0 {
+		s.ServerStream.SetHeader(retryAfterMD(delay))
+		return exhaustedError(delay)
+	}
+
+	return nil
+}
+
+// reserve takes a token from user and channel buckets of the method. Keys of senders, group channels and
+// direct message recipients have distinct prefixes, so a user sending messages and the same user receiving
+// direct messages never share a bucket, returns a delay until call is allowed
+func (l *RateLimiter) reserve(method, user, channel string) time.Duration {
+	limits := l.methods[method]
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	var reservations []*rate.Reservation
+	cancelAll := func() {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+	}
+
+	for _, k := range []struct {
+		key   string
+		limit config.Limit
+	}{
+		{key: user, limit: limits.User},
+		{key: channel, limit: limits.Channel},
+	} {
+		if k.key == "" || k.limit.Rate <= 0 {
+			continue
+		}
+
+		r := l.bucket(method+"|"+k.key, k.limit, now).ReserveN(now, 1)
+		if !r.OK() {
+			cancelAll()
+			return time.Duration(math.MaxInt64)
+		}
+
+		reservations = append(reservations, r)
+
+		if delay := r.DelayFrom(now); delay > 0 {
+			cancelAll()
+			return delay
+		}
+	}
+
+	return 0
+}
+
+func (l *RateLimiter) bucket(key string, limit config.Limit, now time.Time) *rate.Limiter {
+	b, ok := l.buckets[key]
+	if !ok {
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.Rate), burst)}
+		l.buckets[key] = b
+	}
+
+	b.lastSeen = now
+
+	return b.limiter
+}
+
+// sweep drops buckets which have not been used for a while, they are full again by then
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < limiterIdleTTL {
+		return
+	}
+
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > limiterIdleTTL {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.lastSweep = now
+}
+
+func userFromMD(ctx context.Context) string {
+	userName, err := getAuthorizationFromMD(ctx)
+	if err != nil {
+		return ""
+	}
+
+	return "sender:" + userName
+}
+
+func targetChannel(req interface{}) string {
+	if r, ok := req.(channelRequest); ok && r.GetGroupChannelName() != "" {
+		return "group:" + r.GetGroupChannelName()
+	}
+
+	if r, ok := req.(userRequest); ok && r.GetUsername() != "" {
+		return "dm:" + r.GetUsername()
+	}
+
+	return ""
+}
+
+func retryAfterMD(delay time.Duration) metadata.MD {
+	return metadata.Pairs(retryAfterKey, strconv.FormatInt(retryAfterSeconds(delay), 10))
+}
+
+func exhaustedError(delay time.Duration) error {
+	return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry after %ds", retryAfterSeconds(delay))
+}
+
+func retryAfterSeconds(delay time.Duration) int64 {
+	return int64(math.Ceil(delay.Seconds()))
+}
@@ -0,0 +1,142 @@
+//go:build unit_tests
+// +build unit_tests
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	sendMethod   = "/b2bchatapi.Chat/SendMessage"
+	createMethod = "/b2bchatapi.Chat/CreateGroupChat"
+	keysMethod   = "/b2bchatapi.Chat/GetKeys"
+)
+
+func Test_RateLimiter(t *testing.T) {
+	t.Run("test bucket is refilled over time", func(t *testing.T) {
+		l, clock := newTestLimiter(map[string]config.MethodLimit{
+			sendMethod: {User: config.Limit{Rate: 1, Burst: 2}},
+		})
+
+		for i := 0; i < 2; i++ {
+			if delay := l.reserve(sendMethod, "sender:alice", ""); delay != 0 {
+				t.Fatalf("call %d within burst is delayed by %s", i, delay)
+			}
+		}
+
+		if delay := l.reserve(sendMethod, "sender:alice", ""); delay != time.Second {
+			t.Errorf("delay mismatch: exp: %s, act: %s", time.Second, delay)
+		}
+
+		*clock = clock.Add(time.Second)
+
+		if delay := l.reserve(sendMethod, "sender:alice", ""); delay != 0 {
+			t.Errorf("call after refill is delayed by %s", delay)
+		}
+	})
+
+	t.Run("test limits are applied per method", func(t *testing.T) {
+		l, _ := newTestLimiter(map[string]config.MethodLimit{
+			createMethod: {User: config.Limit{Rate: 1, Burst: 1}},
+		})
+
+		interceptor := l.UnaryServerInterceptor()
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "alice"))
+		handler := func(context.Context, interface{}) (interface{}, error) { return "ok", nil }
+
+		call := func(method string, req interface{}) error {
+			_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+			return err
+		}
+
+		for i := 0; i < 5; i++ {
+			if err := call(sendMethod, &chatApi.ChatMessage{}); err != nil {
+				t.Fatalf("call of a method without limits failed: %v", err)
+			}
+		}
+
+		req := &chatApi.GroupChannelNameRequest{GroupChannelName: "group1"}
+		if err := call(createMethod, req); err != nil {
+			t.Fatalf("first call failed: %v", err)
+		}
+
+		if err := call(createMethod, req); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("error mismatch: exp: %v, act: %v", codes.ResourceExhausted, err)
+		}
+	})
+
+	t.Run("test sender and direct message recipient buckets are separate", func(t *testing.T) {
+		l, _ := newTestLimiter(map[string]config.MethodLimit{
+			sendMethod: {User: config.Limit{Rate: 1, Burst: 1}, Channel: config.Limit{Rate: 1, Burst: 1}},
+		})
+
+		send := func(from string, msg *chatApi.ChatMessage) time.Duration {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", from))
+			return l.reserve(sendMethod, userFromMD(ctx), targetChannel(msg))
+		}
+
+		toAlice := &chatApi.ChatMessage{Destination: &chatApi.ChatMessage_Username{Username: "alice"}}
+		toGroup := &chatApi.ChatMessage{Destination: &chatApi.ChatMessage_GroupChannelName{GroupChannelName: "alice"}}
+
+		if delay := send("bob", toAlice); delay != 0 {
+			t.Fatalf("direct message to alice is delayed by %s", delay)
+		}
+
+		if delay := send("alice", toGroup); delay != 0 {
+			t.Errorf("message of alice is delayed by %s after a direct message to alice", delay)
+		}
+
+		if delay := send("carol", toAlice); delay == 0 {
+			t.Error("expected the second direct message to alice to be delayed")
+		}
+	})
+
+	t.Run("test keys of a user are limited across callers", func(t *testing.T) {
+		l, _ := newTestLimiter(map[string]config.MethodLimit{
+			keysMethod: {User: config.Limit{Rate: 1, Burst: 2}, Channel: config.Limit{Rate: 1, Burst: 2}},
+		})
+
+		interceptor := l.UnaryServerInterceptor()
+		handler := func(context.Context, interface{}) (interface{}, error) { return "ok", nil }
+
+		getKeys := func(from, of string) error {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", from))
+			_, err := interceptor(ctx, &chatApi.UsernameRequest{Username: of},
+				&grpc.UnaryServerInfo{FullMethod: keysMethod}, handler)
+			return err
+		}
+
+		for _, from := range []string{"bob", "carol"} {
+			if err := getKeys(from, "alice"); err != nil {
+				t.Fatalf("keys of alice requested by %s: %v", from, err)
+			}
+		}
+
+		if err := getKeys("dave", "alice"); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("error mismatch: exp: %v, act: %v", codes.ResourceExhausted, err)
+		}
+
+		if err := getKeys("dave", "bob"); err != nil {
+			t.Errorf("keys of bob requested by dave: %v", err)
+		}
+	})
+}
+
+func newTestLimiter(methods map[string]config.MethodLimit) (*RateLimiter, *time.Time) {
+	clock := time.Now()
+
+	l := NewRateLimiter(config.RateLimit{Methods: methods})
+	l.now = func() time.Time { return clock }
+
+	return l, &clock
+}
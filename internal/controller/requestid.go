@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const requestIDKey = "x-request-id"
+
+type requestIDStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDStream) Context() context.Context {
+	return s.ctx
+}
+
+// RequestIDUnaryServerInterceptor takes request id from metadata or generates a new one, puts it into context and response header
+func RequestIDUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id := requestIDFromMD(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+
+		return handler(requestid.NewContext(ctx, id), req)
+	}
+}
+
+// RequestIDStreamServerInterceptor takes request id from metadata or generates a new one, puts it into context and response header
+func RequestIDStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := requestIDFromMD(ss.Context())
+		ss.SetHeader(metadata.Pairs(requestIDKey, id))
+
+		return handler(srv, &requestIDStream{ServerStream: ss, ctx: requestid.NewContext(ss.Context(), id)})
+	}
+}
+
+func requestIDFromMD(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if id := md.Get(requestIDKey); len(id) > 0 && id[0] != "" {
+			return id[0]
+		}
+	}
+
+	return requestid.New()
+}
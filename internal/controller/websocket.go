@@ -0,0 +1,322 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/certs"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// wsTypeAuth introduces the user, it must be the first frame
+	wsTypeAuth = "auth"
+	// wsTypeConnect opens the message stream, its result is sent once the stream ends
+	wsTypeConnect = "connect"
+	wsTypeSend    = "send"
+	wsTypeCreate  = "create"
+	wsTypeJoin    = "join"
+	wsTypeLeave   = "leave"
+	wsTypeList    = "list"
+	// wsTypeResult answers a client frame with the same id
+	wsTypeResult = "result"
+	// wsTypeMessage carries a message of the stream
+	wsTypeMessage = "message"
+
+	wsPongWait     = 60 * time.Second
+	wsPingPeriod   = wsPongWait * 9 / 10
+	wsWriteWait    = 10 * time.Second
+	wsMaxFrameSize = 128 * 1024
+)
+
+type (
+	// websocketController serves the chat protocol encoded as json frames over a websocket. Frames are turned
+	// into calls of grpc server, so browser requests pass the same interceptors as grpc ones
+	websocketController struct {
+		chat     chatApi.ChatClient
+		upgrader websocket.Upgrader
+		// usernameFromCert authenticates users by client certificate instead of auth frame
+		usernameFromCert bool
+		log              *zap.Logger
+	}
+
+	// wsFrame is a websocket message, payload is a protojson encoded message of the grpc api
+	wsFrame struct {
+		ID      string          `json:"id,omitempty"`
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload,omitempty"`
+		Error   *wsError        `json:"error,omitempty"`
+	}
+
+	wsError struct {
+		// Code is a grpc status code name
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	// wsConn serializes frame writes, websocket connection supports a single concurrent writer
+	wsConn struct {
+		mu   sync.Mutex
+		conn *websocket.Conn
+	}
+
+	// wsSession keeps state of a single websocket connection
+	wsSession struct {
+		conn     *wsConn
+		userName string
+		// isStreaming is set while the message stream is open
+		isStreaming atomic.Bool
+		// streams tracks Connect handlers running for the connection
+		streams sync.WaitGroup
+	}
+)
+
+// NewWebSocket creates a websocket handler calling grpc server through conn. Browsers are allowed to connect
+// from allowedOrigins only, "*" allows any origin, while an empty list allows the same origin only
+func NewWebSocket(conn grpc.ClientConnInterface, allowedOrigins []string, usernameFromCert bool, log *zap.Logger) websocketController {
+	c := websocketController{
+		chat:             chatApi.NewChatClient(conn),
+		usernameFromCert: usernameFromCert,
+		log:              log,
+	}
+
+	if len(allowedOrigins) > 0 {
+		allowed := originAllowed(allowedOrigins)
+		c.upgrader.CheckOrigin = func(r *http.Request) bool {
+			return allowed(r.Header.Get("Origin"))
+		}
+	}
+
+	return c
+}
+
+func (c websocketController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// user authenticated by certificate doesn't send auth frame
+	var userName string
+	if c.usernameFromCert {
+		var ok bool
+		if userName, ok = certs.Username(r.TLS); !ok {
+			http.Error(w, "client certificate is required", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	// upgrader replies with an error itself
+	conn, err := c.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+
+	s := &wsSession{conn: &wsConn{conn: conn}, userName: userName}
+	// closed connection ends Connect stream, its handler has to finish writing before the connection is closed
+	defer s.streams.Wait()
+	defer cancel()
+
+	conn.SetReadLimit(wsMaxFrameSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	go s.conn.ping(ctx)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var frame wsFrame
+		if err = json.Unmarshal(data, &frame); err != nil {
+			s.conn.reply("", nil, status.Error(codes.InvalidArgument, "malformed frame"))
+			continue
+		}
+
+		if err = c.handle(ctx, s, frame); err != nil {
+			c.log.Debug("failed to write websocket frame", zap.Error(err))
+			return
+		}
+	}
+}
+
+// handle calls the grpc method the frame maps to, then replies with its result
+func (c websocketController) handle(ctx context.Context, s *wsSession, frame wsFrame) error {
+	if frame.Type != wsTypeAuth && s.userName == "" {
+		return s.conn.reply(frame.ID, nil, status.Error(codes.Unauthenticated, "auth frame is expected first"))
+	}
+
+	// user name is passed to grpc server the same way grpc clients pass it
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", s.userName))
+
+	var (
+		resp proto.Message
+		err  error
+	)
+
+	switch frame.Type {
+	case wsTypeAuth:
+		if s.userName != "" {
+			err = status.Error(codes.FailedPrecondition, "user is already authenticated")
+			break
+		}
+
+		req := &chatApi.UsernameRequest{}
+		if err = decodePayload(frame.Payload, req); err == nil {
+			err = req.Validate()
+		}
+
+		if err != nil {
+			err = status.Error(codes.InvalidArgument, err.Error())
+		} else {
+			s.userName = req.GetUsername()
+		}
+
+	case wsTypeConnect:
+		if !s.isStreaming.CompareAndSwap(false, true) {
+			err = status.Error(codes.FailedPrecondition, "message stream is already open")
+			break
+		}
+
+		s.streams.Add(1)
+
+		go func(userName, id string) {
+			defer s.streams.Done()
+			defer s.isStreaming.Store(false)
+
+			s.conn.reply(id, nil, c.stream(ctx, s.conn, userName))
+		}(s.userName, frame.ID)
+
+		return nil
+
+	case wsTypeSend:
+		req := &chatApi.ChatMessage{}
+		if err = decodePayload(frame.Payload, req); err == nil {
+			resp, err = c.chat.SendMessage(ctx, req)
+		}
+
+	case wsTypeCreate, wsTypeJoin, wsTypeLeave:
+		req := &chatApi.GroupChannelNameRequest{}
+		if err = decodePayload(frame.Payload, req); err != nil {
+			break
+		}
+
+		switch frame.Type {
+		case wsTypeCreate:
+			resp, err = c.chat.CreateGroupChat(ctx, req)
+		case wsTypeJoin:
+			resp, err = c.chat.JoinGroupChat(ctx, req)
+		default:
+			resp, err = c.chat.LeaveGroupChat(ctx, req)
+		}
+
+	case wsTypeList:
+		resp, err = c.chat.ListChannels(ctx, &emptypb.Empty{})
+
+	default:
+		err = status.Errorf(codes.InvalidArgument, "unknown frame type %q", frame.Type)
+	}
+
+	return s.conn.reply(frame.ID, resp, err)
+}
+
+// stream passes messages of the user Connect stream to the websocket until the stream ends
+func (c websocketController) stream(ctx context.Context, conn *wsConn, userName string) error {
+	stream, err := c.chat.Connect(ctx, &chatApi.ConnectRequest{Username: userName})
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		payload, err := protojson.Marshal(msg)
+		if err != nil {
+			return err
+		}
+
+		// a client not reading for wsWriteWait ends the stream
+		if err = conn.write(wsFrame{Type: wsTypeMessage, Payload: payload}); err != nil {
+			return err
+		}
+	}
+}
+
+// decodePayload reads a protojson encoded request, missing payload leaves request empty for validation to fail
+func decodePayload(payload json.RawMessage, req proto.Message) error {
+	if len(payload) == 0 {
+		return nil
+	}
+
+	if err := protojson.Unmarshal(payload, req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil
+}
+
+func (c *wsConn) reply(id string, resp proto.Message, err error) error {
+	frame := wsFrame{ID: id, Type: wsTypeResult}
+
+	if err == nil && resp != nil {
+		frame.Payload, err = protojson.Marshal(resp)
+	}
+
+	if err != nil {
+		st := status.Convert(err)
+		frame.Error = &wsError{Code: st.Code().String(), Message: st.Message()}
+	}
+
+	return c.write(frame)
+}
+
+func (c *wsConn) write(frame wsFrame) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+
+	return c.conn.WriteJSON(frame)
+}
+
+// ping keeps the connection alive and lets read deadline detect a dead peer, control frames may be written
+// concurrently with data frames
+func (c *wsConn) ping(ctx context.Context) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+//go:build unit_tests
+// +build unit_tests
+
+package controller
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"github.com/gorilla/websocket"
+	recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeChat serves calls websocket frames are turned into, the rest of IChat panics
+type fakeChat struct {
+	IChat
+	sessions chan *entity.Session
+	sent     chan string
+}
+
+func (c *fakeChat) Connect(_ context.Context, userName string) (*entity.Session, error) {
+	session := entity.NewSession(userName, 1)
+	c.sessions <- session
+
+	return session, nil
+}
+
+func (c *fakeChat) Disconnect(context.Context, *entity.Session) error {
+	return nil
+}
+
+func (c *fakeChat) SendMessage(_ context.Context, message entity.Message, userName string) error {
+	if message.Message == "panic" {
+		panic("broken handler")
+	}
+
+	c.sent <- userName + ": " + message.Message
+
+	return nil
+}
+
+func Test_WebSocket(t *testing.T) {
+	chat := &fakeChat{sessions: make(chan *entity.Session, 1), sent: make(chan string, 1)}
+
+	var calls atomic.Int32
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				calls.Add(1)
+				return handler(ctx, req)
+			},
+			recovery.UnaryServerInterceptor(),
+		),
+	)
+	chatApi.RegisterChatServer(grpcServer, New(chat, 0))
+
+	listener := bufconn.Listen(1 << 20)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	server := httptest.NewServer(NewWebSocket(conn, nil, false, zap.NewNop()))
+	t.Cleanup(server.Close)
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial websocket: %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	call := func(frame wsFrame) wsFrame {
+		t.Helper()
+
+		if err := ws.WriteJSON(frame); err != nil {
+			t.Fatalf("failed to write frame: %v", err)
+		}
+
+		ws.SetReadDeadline(time.Now().Add(time.Second))
+
+		var res wsFrame
+		if err := ws.ReadJSON(&res); err != nil {
+			t.Fatalf("failed to read frame: %v", err)
+		}
+
+		return res
+	}
+
+	send := `{"username":"user2","message":"%s"}`
+
+	t.Run("test frames before auth are rejected", func(t *testing.T) {
+		res := call(wsFrame{ID: "1", Type: wsTypeSend, Payload: []byte(strings.Replace(send, "%s", "hi", 1))})
+		if res.Error == nil || res.Error.Code != "Unauthenticated" {
+			t.Errorf("expected unauthenticated error, got %+v", res)
+		}
+	})
+
+	t.Run("test frames pass grpc server interceptors", func(t *testing.T) {
+		if res := call(wsFrame{ID: "2", Type: wsTypeAuth, Payload: []byte(`{"username":"user1"}`)}); res.Error != nil {
+			t.Fatalf("failed to authenticate: %+v", res.Error)
+		}
+
+		res := call(wsFrame{ID: "3", Type: wsTypeSend, Payload: []byte(strings.Replace(send, "%s", "hi", 1))})
+		if res.Error != nil || res.ID != "3" {
+			t.Fatalf("failed to send message: %+v", res.Error)
+		}
+
+		if act := <-chat.sent; act != "user1: hi" {
+			t.Errorf("message mismatch: exp: %q, act: %q", "user1: hi", act)
+		}
+
+		if calls.Load() != 1 {
+			t.Errorf("interceptor calls mismatch: exp: 1, act: %d", calls.Load())
+		}
+	})
+
+	t.Run("test panic of a handler is recovered", func(t *testing.T) {
+		res := call(wsFrame{ID: "4", Type: wsTypeSend, Payload: []byte(strings.Replace(send, "%s", "panic", 1))})
+		if res.Error == nil || res.Error.Code != "Internal" {
+			t.Errorf("expected internal error, got %+v", res)
+		}
+	})
+
+	t.Run("test stream messages are passed to websocket", func(t *testing.T) {
+		if err := ws.WriteJSON(wsFrame{ID: "5", Type: wsTypeConnect}); err != nil {
+			t.Fatalf("failed to write frame: %v", err)
+		}
+
+		var session *entity.Session
+		select {
+		case session = <-chat.sessions:
+		case <-time.After(time.Second):
+			t.Fatal("stream is not open")
+		}
+
+		session.Queue <- entity.Message{Message: "hello", ContentType: entity.PlainText}
+
+		ws.SetReadDeadline(time.Now().Add(time.Second))
+
+		var res wsFrame
+		if err := ws.ReadJSON(&res); err != nil {
+			t.Fatalf("failed to read frame: %v", err)
+		}
+
+		if res.Type != wsTypeMessage || !strings.Contains(string(res.Payload), "hello") {
+			t.Errorf("expected stream message, got %+v", res)
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// Announcement is a system message broadcast by an operator to all connected users
+type Announcement struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	// Author is an operator who made the announcement
+	Author string `json:"author"`
+	// StartAt is a time the announcement is delivered to connected users at
+	StartAt time.Time `json:"start_at"`
+	// ExpireAt is an end of validity window, users connecting before it receive the announcement as well
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+// IsExpired reports whether the announcement is neither pending nor within its validity window
+func (a Announcement) IsExpired(now time.Time) bool {
+	return !now.Before(a.StartAt) && !now.Before(a.ExpireAt)
+}
@@ -0,0 +1,13 @@
+package entity
+
+type Attachment struct {
+	ID       string `json:"id"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+	// Owner is a user who uploaded the attachment
+	Owner string `json:"owner"`
+	// To is a group channel or user name the attachment is posted to
+	To       string `json:"to"`
+	ChatType uint8  `json:"chat_type"`
+}
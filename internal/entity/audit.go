@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+const (
+	AuditGroupCreate = "group.create"
+	AuditGroupJoin   = "group.join"
+	AuditGroupLeave  = "group.leave"
+	// AuditGroupDelete is recorded when a group is removed, e.g. after its last member has left
+	AuditGroupDelete = "group.delete"
+	// AuditUserDisconnect is recorded when an operator forcibly disconnects a user
+	AuditUserDisconnect     = "user.disconnect"
+	AuditAnnouncementCreate = "announcement.create"
+	// AuditAnnouncementCancel is recorded when an operator cancels a scheduled or active announcement
+	AuditAnnouncementCancel = "announcement.cancel"
+	// AuditKeysUpload is recorded when a user replaces public keys of direct message encryption
+	AuditKeysUpload = "keys.upload"
+)
+
+type (
+	AuditRecord struct {
+		Time      time.Time `json:"time"`
+		RequestID string    `json:"request_id,omitempty"`
+		// Actor is a user who performed the action
+		Actor  string `json:"actor"`
+		Action string `json:"action"`
+		// Target is a group channel or a user the action is applied to
+		Target string `json:"target"`
+	}
+
+	AuditFilter struct {
+		// From and To bound record time, zero value means no bound
+		From time.Time
+		To   time.Time
+		// Actor filters records by actor, empty means any
+		Actor string
+		// Limit is a max amount of returned records, 0 means no limit
+		Limit int
+	}
+)
+
+func (f AuditFilter) Match(rec AuditRecord) bool {
+	if !f.From.IsZero() && rec.Time.Before(f.From) {
+		return false
+	}
+
+	if !f.To.IsZero() && rec.Time.After(f.To) {
+		return false
+	}
+
+	return f.Actor == "" || f.Actor == rec.Actor
+}
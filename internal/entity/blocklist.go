@@ -0,0 +1,36 @@
+package entity
+
+import "sync"
+
+// Blocklist keeps users whose direct messages are not accepted by its owner
+type Blocklist struct {
+	users sync.Map
+}
+
+func (b *Blocklist) Block(user string) bool {
+	_, isExist := b.users.LoadOrStore(user, struct{}{})
+	return !isExist
+}
+
+func (b *Blocklist) Unblock(user string) bool {
+	_, isExist := b.users.LoadAndDelete(user)
+	return isExist
+}
+
+func (b *Blocklist) IsBlocked(user string) bool {
+	_, isExist := b.users.Load(user)
+	return isExist
+}
+
+func (b *Blocklist) GetBlocked() []string {
+	users := []string{}
+
+	b.users.Range(func(key, _ any) bool {
+		if user, ok := key.(string); ok {
+			users = append(users, user)
+		}
+		return true
+	})
+
+	return users
+}
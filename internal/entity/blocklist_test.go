@@ -0,0 +1,62 @@
+//go:build unit_tests
+// +build unit_tests
+
+package entity
+
+import "testing"
+
+func Test_Block(t *testing.T) {
+	t.Run("test block user", func(t *testing.T) {
+		b := new(Blocklist)
+
+		const user = "blocked1"
+		isSucceed := b.Block(user)
+		if !isSucceed {
+			t.Error("failed to block user")
+		}
+
+		isSucceed = b.Block(user)
+		if isSucceed {
+			t.Error("user is blocked twice")
+		}
+
+		if !b.IsBlocked(user) {
+			t.Error("failed to check IsBlocked")
+		}
+	})
+}
+
+func Test_Unblock(t *testing.T) {
+	t.Run("test unblock user", func(t *testing.T) {
+		b := new(Blocklist)
+
+		const user = "blocked1"
+		isSucceed := b.Unblock(user)
+		if isSucceed {
+			t.Error("unblocked user which is not blocked")
+		}
+
+		b.Block(user)
+		isSucceed = b.Unblock(user)
+		if !isSucceed {
+			t.Error("failed to unblock user")
+		}
+
+		if b.IsBlocked(user) {
+			t.Error("user is still blocked")
+		}
+	})
+}
+
+func Test_GetBlocked(t *testing.T) {
+	t.Run("test get blocked users", func(t *testing.T) {
+		b := new(Blocklist)
+
+		b.Block("blocked1")
+		b.Block("blocked2")
+
+		if len(b.GetBlocked()) != 2 {
+			t.Error("blocked users amount mismatch")
+		}
+	})
+}
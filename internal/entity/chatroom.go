@@ -16,14 +16,6 @@ func (c *Chatroom) AddChannelInfo(info Channel) *Chatroom {
 func (c *Chatroom) AddSubscriber(user string) (isSucceed bool) {
 	isSucceed = true
 
-	if c.SubscribersLen() == 0 {
-		s := sync.Map{}
-		s.Store(user, struct{}{})
-		c.subscribers = s
-
-		return isSucceed
-	}
-
 	_, isExist := c.subscribers.LoadOrStore(user, struct{}{})
 	if isExist {
 		isSucceed = false
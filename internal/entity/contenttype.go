@@ -0,0 +1,10 @@
+package entity
+
+const (
+	PlainText uint8 = iota + 1
+	Markdown
+	Code
+	Link
+	// Encrypted content is relayed as is, server can't read it
+	Encrypted
+)
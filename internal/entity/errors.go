@@ -0,0 +1,12 @@
+package entity
+
+import "errors"
+
+// Error kinds usecase errors are wrapped with, so transport layers can pick a proper status code
+var (
+	ErrInvalidArgument  = errors.New("invalid argument")
+	ErrNotFound         = errors.New("not found")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrUnavailable      = errors.New("unavailable")
+)
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+type (
+	// GroupInfo describes a group channel state for operators
+	GroupInfo struct {
+		Name    string
+		Members []MemberInfo
+	}
+
+	MemberInfo struct {
+		User string
+		// Connected is false when the member has no open message stream
+		Connected bool
+		// Node is a replica the member is connected to
+		Node       string
+		QueueDepth int
+	}
+
+	// GroupActivity describes group messages a member has missed while offline
+	GroupActivity struct {
+		Group string
+		// Unread is an amount of missed messages, only the latest of them are kept in backlog
+		Unread int
+		LastAt time.Time
+	}
+)
@@ -0,0 +1,16 @@
+package entity
+
+type (
+	// KeyBundle keeps X25519 public keys other users encrypt direct messages to the user with
+	KeyBundle struct {
+		// IdentityKey is a long term key of the user
+		IdentityKey []byte `json:"identity_key"`
+		// Prekeys are one-time keys, each of them is handed out once
+		Prekeys []Prekey `json:"prekeys,omitempty"`
+	}
+
+	Prekey struct {
+		ID        string `json:"id"`
+		PublicKey []byte `json:"public_key"`
+	}
+)
@@ -4,4 +4,36 @@ type Message struct {
 	To       string
 	Message  string
 	ChatType uint8
+	// ContentType tells how Message is rendered: plain text, markdown, code block or link
+	ContentType uint8
+	// Language is a code block language tag
+	Language string
+	// Preview keeps link preview metadata, Message holds the link url
+	Preview *LinkPreview
+	// AttachmentID refers to an uploaded attachment posted along with the message
+	AttachmentID string
+	// Kind tells regular messages apart from server events
+	Kind uint8
+	// Activity lists groups with missed messages, it is set for an activity summary only
+	Activity []GroupActivity
+	// Ciphertext keeps encrypted content of a direct message, Message is empty then
+	Ciphertext *Ciphertext
+	// Announcement is an announcement change replicas exchange, it's never sent to users
+	Announcement *Announcement
+}
+
+// Ciphertext is a content encrypted by the sender for the recipient
+type Ciphertext struct {
+	SenderIdentityKey []byte
+	EphemeralKey      []byte
+	// PrekeyID is an id of the recipient prekey used, empty means no prekey is used
+	PrekeyID string
+	Nonce    []byte
+	Payload  []byte
+}
+
+type LinkPreview struct {
+	Title       string
+	Description string
+	ImageURL    string
 }
@@ -0,0 +1,14 @@
+package entity
+
+const (
+	RegularMessage uint8 = iota
+	ServerGoingAway
+	// SystemAnnouncement is a system message broadcast by an operator
+	SystemAnnouncement
+	// Disconnected is the last message of a stream terminated by an operator
+	Disconnected
+	// ActivitySummary lists groups with messages missed while the user was offline
+	ActivitySummary
+	// Backlog is a group message sent while the user was offline
+	Backlog
+)
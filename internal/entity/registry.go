@@ -0,0 +1,21 @@
+package entity
+
+import "fmt"
+
+// Errors of group registry, they are shared by registry implementations, so a node forwarding a change
+// to another one can restore them
+var (
+	ErrGroupExists    = fmt.Errorf("%w: group channel with such name already exists", ErrAlreadyExists)
+	ErrGroupNotFound  = fmt.Errorf("%w: group channel with such name is not found", ErrNotFound)
+	ErrMemberExists   = fmt.Errorf("%w: user is already inside the group channel", ErrAlreadyExists)
+	ErrMemberNotFound = fmt.Errorf("%w: user was not found in the specified group channel", ErrNotFound)
+	ErrKeysNotFound   = fmt.Errorf("%w: user has no public keys uploaded", ErrNotFound)
+	ErrUserBlocked    = fmt.Errorf("%w: user is already blocked", ErrAlreadyExists)
+	ErrUserNotBlocked = fmt.Errorf("%w: user is not blocked", ErrNotFound)
+)
+
+// RegistryErrors is a list of errors registry changes may fail with
+var RegistryErrors = []error{
+	ErrGroupExists, ErrGroupNotFound, ErrMemberExists, ErrMemberNotFound, ErrKeysNotFound, ErrUserBlocked,
+	ErrUserNotBlocked,
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Session is a message stream opened by a connected user
+	Session struct {
+		User        string
+		ConnectedAt time.Time
+		Queue       chan Message
+
+		done      chan struct{}
+		closeOnce sync.Once
+		// writingSince is unix nano time a write to the stream has started at, 0 means no write is in progress
+		writingSince atomic.Int64
+	}
+
+	// SessionInfo is a snapshot of a session state
+	SessionInfo struct {
+		User        string
+		ConnectedAt time.Time
+		QueueDepth  int
+		// Node is an id of the replica the session is open on
+		Node string
+	}
+)
+
+func NewSession(user string, queueSize int) *Session {
+	return &Session{
+		User:        user,
+		ConnectedAt: time.Now().UTC(),
+		Queue:       make(chan Message, queueSize),
+		done:        make(chan struct{}),
+	}
+}
+
+// Done is closed once the session is terminated by server
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close terminates the session, it is safe to call Close several times
+func (s *Session) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// StartWrite marks a write to the stream is in progress, a write blocks while the client doesn't read
+func (s *Session) StartWrite() {
+	s.writingSince.Store(time.Now().UnixNano())
+}
+
+// EndWrite marks the stream write is finished
+func (s *Session) EndWrite() {
+	s.writingSince.Store(0)
+}
+
+// IsStale reports whether the stream has not been writable for the timeout
+func (s *Session) IsStale(now time.Time, timeout time.Duration) bool {
+	since := s.writingSince.Load()
+
+	return since != 0 && now.Sub(time.Unix(0, since)) >= timeout
+}
+
+func (s *Session) Info() SessionInfo {
+	return SessionInfo{
+		User:        s.User,
+		ConnectedAt: s.ConnectedAt,
+		QueueDepth:  len(s.Queue),
+	}
+}
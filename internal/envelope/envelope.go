@@ -0,0 +1,367 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/fsutil"
+)
+
+const (
+	// KeySize is a size of master and data keys, they are AES-256 keys
+	KeySize = 32
+	// MagicSize is a size of a prefix telling encrypted data from plaintext
+	MagicSize = 4
+)
+
+// recordMagic starts data sealed by Seal, it tells sealed data from plaintext written before encryption was enabled
+var recordMagic = []byte("GCE\x01")
+
+var (
+	ErrNoMasterKey      = errors.New("master key is not configured")
+	ErrInvalidKeySize   = fmt.Errorf("key must be %d bytes", KeySize)
+	ErrMasterKeyChanged = errors.New("data keys are encrypted by another master key")
+	ErrUnknownChannel   = errors.New("channel has no data key")
+	ErrDecrypt          = errors.New("failed to decrypt data")
+	ErrPlaintext        = errors.New("data is not encrypted")
+)
+
+// Keyring encrypts data of a channel by its own data key, data keys are kept in a file encrypted by master key.
+// Master key is rotated by re-encrypting data keys only, so data itself is never rewritten. Data written in
+// plaintext before encryption was enabled is read until plaintextUntil only
+type Keyring struct {
+	mu             sync.Mutex
+	path           string
+	master         cipher.AEAD
+	id             string
+	keys           map[string]cipher.AEAD
+	plaintextUntil time.Time
+	now            func() time.Time
+}
+
+// keysFile is a content of data keys file, wrapped keys are bound to their channel names
+type keysFile struct {
+	// MasterKeyID tells a master key data keys are wrapped by, it's a prefix of the key hash
+	MasterKeyID string `json:"master_key_id"`
+	// Keys maps a channel to its data key wrapped by master key
+	Keys map[string][]byte `json:"keys"`
+}
+
+// New loads master key and data keys of the config, keys file is created once the first channel is encrypted
+func New(cfg config.Encryption) (*Keyring, error) {
+	master, err := LoadMasterKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.KeysFile == "" {
+		return nil, errors.New("keys file is not configured")
+	}
+
+	aead, err := newAEAD(master)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Keyring{
+		path:           cfg.KeysFile,
+		master:         aead,
+		id:             keyID(master),
+		keys:           make(map[string]cipher.AEAD),
+		plaintextUntil: cfg.AllowPlaintextUntil,
+		now:            time.Now,
+	}
+
+	file, err := readKeysFile(cfg.KeysFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.MasterKeyID != "" && file.MasterKeyID != k.id {
+		return nil, ErrMasterKeyChanged
+	}
+
+	for channel, wrapped := range file.Keys {
+		key, err := unwrap(aead, channel, wrapped)
+		if err != nil {
+			return nil, fmt.Errorf("data key of %q: %w", channel, err)
+		}
+
+		if k.keys[channel], err = newAEAD(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
+
+// Seal encrypts data of the channel, data key of the channel is created when it has none
+func (k *Keyring) Seal(channel string, data []byte) ([]byte, error) {
+	aead, err := k.key(channel, true)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	res := append(append([]byte{}, recordMagic...), nonce...)
+
+	return aead.Seal(res, nonce, data, []byte(channel)), nil
+}
+
+// Open decrypts data sealed for the channel. Plaintext data is returned as it is while plaintext is allowed,
+// ErrPlaintext is returned after that
+func (k *Keyring) Open(channel string, data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		if !k.plaintextAllowed() {
+			return nil, ErrPlaintext
+		}
+
+		return data, nil
+	}
+
+	aead, err := k.key(channel, false)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[len(recordMagic):]
+	if len(data) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	res, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(channel))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	return res, nil
+}
+
+// IsSealed reports whether data is sealed by Seal rather than written in plaintext
+func IsSealed(data []byte) bool {
+	return len(data) >= len(recordMagic) && string(data[:len(recordMagic)]) == string(recordMagic)
+}
+
+// plaintextAllowed reports whether data written before encryption was enabled may still be read
+func (k *Keyring) plaintextAllowed() bool {
+	return k.now().Before(k.plaintextUntil)
+}
+
+// key provides data key of the channel, a missing key is created and stored when create is set
+func (k *Keyring) key(channel string, create bool) (cipher.AEAD, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if aead, ok := k.keys[channel]; ok {
+		return aead, nil
+	}
+
+	if !create {
+		return nil, ErrUnknownChannel
+	}
+
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// file is read again, so a key is never added to a file rotated to another master key meanwhile
+	file, err := readKeysFile(k.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.MasterKeyID != "" && file.MasterKeyID != k.id {
+		return nil, ErrMasterKeyChanged
+	}
+
+	if file.Keys[channel], err = wrap(k.master, channel, key); err != nil {
+		return nil, err
+	}
+
+	file.MasterKeyID = k.id
+	if err = writeKeysFile(k.path, file); err != nil {
+		return nil, err
+	}
+
+	k.keys[channel] = aead
+
+	return aead, nil
+}
+
+// Rotate re-encrypts data keys of keys file by newKey, they must be encrypted by oldKey. Data encrypted by
+// the data keys stays readable
+func Rotate(path string, oldKey, newKey []byte) error {
+	oldMaster, err := newAEAD(oldKey)
+	if err != nil {
+		return err
+	}
+
+	newMaster, err := newAEAD(newKey)
+	if err != nil {
+		return err
+	}
+
+	file, err := readKeysFile(path)
+	if err != nil {
+		return err
+	}
+
+	if file.MasterKeyID != "" && file.MasterKeyID != keyID(oldKey) {
+		return ErrMasterKeyChanged
+	}
+
+	for channel, wrapped := range file.Keys {
+		key, err := unwrap(oldMaster, channel, wrapped)
+		if err != nil {
+			return fmt.Errorf("data key of %q: %w", channel, err)
+		}
+
+		if file.Keys[channel], err = wrap(newMaster, channel, key); err != nil {
+			return err
+		}
+	}
+
+	file.MasterKeyID = keyID(newKey)
+
+	return writeKeysFile(path, file)
+}
+
+// LoadMasterKey provides base64 encoded master key of the config, the key itself takes precedence over key file
+func LoadMasterKey(cfg config.Encryption) ([]byte, error) {
+	if cfg.MasterKey != "" {
+		return DecodeKey(cfg.MasterKey)
+	}
+
+	if cfg.MasterKeyFile == "" {
+		return nil, ErrNoMasterKey
+	}
+
+	return ReadKeyFile(cfg.MasterKeyFile)
+}
+
+// ReadKeyFile reads base64 encoded key from the file
+func ReadKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeKey(string(data))
+}
+
+// DecodeKey decodes base64 encoded key, surrounding whitespace is ignored
+func DecodeKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("decode key: %w", err)
+	}
+
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// keyID identifies a key without disclosing it
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// wrap encrypts data key by master key, channel is authenticated, so a key can't be moved to another channel
+func wrap(master cipher.AEAD, channel string, key []byte) ([]byte, error) {
+	nonce := make([]byte, master.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return master.Seal(nonce, nonce, key, []byte(channel)), nil
+}
+
+func unwrap(master cipher.AEAD, channel string, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < master.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	key, err := master.Open(nil, wrapped[:master.NonceSize()], wrapped[master.NonceSize():], []byte(channel))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	return key, nil
+}
+
+func readKeysFile(path string) (keysFile, error) {
+	file := keysFile{Keys: make(map[string][]byte)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+
+	if err != nil {
+		return file, err
+	}
+
+	if err = json.Unmarshal(data, &file); err != nil {
+		return file, err
+	}
+
+	if file.Keys == nil {
+		file.Keys = make(map[string][]byte)
+	}
+
+	return file, nil
+}
+
+// writeKeysFile replaces the file via rename, so a crash never leaves data keys partially written
+func writeKeysFile(path string, file keysFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	return fsutil.WriteAtomic(path, data)
+}
@@ -0,0 +1,270 @@
+//go:build unit_tests
+// +build unit_tests
+
+package envelope
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+)
+
+func Test_SealOpen(t *testing.T) {
+	cfg := newConfig(t)
+
+	k, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	sealed, err := k.Seal("group:g1", []byte("hello"))
+	if err != nil {
+		t.Fatalf("failed to seal: %v", err)
+	}
+
+	if bytes.Contains(sealed, []byte("hello")) {
+		t.Error("sealed data contains plaintext")
+	}
+
+	t.Run("test data is opened by a keyring loaded again", func(t *testing.T) {
+		loaded, err := New(cfg)
+		if err != nil {
+			t.Fatalf("failed to load keyring: %v", err)
+		}
+
+		act, err := loaded.Open("group:g1", sealed)
+		if err != nil {
+			t.Fatalf("failed to open: %v", err)
+		}
+		if string(act) != "hello" {
+			t.Errorf("data mismatch: exp: hello, act: %s", act)
+		}
+	})
+
+	t.Run("test data of another channel is not opened", func(t *testing.T) {
+		if _, err := k.Seal("group:g2", []byte("other")); err != nil {
+			t.Fatalf("failed to seal: %v", err)
+		}
+
+		if _, err := k.Open("group:g2", sealed); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("error mismatch: exp: %v, act: %v", ErrDecrypt, err)
+		}
+	})
+
+	t.Run("test keyring of another master key is rejected", func(t *testing.T) {
+		other := cfg
+		other.MasterKey = newKey(t)
+
+		if _, err := New(other); !errors.Is(err, ErrMasterKeyChanged) {
+			t.Errorf("error mismatch: exp: %v, act: %v", ErrMasterKeyChanged, err)
+		}
+	})
+}
+
+func Test_Stream(t *testing.T) {
+	k, err := New(newConfig(t))
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	tcs := []struct {
+		name string
+		size int
+	}{
+		{name: "test empty stream", size: 0},
+		{name: "test stream shorter than a segment", size: 100},
+		{name: "test stream of whole segments", size: 2 * segmentSize},
+		{name: "test stream of several segments", size: 3*segmentSize + 17},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			data := make([]byte, tc.size)
+			rand.Read(data)
+
+			sealed := seal(t, k, "direct:u1:u2", data)
+
+			r, err := k.NewReader("direct:u1:u2", bytes.NewReader(sealed))
+			if err != nil {
+				t.Fatalf("failed to create reader: %v", err)
+			}
+
+			act, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("failed to read: %v", err)
+			}
+			if !bytes.Equal(act, data) {
+				t.Errorf("data mismatch: exp: %d bytes, act: %d bytes", len(data), len(act))
+			}
+		})
+	}
+
+	t.Run("test truncated stream fails", func(t *testing.T) {
+		data := make([]byte, 2*segmentSize+10)
+		sealed := seal(t, k, "direct:u1:u2", data)
+
+		// the final segment is dropped, the rest is a valid stream prefix
+		truncated := sealed[:MagicSize+noncePrefixSize+2*(segmentSize+16)]
+
+		r, err := k.NewReader("direct:u1:u2", bytes.NewReader(truncated))
+		if err != nil {
+			t.Fatalf("failed to create reader: %v", err)
+		}
+
+		if _, err = io.ReadAll(r); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("error mismatch: exp: %v, act: %v", ErrDecrypt, err)
+		}
+	})
+}
+
+func Test_Plaintext(t *testing.T) {
+	k, err := New(newConfig(t))
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	k.now = func() time.Time { return now }
+
+	tcs := []struct {
+		name  string
+		until time.Time
+		err   error
+	}{
+		{name: "test plaintext is read within migration window", until: now.Add(time.Hour)},
+		{name: "test plaintext is rejected after migration window", until: now, err: ErrPlaintext},
+		{name: "test plaintext is rejected without migration window", err: ErrPlaintext},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			k.plaintextUntil = tc.until
+
+			for _, data := range [][]byte{[]byte("{}"), []byte(`{"plaintext written before":"encryption"}`)} {
+				act, err := k.Open("announcements", data)
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("open error mismatch: exp: %v, act: %v", tc.err, err)
+				}
+				if err == nil && !bytes.Equal(act, data) {
+					t.Errorf("data mismatch: exp: %s, act: %s", data, act)
+				}
+
+				r, err := k.NewReader("direct:u1:u2", bytes.NewReader(data))
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("reader error mismatch: exp: %v, act: %v", tc.err, err)
+				}
+				if err != nil {
+					continue
+				}
+
+				if act, err = io.ReadAll(r); err != nil || !bytes.Equal(act, data) {
+					t.Errorf("data mismatch: exp: %s, act: %s, err: %v", data, act, err)
+				}
+			}
+		})
+	}
+}
+
+func Test_Rotate(t *testing.T) {
+	cfg := newConfig(t)
+
+	k, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	sealed, err := k.Seal("announcements", []byte("hello"))
+	if err != nil {
+		t.Fatalf("failed to seal: %v", err)
+	}
+
+	current, err := LoadMasterKey(cfg)
+	if err != nil {
+		t.Fatalf("failed to load master key: %v", err)
+	}
+
+	rotated := cfg
+	rotated.MasterKey = newKey(t)
+	next, _ := DecodeKey(rotated.MasterKey)
+
+	if err = Rotate(cfg.KeysFile, next, current); !errors.Is(err, ErrMasterKeyChanged) {
+		t.Errorf("error mismatch: exp: %v, act: %v", ErrMasterKeyChanged, err)
+	}
+
+	if err = Rotate(cfg.KeysFile, current, next); err != nil {
+		t.Fatalf("failed to rotate: %v", err)
+	}
+
+	if _, err = New(cfg); !errors.Is(err, ErrMasterKeyChanged) {
+		t.Errorf("error mismatch: exp: %v, act: %v", ErrMasterKeyChanged, err)
+	}
+
+	k, err = New(rotated)
+	if err != nil {
+		t.Fatalf("failed to load keyring: %v", err)
+	}
+
+	act, err := k.Open("announcements", sealed)
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	if string(act) != "hello" {
+		t.Errorf("data mismatch: exp: hello, act: %s", act)
+	}
+}
+
+func seal(t *testing.T, k *Keyring, channel string, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w, err := k.NewWriter(channel, &buf)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	if _, err = w.Write(data); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func newConfig(t *testing.T) config.Encryption {
+	t.Helper()
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "master.key")
+
+	if err := os.WriteFile(keyFile, []byte(newKey(t)+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write master key: %v", err)
+	}
+
+	return config.Encryption{
+		MasterKeyFile: keyFile,
+		KeysFile:      filepath.Join(dir, "keys.json"),
+	}
+}
+
+func newKey(t *testing.T) string {
+	t.Helper()
+
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key)
+}
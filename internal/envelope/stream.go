@@ -0,0 +1,233 @@
+package envelope
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+const (
+	// segmentSize is a size of plaintext sealed at once, so a file is never kept in memory as a whole
+	segmentSize = 64 << 10
+	// noncePrefixSize is a size of random nonce part of a stream, the rest is segment counter and final flag
+	noncePrefixSize = 7
+)
+
+// streamMagic starts a stream written by NewWriter
+var streamMagic = []byte("GCE\x02")
+
+var errStreamTooLong = errors.New("stream exceeds max amount of segments")
+
+// NewWriter encrypts data written to w by data key of the channel. Data is split into segments sealed
+// one by one, the last one is marked final, so a truncated stream fails to decrypt. Writer must be closed
+// to write the last segment
+func (k *Keyring) NewWriter(channel string, w io.Writer) (io.WriteCloser, error) {
+	aead, err := k.key(channel, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &streamWriter{
+		w:       w,
+		aead:    aead,
+		channel: []byte(channel),
+		buf:     make([]byte, 0, segmentSize),
+		out:     make([]byte, 0, segmentSize+aead.Overhead()),
+	}
+
+	if _, err = rand.Read(s.prefix[:]); err != nil {
+		return nil, err
+	}
+
+	if _, err = w.Write(append(append([]byte{}, streamMagic...), s.prefix[:]...)); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// NewReader decrypts a stream of the channel written by NewWriter. Plaintext data is read as it is while
+// plaintext is allowed, ErrPlaintext is returned after that
+func (k *Keyring) NewReader(channel string, r io.Reader) (io.Reader, error) {
+	header := make([]byte, len(streamMagic)+noncePrefixSize)
+
+	n, err := io.ReadFull(r, header)
+	if !IsStream(header[:n]) {
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+
+		if !k.plaintextAllowed() {
+			return nil, ErrPlaintext
+		}
+
+		return io.MultiReader(bytes.NewReader(header[:n]), r), nil
+	}
+
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	aead, err := k.key(channel, false)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &streamReader{
+		r:       r,
+		aead:    aead,
+		channel: []byte(channel),
+		buf:     make([]byte, segmentSize+aead.Overhead()),
+	}
+	copy(s.prefix[:], header[len(streamMagic):])
+
+	return s, nil
+}
+
+// IsStream reports whether data starts with a header written by NewWriter
+func IsStream(data []byte) bool {
+	return len(data) >= len(streamMagic) && string(data[:len(streamMagic)]) == string(streamMagic)
+}
+
+type streamWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	channel []byte
+	prefix  [noncePrefixSize]byte
+	counter uint32
+	buf     []byte
+	out     []byte
+	closed  bool
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if s.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	n := 0
+	for len(p) > 0 {
+		chunk := segmentSize - len(s.buf)
+		if chunk > len(p) {
+			chunk = len(p)
+		}
+
+		s.buf = append(s.buf, p[:chunk]...)
+		p = p[chunk:]
+		n += chunk
+
+		// a full segment is written once more data comes, the last one is written by Close as final
+		if len(s.buf) == segmentSize && len(p) > 0 {
+			if err := s.flush(false); err != nil {
+				return n, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// Close writes the last segment, it doesn't close the underlying writer
+func (s *streamWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	if len(s.buf) == segmentSize {
+		if err := s.flush(false); err != nil {
+			return err
+		}
+	}
+
+	// the final segment is shorter than a full one, it's empty when data fills whole segments
+	return s.flush(true)
+}
+
+func (s *streamWriter) flush(final bool) error {
+	if s.counter == math.MaxUint32 {
+		return errStreamTooLong
+	}
+
+	s.out = s.aead.Seal(s.out[:0], nonce(s.prefix, s.counter, final), s.buf, s.channel)
+	s.buf = s.buf[:0]
+	s.counter++
+
+	_, err := s.w.Write(s.out)
+
+	return err
+}
+
+type streamReader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	channel []byte
+	prefix  [noncePrefixSize]byte
+	counter uint32
+	buf     []byte
+	plain   []byte
+	done    bool
+}
+
+func (s *streamReader) Read(p []byte) (int, error) {
+	for len(s.plain) == 0 {
+		if s.done {
+			return 0, io.EOF
+		}
+
+		if err := s.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, s.plain)
+	s.plain = s.plain[n:]
+
+	return n, nil
+}
+
+// next decrypts the next segment, a segment shorter than a full one is the final one
+func (s *streamReader) next() error {
+	n, err := io.ReadFull(s.r, s.buf)
+
+	final := false
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		final = true
+	case errors.Is(err, io.EOF):
+		// the final segment is always written, its absence means the stream is truncated
+		return ErrDecrypt
+	case err != nil:
+		return err
+	}
+
+	plain, err := s.aead.Open(s.buf[:0], nonce(s.prefix, s.counter, final), s.buf[:n], s.channel)
+	if err != nil {
+		return ErrDecrypt
+	}
+
+	s.plain = plain
+	s.counter++
+	s.done = final
+
+	return nil
+}
+
+// nonce is the stream prefix followed by segment counter and final flag, so segments can't be reordered,
+// dropped or taken from another stream
+func nonce(prefix [noncePrefixSize]byte, counter uint32, final bool) []byte {
+	res := make([]byte, 0, noncePrefixSize+5)
+	res = append(res, prefix[:]...)
+	res = binary.BigEndian.AppendUint32(res, counter)
+
+	if final {
+		return append(res, 1)
+	}
+
+	return append(res, 0)
+}
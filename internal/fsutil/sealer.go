@@ -0,0 +1,22 @@
+package fsutil
+
+import "io"
+
+type (
+	// ISealer encrypts data stores write to disk, every store encrypts its data by a key of its own channel
+	ISealer interface {
+		// Seal encrypts data by a key of the channel
+		Seal(channel string, data []byte) ([]byte, error)
+		// Open decrypts data of the channel
+		Open(channel string, data []byte) ([]byte, error)
+	}
+
+	// IStreamSealer encrypts data too large to be kept in memory as a stream
+	IStreamSealer interface {
+		ISealer
+		// NewWriter encrypts data written to w by a key of the channel
+		NewWriter(channel string, w io.Writer) (io.WriteCloser, error)
+		// NewReader decrypts data of the channel read from r
+		NewReader(channel string, r io.Reader) (io.Reader, error)
+	}
+)
@@ -0,0 +1,34 @@
+// Package fsutil provides what stores keeping data in files share: atomic file replacement and the sealer
+// encrypting data written to disk
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteAtomic replaces the file at path by data readable by the owner only. Data is written into a temporary
+// file in the same directory, synced and renamed over path, so a crash never leaves the file partially written
+func WriteAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,53 @@
+//go:build unit_tests
+// +build unit_tests
+
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_WriteAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+
+	for _, data := range []string{"first", "second"} {
+		if err := WriteAtomic(path, []byte(data)); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+
+		act, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+
+		if string(act) != data {
+			t.Errorf("content mismatch: exp: %s, act: %s", data, act)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat: %v", err)
+	}
+
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected file to be readable by the owner only, got %s", perm)
+	}
+
+	// temporary files are removed once renamed
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected a single file, got %d", len(entries))
+	}
+
+	if err = WriteAtomic(filepath.Join(dir, "missing", "data.json"), []byte("data")); err == nil {
+		t.Error("expected error for a missing directory")
+	}
+}
@@ -0,0 +1,160 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/certs"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// heartbeatPeriod is a period of comments sent to an idle event stream, so proxies don't close it
+const heartbeatPeriod = 30 * time.Second
+
+// New creates a REST facade of Chat service calling grpc server through conn, so REST requests pass the same
+// interceptors as grpc ones. Connect stream is served as server-sent events. When usernameFromCert is set,
+// user name is taken from client certificate instead of Authorization header
+func New(ctx context.Context, conn *grpc.ClientConn, usernameFromCert bool) (http.Handler, error) {
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(headerMatcher))
+
+	if err := chatApi.RegisterChatHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+
+	chat := chatApi.NewChatClient(conn)
+	if err := mux.HandlePath(http.MethodGet, "/v1/events", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		userName := r.URL.Query().Get("username")
+		if usernameFromCert {
+			userName, _ = certs.Username(r.TLS)
+		}
+
+		serveEvents(w, r, chat, userName)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := mux.HandlePath(http.MethodGet, "/v1/openapi.json", func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(chatApi.OpenAPI)
+	}); err != nil {
+		return nil, err
+	}
+
+	if !usernameFromCert {
+		return mux, nil
+	}
+
+	// mux passes Authorization header to grpc metadata regardless of header matcher, so the header is replaced
+	// by the certificate user name instead of appending one more value
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del("Authorization")
+		if userName, ok := certs.Username(r.TLS); ok {
+			r.Header.Set("Authorization", userName)
+		}
+
+		mux.ServeHTTP(w, r)
+	}), nil
+}
+
+// headerMatcher passes headers to grpc metadata as the default matcher does, except of the ones setting
+// authorization metadata. User name is passed by Authorization header only
+func headerMatcher(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case "Authorization", runtime.MetadataHeaderPrefix + "Authorization":
+		return "", false
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// serveEvents opens Connect stream of the user, stream messages are sent as message events. Stream errors
+// are sent as error event, since response status is sent before stream starts
+func serveEvents(w http.ResponseWriter, r *http.Request, chat chatApi.ChatClient, userName string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream, err := chat.Connect(ctx, &chatApi.ConnectRequest{Username: userName})
+	if err != nil {
+		http.Error(w, err.Error(), runtime.HTTPStatusFromCode(status.Code(err)))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	messages := make(chan *chatApi.ChatMessage)
+	streamErr := make(chan error, 1)
+
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				streamErr <- err
+				return
+			}
+
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(heartbeatPeriod)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+
+		case msg := <-messages:
+			if err = writeEvent(w, "message", msg); err != nil {
+				return
+			}
+
+		case err = <-streamErr:
+			if !errors.Is(err, io.EOF) {
+				writeEvent(w, "error", status.Convert(err).Proto())
+			}
+
+			flusher.Flush()
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, event string, msg proto.Message) error {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+
+	return err
+}
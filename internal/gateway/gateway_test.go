@@ -0,0 +1,312 @@
+//go:build unit_tests
+// +build unit_tests
+
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// fakeChat records authorization metadata of the calls the gateway makes
+type fakeChat struct {
+	chatApi.UnimplementedChatServer
+	auth chan []string
+	sent chan *chatApi.ChatMessage
+}
+
+func (c *fakeChat) authorization(ctx context.Context) []string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.auth <- md.Get("authorization")
+
+	return md.Get("authorization")
+}
+
+func (c *fakeChat) ListBlocked(ctx context.Context, _ *emptypb.Empty) (*chatApi.Usernames, error) {
+	if len(c.authorization(ctx)) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "user name is not set")
+	}
+
+	return &chatApi.Usernames{Items: []string{"user2"}}, nil
+}
+
+func (c *fakeChat) SendMessage(ctx context.Context, msg *chatApi.ChatMessage) (*emptypb.Empty, error) {
+	c.authorization(ctx)
+	if msg.GetMessage() == "" {
+		return nil, status.Error(codes.InvalidArgument, "message is empty")
+	}
+
+	c.sent <- msg
+
+	return &emptypb.Empty{}, nil
+}
+
+func (c *fakeChat) Connect(req *chatApi.ConnectRequest, stream chatApi.Chat_ConnectServer) error {
+	if req.GetUsername() == "" {
+		return status.Error(codes.Unauthenticated, "user name is not set")
+	}
+
+	for _, text := range []string{"hello", "bye"} {
+		msg := &chatApi.ChatMessage{
+			Destination: &chatApi.ChatMessage_Username{Username: req.GetUsername()},
+			Content:     &chatApi.ChatMessage_Message{Message: text},
+		}
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newTestGateway(t *testing.T, usernameFromCert bool) (http.Handler, *fakeChat) {
+	t.Helper()
+
+	chat := &fakeChat{auth: make(chan []string, 1), sent: make(chan *chatApi.ChatMessage, 1)}
+
+	grpcServer := grpc.NewServer()
+	chatApi.RegisterChatServer(grpcServer, chat)
+
+	listener := NewPipeListener()
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("pipe",
+		grpc.WithContextDialer(listener.Dial),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	handler, err := New(ctx, conn, usernameFromCert)
+	if err != nil {
+		t.Fatalf("failed to create gateway: %v", err)
+	}
+
+	return handler, chat
+}
+
+func Test_REST(t *testing.T) {
+	handler, chat := newTestGateway(t, false)
+
+	t.Run("header is passed as user name", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/blocked", nil)
+		req.Header.Set("Authorization", "user1")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body)
+		}
+
+		if auth := <-chat.auth; len(auth) != 1 || auth[0] != "user1" {
+			t.Fatalf("unexpected authorization %v", auth)
+		}
+
+		var blocked struct {
+			Items []string `json:"items"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &blocked); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		if len(blocked.Items) != 1 || blocked.Items[0] != "user2" {
+			t.Fatalf("unexpected response %v", blocked.Items)
+		}
+	})
+
+	t.Run("grpc code is mapped to http status", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/blocked", nil))
+		<-chat.auth
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+
+	t.Run("body is decoded into request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/messages",
+			strings.NewReader(`{"username":"user2","message":"hi"}`))
+		req.Header.Set("Authorization", "user1")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		<-chat.auth
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body)
+		}
+
+		msg := <-chat.sent
+		if msg.GetUsername() != "user2" || msg.GetMessage() != "hi" {
+			t.Fatalf("unexpected message %v", msg)
+		}
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(`{"username":"user2"}`))
+		req.Header.Set("Authorization", "user1")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		<-chat.auth
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("openapi document", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil))
+
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+			t.Fatalf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+		}
+
+		if !json.Valid(rec.Body.Bytes()) {
+			t.Fatal("openapi document is not valid json")
+		}
+	})
+}
+
+func Test_AuthorizationHeaders(t *testing.T) {
+	certState := &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "user1"}}}},
+	}
+
+	tests := []struct {
+		name             string
+		usernameFromCert bool
+		state            *tls.ConnectionState
+		header           http.Header
+		want             []string
+	}{
+		{
+			name:   "metadata header is ignored",
+			header: http.Header{"Authorization": {"user1"}, "Grpc-Metadata-Authorization": {"admin"}},
+			want:   []string{"user1"},
+		},
+		{
+			name:   "lower case metadata header is ignored",
+			header: http.Header{"grpc-metadata-authorization": {"admin"}},
+		},
+		{
+			name:             "certificate user name replaces headers",
+			usernameFromCert: true,
+			state:            certState,
+			header:           http.Header{"Authorization": {"admin"}, "Grpc-Metadata-Authorization": {"admin"}},
+			want:             []string{"user1"},
+		},
+		{
+			name:             "headers are ignored without certificate",
+			usernameFromCert: true,
+			header:           http.Header{"Authorization": {"admin"}, "Grpc-Metadata-Authorization": {"admin"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, chat := newTestGateway(t, tt.usernameFromCert)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/blocked", nil)
+			req.Header = tt.header
+			req.TLS = tt.state
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			auth := <-chat.auth
+			if len(auth) != len(tt.want) || (len(auth) > 0 && auth[0] != tt.want[0]) {
+				t.Fatalf("expected authorization %v, got %v", tt.want, auth)
+			}
+		})
+	}
+}
+
+func Test_Events(t *testing.T) {
+	handler, _ := newTestGateway(t, false)
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	t.Run("stream messages are sent as events", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/v1/events?username=user1")
+		if err != nil {
+			t.Fatalf("failed to request events: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("unexpected response %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+
+		// stream ends after the messages, so the body is closed by server
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read events: %v", err)
+		}
+
+		events := strings.Split(strings.TrimSpace(string(body)), "\n\n")
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events, got %q", body)
+		}
+
+		for i, text := range []string{"hello", "bye"} {
+			lines := strings.SplitN(events[i], "\n", 2)
+			if lines[0] != "event: message" {
+				t.Fatalf("unexpected event %q", events[i])
+			}
+
+			var msg struct {
+				Username string `json:"username"`
+				Message  string `json:"message"`
+			}
+			if err = json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &msg); err != nil {
+				t.Fatalf("failed to decode event data: %v", err)
+			}
+
+			if msg.Username != "user1" || msg.Message != text {
+				t.Fatalf("unexpected message %+v", msg)
+			}
+		}
+	})
+
+	t.Run("stream error is sent as error event", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/v1/events")
+		if err != nil {
+			t.Fatalf("failed to request events: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read events: %v", err)
+		}
+
+		if !strings.HasPrefix(string(body), "event: error\n") || !strings.Contains(string(body), "user name is not set") {
+			t.Fatalf("unexpected events %q", body)
+		}
+	})
+}
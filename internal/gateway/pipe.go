@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"context"
+	"net"
+	"sync"
+)
+
+type (
+	// pipeListener passes in-process connections to grpc server, so gateway requests don't go through network
+	pipeListener struct {
+		conns     chan net.Conn
+		done      chan struct{}
+		closeOnce sync.Once
+	}
+
+	pipeAddr struct{}
+)
+
+func NewPipeListener() *pipeListener {
+	return &pipeListener{
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+// Dial connects to the listener, it is used as grpc context dialer
+func (l *pipeListener) Dial(ctx context.Context, _ string) (net.Conn, error) {
+	server, client := net.Pipe()
+
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (pipeAddr) Network() string {
+	return "pipe"
+}
+
+func (pipeAddr) String() string {
+	return "pipe"
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "chat"
+
+type chat struct {
+	connectedUsers    prometheus.Gauge
+	activeGroups      prometheus.Gauge
+	messagesSent      *prometheus.CounterVec
+	fanoutLatency     prometheus.Histogram
+	droppedDeliveries prometheus.Counter
+}
+
+// NewChat creates and registers chat domain metrics
+func NewChat(reg prometheus.Registerer) *chat {
+	c := &chat{
+		connectedUsers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "connected_users",
+			Help:      "Amount of users with an open message stream.",
+		}),
+		activeGroups: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active_groups",
+			Help:      "Amount of existing group channels.",
+		}),
+		messagesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_sent_total",
+			Help:      "Amount of sent messages by chat type.",
+		}, []string{"chat_type"}),
+		fanoutLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "fanout_duration_seconds",
+			Help:      "Time spent delivering a group message to all subscriber queues.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+		}),
+		droppedDeliveries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dropped_deliveries_total",
+			Help:      "Amount of messages not delivered to a subscriber.",
+		}),
+	}
+
+	reg.MustRegister(c.connectedUsers, c.activeGroups, c.messagesSent, c.fanoutLatency, c.droppedDeliveries)
+
+	return c
+}
+
+func (c *chat) SetConnectedUsers(n int) {
+	c.connectedUsers.Set(float64(n))
+}
+
+func (c *chat) SetActiveGroups(n int) {
+	c.activeGroups.Set(float64(n))
+}
+
+func (c *chat) MessageSent(chatType uint8) {
+	c.messagesSent.WithLabelValues(chatTypeLabel(chatType)).Inc()
+}
+
+func (c *chat) ObserveFanout(d time.Duration) {
+	c.fanoutLatency.Observe(d.Seconds())
+}
+
+func (c *chat) DeliveryDropped() {
+	c.droppedDeliveries.Inc()
+}
+
+func chatTypeLabel(chatType uint8) string {
+	switch chatType {
+	case entity.OneToOne:
+		return "one_to_one"
+	case entity.OneToMany:
+		return "one_to_many"
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,117 @@
+//go:build unit_tests
+// +build unit_tests
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func Test_Chat(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := NewChat(reg)
+
+	c.SetConnectedUsers(3)
+	c.SetActiveGroups(2)
+	c.MessageSent(entity.OneToOne)
+	c.MessageSent(entity.OneToMany)
+	c.MessageSent(entity.OneToMany)
+	c.ObserveFanout(time.Millisecond)
+	c.DeliveryDropped()
+
+	families := gather(t, reg)
+
+	tcs := []struct {
+		name   string
+		labels map[string]string
+		exp    float64
+	}{
+		{name: "chat_connected_users", exp: 3},
+		{name: "chat_active_groups", exp: 2},
+		{name: "chat_messages_sent_total", labels: map[string]string{"chat_type": "one_to_one"}, exp: 1},
+		{name: "chat_messages_sent_total", labels: map[string]string{"chat_type": "one_to_many"}, exp: 2},
+		{name: "chat_fanout_duration_seconds", exp: 1},
+		{name: "chat_dropped_deliveries_total", exp: 1},
+	}
+
+	for _, tc := range tcs {
+		m := find(families[tc.name], tc.labels)
+		if m == nil {
+			t.Errorf("metric %s%v is not found", tc.name, tc.labels)
+			continue
+		}
+
+		if act := value(m); act != tc.exp {
+			t.Errorf("value of %s%v mismatch: exp: %v, act: %v", tc.name, tc.labels, tc.exp, act)
+		}
+	}
+}
+
+func Test_QueueDepth(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewQueueDepth(func() map[string]int {
+		return map[string]int{"user1": 4, "user2": 0}
+	}))
+
+	families := gather(t, reg)
+
+	for user, exp := range map[string]float64{"user1": 4, "user2": 0} {
+		m := find(families["chat_connection_queue_depth"], map[string]string{"user": user})
+		if m == nil || m.GetGauge().GetValue() != exp {
+			t.Errorf("queue depth of %s mismatch: exp: %v, act: %v", user, exp, m)
+		}
+	}
+}
+
+func gather(t *testing.T, reg *prometheus.Registry) map[string]*dto.MetricFamily {
+	t.Helper()
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+
+	res := make(map[string]*dto.MetricFamily, len(families))
+	for _, f := range families {
+		res[f.GetName()] = f
+	}
+
+	return res
+}
+
+// find provides a metric of the family having all the labels
+func find(family *dto.MetricFamily, labels map[string]string) *dto.Metric {
+	for _, m := range family.GetMetric() {
+		matched := 0
+		for _, pair := range m.GetLabel() {
+			if labels[pair.GetName()] == pair.GetValue() {
+				matched++
+			}
+		}
+
+		if matched == len(labels) {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// value provides a gauge or counter value, or a sample count of a histogram
+func value(m *dto.Metric) float64 {
+	switch {
+	case m.GetGauge() != nil:
+		return m.GetGauge().GetValue()
+	case m.GetCounter() != nil:
+		return m.GetCounter().GetValue()
+	case m.GetHistogram() != nil:
+		return float64(m.GetHistogram().GetSampleCount())
+	}
+
+	return 0
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// queueDepth collects connection queue lengths on scrape
+type queueDepth struct {
+	desc   *prometheus.Desc
+	source func() map[string]int
+}
+
+// NewQueueDepth creates a collector of per connection queue depth, source provides queue length by user name
+func NewQueueDepth(source func() map[string]int) *queueDepth {
+	return &queueDepth{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "connection_queue_depth"),
+			"Amount of messages waiting in a connection queue.",
+			[]string{"user"}, nil,
+		),
+		source: source,
+	}
+}
+
+func (q *queueDepth) Describe(ch chan<- *prometheus.Desc) {
+	ch <- q.desc
+}
+
+func (q *queueDepth) Collect(ch chan<- prometheus.Metric) {
+	for user, depth := range q.source() {
+		ch <- prometheus.MustNewConstMetric(q.desc, prometheus.GaugeValue, float64(depth), user)
+	}
+}
@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+)
+
+const applyPath = "/registry/apply"
+
+// maxCommandSize limits a forwarded change, names are short so a command is never close to it
+const maxCommandSize = 64 * 1024
+
+// Handler serves registry changes forwarded by follower replicas, it's served over mutual TLS, so changes are
+// accepted from replicas only
+func Handler(r *raftRegistry) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(applyPath, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "method is not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		cmd, err := io.ReadAll(io.LimitReader(req.Body, maxCommandSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		res, err := r.Apply(req.Context(), cmd)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	})
+
+	return mux
+}
+
+// HTTPForwarder forwards registry changes to the leader api address taken from peers over https, client must
+// present a replica certificate
+func HTTPForwarder(peers []config.RegistryPeer, client *http.Client) Forwarder {
+	addrs := make(map[string]string, len(peers))
+	for _, peer := range peers {
+		addrs[peer.ID] = peer.APIAddr
+	}
+
+	return func(ctx context.Context, leaderID string, cmd []byte) (Result, error) {
+		addr, ok := addrs[leaderID]
+		if !ok {
+			return Result{}, fmt.Errorf("%w: leader %q is not found in peers", entity.ErrUnavailable, leaderID)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+addr+applyPath, bytes.NewReader(cmd))
+		if err != nil {
+			return Result{}, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return Result{}, fmt.Errorf("%w: %s", entity.ErrUnavailable, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxCommandSize))
+			return Result{}, fmt.Errorf("%w: leader failed to apply registry change: %s", entity.ErrUnavailable,
+				bytes.TrimSpace(msg))
+		}
+
+		var res Result
+		if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			return Result{}, errors.New("malformed registry change result")
+		}
+
+		return res, nil
+	}
+}
@@ -0,0 +1,400 @@
+package registry
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/shard"
+)
+
+// local keeps groups, presence, blocklists and public keys of a single replica in memory. All are sharded, so changes
+// of unrelated groups and users don't wait for each other
+type local struct {
+	// nodeID is an id of the replica
+	nodeID string
+
+	// groups keeps a list of active chat rooms (map[chat_name]chat)
+	groups *shard.Map[*entity.Chatroom]
+	// presence keeps replicas users are connected to (map[user_name]node_id)
+	presence *shard.Map[string]
+	// blocklists keeps users each user doesn't accept direct messages from (map[user_name]blocklist)
+	blocklists *shard.Map[*entity.Blocklist]
+	// keys keeps public keys of direct message encryption (map[user_name]keys)
+	keys *shard.Map[*entity.KeyBundle]
+}
+
+func NewLocal(nodeID string) *local {
+	return &local{
+		nodeID:     nodeID,
+		groups:     shard.New[*entity.Chatroom](),
+		presence:   shard.New[string](),
+		blocklists: shard.New[*entity.Blocklist](),
+		keys:       shard.New[*entity.KeyBundle](),
+	}
+}
+
+// NodeID provides an id of the replica
+func (l *local) NodeID() string {
+	return l.nodeID
+}
+
+// CreateGroup registers a group with its first member
+func (l *local) CreateGroup(ctx context.Context, group, owner string) error {
+	if err := l.groups.With(group, entity.SafeWrite, func(groups map[string]*entity.Chatroom) error {
+		if _, ok := groups[group]; ok {
+			return entity.ErrGroupExists
+		}
+
+		groups[group] = newChatroom(group, owner)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// JoinGroup adds a member to the group
+func (l *local) JoinGroup(ctx context.Context, group, user string) error {
+	if err := l.groups.With(group, entity.SafeWrite, func(groups map[string]*entity.Chatroom) error {
+		chatroom, ok := groups[group]
+		if !ok {
+			return entity.ErrGroupNotFound
+		}
+
+		if !chatroom.AddSubscriber(user) {
+			return entity.ErrMemberExists
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// LeaveGroup removes a member from the group, the group is removed once its last member has left
+func (l *local) LeaveGroup(ctx context.Context, group, user string) (isDeleted bool, err error) {
+	if err = l.groups.With(group, entity.SafeWrite, func(groups map[string]*entity.Chatroom) error {
+		chatroom, ok := groups[group]
+		if !ok {
+			return entity.ErrGroupNotFound
+		}
+
+		if !chatroom.RemoveSubscriber(user) {
+			return entity.ErrMemberNotFound
+		}
+
+		if chatroom.SubscribersLen() == 0 {
+			delete(groups, group)
+			isDeleted = true
+		}
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return isDeleted, ctx.Err()
+}
+
+// DeleteGroup removes a group regardless of its members
+func (l *local) DeleteGroup(ctx context.Context, group string) error {
+	if err := l.groups.With(group, entity.SafeWrite, func(groups map[string]*entity.Chatroom) error {
+		if _, ok := groups[group]; !ok {
+			return entity.ErrGroupNotFound
+		}
+
+		delete(groups, group)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// Groups provides existing groups ordered by name
+func (l *local) Groups(ctx context.Context) (entity.Channels, error) {
+	res := make(entity.Channels, 0)
+
+	l.groups.Range(func(_ string, chatroom *entity.Chatroom) bool {
+		res = append(res, chatroom.Channel)
+		return true
+	})
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res, ctx.Err()
+}
+
+// Members provides group members ordered by name
+func (l *local) Members(ctx context.Context, group string) ([]string, error) {
+	var res []string
+
+	if err := l.groups.With(group, entity.SafeRead, func(groups map[string]*entity.Chatroom) error {
+		chatroom, ok := groups[group]
+		if !ok {
+			return entity.ErrGroupNotFound
+		}
+
+		res = chatroom.GetSubscribers()
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(res)
+
+	return res, ctx.Err()
+}
+
+// SetPresence records a replica the user is connected to
+func (l *local) SetPresence(ctx context.Context, user, node string) error {
+	l.presence.With(user, entity.SafeWrite, func(presence map[string]string) error {
+		presence[user] = node
+
+		return nil
+	})
+
+	return ctx.Err()
+}
+
+// RemovePresence forgets the user connection, unless the user has reconnected to another replica meanwhile
+func (l *local) RemovePresence(ctx context.Context, user, node string) error {
+	l.presence.With(user, entity.SafeWrite, func(presence map[string]string) error {
+		if presence[user] == node {
+			delete(presence, user)
+		}
+
+		return nil
+	})
+
+	return ctx.Err()
+}
+
+// Presence provides a replica the user is connected to, empty string means the user is offline
+func (l *local) Presence(ctx context.Context, user string) (string, error) {
+	var node string
+
+	l.presence.With(user, entity.SafeRead, func(presence map[string]string) error {
+		node = presence[user]
+
+		return nil
+	})
+
+	return node, ctx.Err()
+}
+
+// Connected provides users connected to any replica along with ids of their replicas
+func (l *local) Connected(ctx context.Context) (map[string]string, error) {
+	res := make(map[string]string)
+
+	l.presence.Range(func(user, node string) bool {
+		res[user] = node
+		return true
+	})
+
+	return res, ctx.Err()
+}
+
+// Block stops accepting direct messages of blocked by the user
+func (l *local) Block(ctx context.Context, user, blocked string) error {
+	if err := l.blocklists.With(user, entity.SafeWrite, func(blocklists map[string]*entity.Blocklist) error {
+		blocklist, ok := blocklists[user]
+		if !ok {
+			blocklist = new(entity.Blocklist)
+			blocklists[user] = blocklist
+		}
+
+		if !blocklist.Block(blocked) {
+			return entity.ErrUserBlocked
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// Unblock resumes accepting direct messages of blocked by the user
+func (l *local) Unblock(ctx context.Context, user, blocked string) error {
+	if err := l.blocklists.With(user, entity.SafeWrite, func(blocklists map[string]*entity.Blocklist) error {
+		blocklist, ok := blocklists[user]
+		if !ok || !blocklist.Unblock(blocked) {
+			return entity.ErrUserNotBlocked
+		}
+
+		if len(blocklist.GetBlocked()) == 0 {
+			delete(blocklists, user)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// Blocked provides users blocked by the user ordered by name
+func (l *local) Blocked(ctx context.Context, user string) ([]string, error) {
+	res := []string{}
+
+	l.blocklists.With(user, entity.SafeRead, func(blocklists map[string]*entity.Blocklist) error {
+		if blocklist, ok := blocklists[user]; ok {
+			res = blocklist.GetBlocked()
+		}
+
+		return nil
+	})
+
+	sort.Strings(res)
+
+	return res, ctx.Err()
+}
+
+// PutKeys replaces public keys of the user
+func (l *local) PutKeys(ctx context.Context, user string, keys entity.KeyBundle) error {
+	l.keys.With(user, entity.SafeWrite, func(bundles map[string]*entity.KeyBundle) error {
+		bundles[user] = &keys
+
+		return nil
+	})
+
+	return ctx.Err()
+}
+
+// TakeKeys provides identity key of the user along with the oldest prekey, the prekey is removed
+func (l *local) TakeKeys(ctx context.Context, user string) (entity.KeyBundle, error) {
+	var res entity.KeyBundle
+
+	if err := l.keys.With(user, entity.SafeWrite, func(bundles map[string]*entity.KeyBundle) error {
+		keys, ok := bundles[user]
+		if !ok {
+			return entity.ErrKeysNotFound
+		}
+
+		res.IdentityKey = keys.IdentityKey
+
+		if len(keys.Prekeys) > 0 {
+			res.Prekeys = keys.Prekeys[:1:1]
+			keys.Prekeys = keys.Prekeys[1:]
+		}
+
+		return nil
+	}); err != nil {
+		return entity.KeyBundle{}, err
+	}
+
+	return res, ctx.Err()
+}
+
+func (l *local) Close() error {
+	return nil
+}
+
+// state is a serializable copy of registry content
+type state struct {
+	// Groups maps a group name to its members
+	Groups   map[string][]string `json:"groups"`
+	Presence map[string]string   `json:"presence"`
+	// Blocklists maps a user name to users blocked by the user
+	Blocklists map[string][]string `json:"blocklists"`
+	// Keys maps a user name to public keys
+	Keys map[string]entity.KeyBundle `json:"keys"`
+}
+
+// snapshot copies registry content shard by shard, it is consistent as long as no change is applied meanwhile,
+// which raft guarantees for fsm snapshots
+func (l *local) snapshot() state {
+	res := state{
+		Groups:     make(map[string][]string),
+		Presence:   make(map[string]string),
+		Blocklists: make(map[string][]string),
+		Keys:       make(map[string]entity.KeyBundle),
+	}
+
+	l.groups.Range(func(name string, chatroom *entity.Chatroom) bool {
+		res.Groups[name] = chatroom.GetSubscribers()
+		return true
+	})
+
+	l.presence.Range(func(user, node string) bool {
+		res.Presence[user] = node
+		return true
+	})
+
+	l.blocklists.Range(func(user string, blocklist *entity.Blocklist) bool {
+		res.Blocklists[user] = blocklist.GetBlocked()
+		return true
+	})
+
+	l.keys.Range(func(user string, keys *entity.KeyBundle) bool {
+		res.Keys[user] = *keys
+		return true
+	})
+
+	return res
+}
+
+func (l *local) restore(s state) {
+	l.groups.Clear()
+	for name, members := range s.Groups {
+		chatroom := newChatroom(name, members...)
+		l.groups.With(name, entity.SafeWrite, func(groups map[string]*entity.Chatroom) error {
+			groups[name] = chatroom
+
+			return nil
+		})
+	}
+
+	l.presence.Clear()
+	for user, node := range s.Presence {
+		l.presence.With(user, entity.SafeWrite, func(presence map[string]string) error {
+			presence[user] = node
+
+			return nil
+		})
+	}
+
+	l.blocklists.Clear()
+	for user, blocked := range s.Blocklists {
+		blocklist := new(entity.Blocklist)
+		for _, item := range blocked {
+			blocklist.Block(item)
+		}
+
+		l.blocklists.With(user, entity.SafeWrite, func(blocklists map[string]*entity.Blocklist) error {
+			blocklists[user] = blocklist
+
+			return nil
+		})
+	}
+
+	l.keys.Clear()
+	for user, keys := range s.Keys {
+		keys := keys
+		l.keys.With(user, entity.SafeWrite, func(bundles map[string]*entity.KeyBundle) error {
+			bundles[user] = &keys
+
+			return nil
+		})
+	}
+}
+
+func newChatroom(name string, members ...string) *entity.Chatroom {
+	chatroom := new(entity.Chatroom).AddChannelInfo(entity.Channel{Name: name, Type: entity.OneToMany})
+	for _, member := range members {
+		chatroom.AddSubscriber(member)
+	}
+
+	return chatroom
+}
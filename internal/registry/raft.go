@@ -0,0 +1,410 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/hashicorp/raft"
+)
+
+const (
+	opCreateGroup    = "create_group"
+	opJoinGroup      = "join_group"
+	opLeaveGroup     = "leave_group"
+	opDeleteGroup    = "delete_group"
+	opSetPresence    = "set_presence"
+	opRemovePresence = "remove_presence"
+	opBlock          = "block"
+	opUnblock        = "unblock"
+	opPutKeys        = "put_keys"
+	opTakeKeys       = "take_keys"
+
+	defaultTimeout = 5 * time.Second
+	// appliedPollInterval is a period a follower checks whether a forwarded change has reached its state
+	appliedPollInterval = 5 * time.Millisecond
+)
+
+var (
+	errNoLeader  = fmt.Errorf("%w: registry cluster has no leader", entity.ErrUnavailable)
+	errUnknownOp = errors.New("unknown registry operation")
+)
+
+type (
+	// Forwarder sends an encoded registry change to the leader replica
+	Forwarder func(ctx context.Context, leaderID string, cmd []byte) (Result, error)
+
+	// Result is an outcome of a registry change applied by the leader
+	Result struct {
+		// Index is a raft log index of the change, followers wait for it before replying to keep read-your-writes
+		Index     uint64 `json:"index"`
+		IsDeleted bool   `json:"is_deleted,omitempty"`
+		// Keys are public keys taken from the directory
+		Keys  *entity.KeyBundle `json:"keys,omitempty"`
+		Error string            `json:"error,omitempty"`
+	}
+
+	command struct {
+		Op    string `json:"op"`
+		Group string `json:"group,omitempty"`
+		User  string `json:"user,omitempty"`
+		Node  string `json:"node,omitempty"`
+		// Blocked is a user blocked or unblocked by User
+		Blocked string            `json:"blocked,omitempty"`
+		Keys    *entity.KeyBundle `json:"keys,omitempty"`
+	}
+
+	// raftRegistry replicates registry changes to every replica through raft log, changes made on a follower
+	// are forwarded to the leader. Reads are served from the replica state
+	raftRegistry struct {
+		raft    *raft.Raft
+		fsm     *fsm
+		state   *local
+		forward Forwarder
+		timeout time.Duration
+		// closers are called on Close after raft is shut down
+		closers []func() error
+	}
+
+	fsm struct {
+		state *local
+		// applied is an index of the last log entry applied to state, raft reports its own applied index
+		// before entries reach fsm
+		applied atomic.Uint64
+	}
+
+	fsmSnapshot struct {
+		Index uint64 `json:"index"`
+		State state  `json:"state"`
+	}
+)
+
+// NewRaft starts a raft replica. Logs and snapshots are kept in memory, so registry content lives as long as
+// a quorum of replicas is alive. Every replica bootstraps the cluster of configured peers, which is safe since
+// the configuration is identical
+func NewRaft(cfg config.Registry, transport raft.Transport, forward Forwarder) (*raftRegistry, error) {
+	conf := raft.DefaultConfig()
+	conf.LocalID = raft.ServerID(cfg.NodeID)
+	conf.LogLevel = "WARN"
+
+	return newRaft(conf, cfg.Peers, cfg.Timeout, transport, forward)
+}
+
+func newRaft(conf *raft.Config, peers []config.RegistryPeer, timeout time.Duration, transport raft.Transport,
+	forward Forwarder) (*raftRegistry, error) {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	r := &raftRegistry{
+		state:   NewLocal(string(conf.LocalID)),
+		forward: forward,
+		timeout: timeout,
+	}
+
+	logs := raft.NewInmemStore()
+	snapshots := raft.NewInmemSnapshotStore()
+
+	servers := make([]raft.Server, len(peers))
+	for i, peer := range peers {
+		servers[i] = raft.Server{ID: raft.ServerID(peer.ID), Address: raft.ServerAddress(peer.RaftAddr)}
+	}
+
+	err := raft.BootstrapCluster(conf, logs, logs, snapshots, transport, raft.Configuration{Servers: servers})
+	if err != nil {
+		return nil, err
+	}
+
+	r.fsm = &fsm{state: r.state}
+
+	r.raft, err = raft.NewRaft(conf, r.fsm, logs, logs, snapshots, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// NodeID provides an id of the replica
+func (r *raftRegistry) NodeID() string {
+	return r.state.NodeID()
+}
+
+// CreateGroup registers a group with its first member
+func (r *raftRegistry) CreateGroup(ctx context.Context, group, owner string) error {
+	_, err := r.change(ctx, command{Op: opCreateGroup, Group: group, User: owner})
+	return err
+}
+
+// JoinGroup adds a member to the group
+func (r *raftRegistry) JoinGroup(ctx context.Context, group, user string) error {
+	_, err := r.change(ctx, command{Op: opJoinGroup, Group: group, User: user})
+	return err
+}
+
+// LeaveGroup removes a member from the group, the group is removed once its last member has left
+func (r *raftRegistry) LeaveGroup(ctx context.Context, group, user string) (bool, error) {
+	res, err := r.change(ctx, command{Op: opLeaveGroup, Group: group, User: user})
+	return res.IsDeleted, err
+}
+
+// DeleteGroup removes a group regardless of its members
+func (r *raftRegistry) DeleteGroup(ctx context.Context, group string) error {
+	_, err := r.change(ctx, command{Op: opDeleteGroup, Group: group})
+	return err
+}
+
+// Groups provides existing groups ordered by name
+func (r *raftRegistry) Groups(ctx context.Context) (entity.Channels, error) {
+	return r.state.Groups(ctx)
+}
+
+// Members provides group members ordered by name
+func (r *raftRegistry) Members(ctx context.Context, group string) ([]string, error) {
+	return r.state.Members(ctx, group)
+}
+
+// SetPresence records a replica the user is connected to
+func (r *raftRegistry) SetPresence(ctx context.Context, user, node string) error {
+	_, err := r.change(ctx, command{Op: opSetPresence, User: user, Node: node})
+	return err
+}
+
+// RemovePresence forgets the user connection, unless the user has reconnected to another replica meanwhile
+func (r *raftRegistry) RemovePresence(ctx context.Context, user, node string) error {
+	_, err := r.change(ctx, command{Op: opRemovePresence, User: user, Node: node})
+	return err
+}
+
+// Presence provides a replica the user is connected to, empty string means the user is offline
+func (r *raftRegistry) Presence(ctx context.Context, user string) (string, error) {
+	return r.state.Presence(ctx, user)
+}
+
+// Connected provides users connected to any replica along with ids of their replicas
+func (r *raftRegistry) Connected(ctx context.Context) (map[string]string, error) {
+	return r.state.Connected(ctx)
+}
+
+// Block stops accepting direct messages of blocked by the user
+func (r *raftRegistry) Block(ctx context.Context, user, blocked string) error {
+	_, err := r.change(ctx, command{Op: opBlock, User: user, Blocked: blocked})
+	return err
+}
+
+// Unblock resumes accepting direct messages of blocked by the user
+func (r *raftRegistry) Unblock(ctx context.Context, user, blocked string) error {
+	_, err := r.change(ctx, command{Op: opUnblock, User: user, Blocked: blocked})
+	return err
+}
+
+// Blocked provides users blocked by the user ordered by name
+func (r *raftRegistry) Blocked(ctx context.Context, user string) ([]string, error) {
+	return r.state.Blocked(ctx, user)
+}
+
+// PutKeys replaces public keys of the user
+func (r *raftRegistry) PutKeys(ctx context.Context, user string, keys entity.KeyBundle) error {
+	_, err := r.change(ctx, command{Op: opPutKeys, User: user, Keys: &keys})
+	return err
+}
+
+// TakeKeys provides identity key of the user along with the oldest prekey, the prekey is removed. Taking a prekey
+// changes the directory, so it goes through raft log as well
+func (r *raftRegistry) TakeKeys(ctx context.Context, user string) (entity.KeyBundle, error) {
+	res, err := r.change(ctx, command{Op: opTakeKeys, User: user})
+	if err != nil || res.Keys == nil {
+		return entity.KeyBundle{}, err
+	}
+
+	return *res.Keys, nil
+}
+
+// Apply commits an encoded registry change, it succeeds on the leader only
+func (r *raftRegistry) Apply(ctx context.Context, cmd []byte) (Result, error) {
+	f := r.raft.Apply(cmd, r.timeout)
+	if err := f.Error(); err != nil {
+		if errors.Is(err, raft.ErrNotLeader) || errors.Is(err, raft.ErrLeadershipLost) {
+			return Result{}, fmt.Errorf("%w: %s", entity.ErrUnavailable, err)
+		}
+
+		return Result{}, err
+	}
+
+	res, ok := f.Response().(Result)
+	if !ok {
+		return Result{}, errUnknownOp
+	}
+
+	res.Index = f.Index()
+
+	return res, ctx.Err()
+}
+
+func (r *raftRegistry) Close() error {
+	err := r.raft.Shutdown().Error()
+
+	for _, closer := range r.closers {
+		if closeErr := closer(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// change applies a registry change on the leader or forwards it there, then waits for the change to reach
+// the replica state
+func (r *raftRegistry) change(ctx context.Context, cmd command) (Result, error) {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var res Result
+	if r.raft.State() == raft.Leader {
+		res, err = r.Apply(ctx, data)
+	} else {
+		_, leaderID := r.raft.LeaderWithID()
+		if leaderID == "" {
+			return Result{}, errNoLeader
+		}
+
+		res, err = r.forward(ctx, string(leaderID), data)
+	}
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	if err = r.waitApplied(ctx, res.Index); err != nil {
+		return Result{}, err
+	}
+
+	return res, resultError(res.Error)
+}
+
+func (r *raftRegistry) waitApplied(ctx context.Context, index uint64) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(appliedPollInterval)
+	defer ticker.Stop()
+
+	for r.fsm.applied.Load() < index {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: registry change is not replicated in time", entity.ErrUnavailable)
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// resultError restores a registry error, so it keeps its kind after being forwarded
+func resultError(msg string) error {
+	if msg == "" {
+		return nil
+	}
+
+	for _, err := range entity.RegistryErrors {
+		if err.Error() == msg {
+			return err
+		}
+	}
+
+	return errors.New(msg)
+}
+
+func (f *fsm) Apply(l *raft.Log) interface{} {
+	defer f.applied.Store(l.Index)
+
+	var cmd command
+	if err := json.Unmarshal(l.Data, &cmd); err != nil {
+		return Result{Error: err.Error()}
+	}
+
+	var (
+		ctx = context.Background()
+		res Result
+		err error
+	)
+
+	switch cmd.Op {
+	case opCreateGroup:
+		err = f.state.CreateGroup(ctx, cmd.Group, cmd.User)
+	case opJoinGroup:
+		err = f.state.JoinGroup(ctx, cmd.Group, cmd.User)
+	case opLeaveGroup:
+		res.IsDeleted, err = f.state.LeaveGroup(ctx, cmd.Group, cmd.User)
+	case opDeleteGroup:
+		err = f.state.DeleteGroup(ctx, cmd.Group)
+	case opSetPresence:
+		err = f.state.SetPresence(ctx, cmd.User, cmd.Node)
+	case opRemovePresence:
+		err = f.state.RemovePresence(ctx, cmd.User, cmd.Node)
+	case opBlock:
+		err = f.state.Block(ctx, cmd.User, cmd.Blocked)
+	case opUnblock:
+		err = f.state.Unblock(ctx, cmd.User, cmd.Blocked)
+	case opPutKeys:
+		if cmd.Keys == nil {
+			err = errUnknownOp
+			break
+		}
+
+		err = f.state.PutKeys(ctx, cmd.User, *cmd.Keys)
+	case opTakeKeys:
+		var keys entity.KeyBundle
+		if keys, err = f.state.TakeKeys(ctx, cmd.User); err == nil {
+			res.Keys = &keys
+		}
+	default:
+		err = errUnknownOp
+	}
+
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+// Snapshot keeps the applied index along with state, raft calls it between Apply calls, so they match
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	return &fsmSnapshot{Index: f.applied.Load(), State: f.state.snapshot()}, nil
+}
+
+// Restore replaces state by a snapshot, applied index is restored too, so changes the snapshot includes aren't
+// waited for
+func (f *fsm) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+
+	var s fsmSnapshot
+	if err := json.NewDecoder(rc).Decode(&s); err != nil {
+		return err
+	}
+
+	f.state.restore(s.State)
+	f.applied.Store(s.Index)
+
+	return nil
+}
+
+func (s *fsmSnapshot) Persist(sink raft.SnapshotSink) error {
+	if err := json.NewEncoder(sink).Encode(s); err != nil {
+		sink.Cancel()
+		return err
+	}
+
+	return sink.Close()
+}
+
+func (s *fsmSnapshot) Release() {}
@@ -0,0 +1,370 @@
+//go:build unit_tests
+// +build unit_tests
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/hashicorp/raft"
+)
+
+func newTestCluster(t *testing.T, size int) []*raftRegistry {
+	t.Helper()
+
+	nodes, _ := startTestCluster(t, size, func(*raft.Config) {})
+
+	return nodes
+}
+
+// startTestCluster starts replicas connected by in-memory transports, configure adjusts raft config of each one
+func startTestCluster(t *testing.T, size int, configure func(*raft.Config)) ([]*raftRegistry, []*raft.InmemTransport) {
+	t.Helper()
+
+	peers := make([]config.RegistryPeer, size)
+	transports := make([]*raft.InmemTransport, size)
+	for i := range peers {
+		addr, transport := raft.NewInmemTransport("")
+		peers[i] = config.RegistryPeer{ID: fmt.Sprintf("node%d", i), RaftAddr: string(addr)}
+		transports[i] = transport
+	}
+
+	for _, a := range transports {
+		for _, b := range transports {
+			a.Connect(b.LocalAddr(), b)
+		}
+	}
+
+	nodes := make([]*raftRegistry, size)
+	forward := func(ctx context.Context, leaderID string, cmd []byte) (Result, error) {
+		for _, node := range nodes {
+			if node.NodeID() == leaderID {
+				return node.Apply(ctx, cmd)
+			}
+		}
+
+		return Result{}, errNoLeader
+	}
+
+	for i := range nodes {
+		conf := raft.DefaultConfig()
+		conf.LocalID = raft.ServerID(peers[i].ID)
+		conf.LogLevel = "ERROR"
+		conf.HeartbeatTimeout = 50 * time.Millisecond
+		conf.ElectionTimeout = 50 * time.Millisecond
+		conf.LeaderLeaseTimeout = 50 * time.Millisecond
+		conf.CommitTimeout = 5 * time.Millisecond
+		configure(conf)
+
+		node, err := newRaft(conf, peers, time.Second, transports[i], forward)
+		if err != nil {
+			t.Fatalf("failed to start %s: %v", peers[i].ID, err)
+		}
+
+		nodes[i] = node
+		t.Cleanup(func() { node.Close() })
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for nodes[0].raft.Leader() == "" {
+		if time.Now().After(deadline) {
+			t.Fatal("leader is not elected")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return nodes, transports
+}
+
+func follower(nodes []*raftRegistry) *raftRegistry {
+	for _, node := range nodes {
+		if node.raft.State() == raft.Follower {
+			return node
+		}
+	}
+
+	return nil
+}
+
+func Test_RaftRegistry(t *testing.T) {
+	t.Run("test group changes made on any replica are consistent across cluster", func(t *testing.T) {
+		nodes := newTestCluster(t, 3)
+		ctx := context.Background()
+
+		f := follower(nodes)
+		if f == nil {
+			t.Fatal("cluster has no follower")
+		}
+
+		// change made on a follower is forwarded to the leader and visible on the follower right away
+		if err := f.CreateGroup(ctx, "group1", "user1"); err != nil {
+			t.Fatalf("failed to create group on follower: %v", err)
+		}
+
+		groups, err := f.Groups(ctx)
+		if err != nil || len(groups) != 1 || groups[0].Name != "group1" {
+			t.Fatalf("expected group1 on follower, got %v, %v", groups, err)
+		}
+
+		for _, node := range nodes {
+			if err = node.CreateGroup(ctx, "group1", "user2"); !errors.Is(err, entity.ErrGroupExists) {
+				t.Errorf("expected group exists error on %s, got %v", node.NodeID(), err)
+			}
+		}
+
+		if err = nodes[2].JoinGroup(ctx, "group1", "user2"); err != nil {
+			t.Fatalf("failed to join group: %v", err)
+		}
+
+		if err = nodes[1].JoinGroup(ctx, "group1", "user2"); !errors.Is(err, entity.ErrMemberExists) {
+			t.Errorf("expected member exists error, got %v", err)
+		}
+
+		if err = nodes[0].SetPresence(ctx, "user2", nodes[0].NodeID()); err != nil {
+			t.Fatalf("failed to set presence: %v", err)
+		}
+
+		// other replicas catch up asynchronously
+		exp := []string{"user1", "user2"}
+		deadline := time.Now().Add(time.Second)
+		for _, node := range nodes {
+			for {
+				members, _ := node.Members(ctx, "group1")
+				presence, _ := node.Presence(ctx, "user2")
+				if reflect.DeepEqual(members, exp) && presence == nodes[0].NodeID() {
+					break
+				}
+
+				if time.Now().After(deadline) {
+					t.Fatalf("expected members %v on %s, got %v", exp, node.NodeID(), members)
+				}
+
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+
+		isDeleted, err := f.LeaveGroup(ctx, "group1", "user1")
+		if err != nil || isDeleted {
+			t.Fatalf("expected group to remain after first leave, got %v, %v", isDeleted, err)
+		}
+
+		isDeleted, err = f.LeaveGroup(ctx, "group1", "user2")
+		if err != nil || !isDeleted {
+			t.Fatalf("expected group to be deleted after last leave, got %v, %v", isDeleted, err)
+		}
+
+		if _, err = f.Members(ctx, "group1"); !errors.Is(err, entity.ErrGroupNotFound) {
+			t.Errorf("expected group not found error, got %v", err)
+		}
+	})
+
+	t.Run("test every prekey is handed out once across cluster", func(t *testing.T) {
+		nodes := newTestCluster(t, 3)
+		ctx := context.Background()
+
+		keys := entity.KeyBundle{
+			IdentityKey: []byte("identity"),
+			Prekeys:     []entity.Prekey{{ID: "1", PublicKey: []byte("prekey1")}, {ID: "2", PublicKey: []byte("prekey2")}},
+		}
+
+		if _, err := nodes[0].TakeKeys(ctx, "user1"); !errors.Is(err, entity.ErrKeysNotFound) {
+			t.Errorf("expected keys not found error, got %v", err)
+		}
+
+		if err := follower(nodes).PutKeys(ctx, "user1", keys); err != nil {
+			t.Fatalf("failed to put keys: %v", err)
+		}
+
+		// takes made on different replicas go through the leader, so none of them gets a used prekey
+		var ids []string
+		for _, node := range nodes {
+			taken, err := node.TakeKeys(ctx, "user1")
+			if err != nil {
+				t.Fatalf("failed to take keys on %s: %v", node.NodeID(), err)
+			}
+
+			if string(taken.IdentityKey) != "identity" || len(taken.Prekeys) > 1 {
+				t.Fatalf("unexpected keys on %s: %v", node.NodeID(), taken)
+			}
+
+			for _, prekey := range taken.Prekeys {
+				ids = append(ids, prekey.ID)
+			}
+		}
+
+		if exp := []string{"1", "2"}; !reflect.DeepEqual(ids, exp) {
+			t.Errorf("prekeys mismatch: exp: %v, act: %v", exp, ids)
+		}
+	})
+
+	t.Run("test blocklists and presence are shared by cluster", func(t *testing.T) {
+		nodes := newTestCluster(t, 3)
+		ctx := context.Background()
+
+		f := follower(nodes)
+		if f == nil {
+			t.Fatal("cluster has no follower")
+		}
+
+		if err := f.Block(ctx, "user1", "user2"); err != nil {
+			t.Fatalf("failed to block user: %v", err)
+		}
+
+		// errors keep their kind after being forwarded to the leader
+		if err := f.Block(ctx, "user1", "user2"); !errors.Is(err, entity.ErrUserBlocked) {
+			t.Errorf("expected user blocked error, got %v", err)
+		}
+
+		if err := f.Unblock(ctx, "user1", "user3"); !errors.Is(err, entity.ErrUserNotBlocked) {
+			t.Errorf("expected user not blocked error, got %v", err)
+		}
+
+		if err := nodes[0].SetPresence(ctx, "user1", nodes[0].NodeID()); err != nil {
+			t.Fatalf("failed to set presence: %v", err)
+		}
+
+		expConnected := map[string]string{"user1": nodes[0].NodeID()}
+		deadline := time.Now().Add(time.Second)
+		for _, node := range nodes {
+			for {
+				blocked, _ := node.Blocked(ctx, "user1")
+				connected, _ := node.Connected(ctx)
+				if reflect.DeepEqual(blocked, []string{"user2"}) && reflect.DeepEqual(connected, expConnected) {
+					break
+				}
+
+				if time.Now().After(deadline) {
+					t.Fatalf("expected [user2] blocked and %v connected on %s, got %v, %v", expConnected,
+						node.NodeID(), blocked, connected)
+				}
+
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+
+		if err := nodes[2].Unblock(ctx, "user1", "user2"); err != nil {
+			t.Fatalf("failed to unblock user: %v", err)
+		}
+
+		if blocked, _ := nodes[2].Blocked(ctx, "user1"); len(blocked) != 0 {
+			t.Errorf("expected no blocked users, got %v", blocked)
+		}
+	})
+}
+
+func Test_RaftSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	nodes, transports := startTestCluster(t, 3, func(conf *raft.Config) {
+		conf.TrailingLogs = 1
+	})
+
+	var (
+		leader *raftRegistry
+		lagged int
+	)
+	for i, node := range nodes {
+		if node.raft.State() == raft.Leader {
+			leader = node
+		} else {
+			lagged = i
+		}
+	}
+
+	if leader == nil {
+		t.Fatal("cluster has no leader")
+	}
+
+	// the follower misses changes, so the leader sends it a snapshot once logs are compacted
+	transports[lagged].DisconnectAll()
+	for i, transport := range transports {
+		if i != lagged {
+			transport.Disconnect(transports[lagged].LocalAddr())
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := leader.CreateGroup(ctx, fmt.Sprintf("group%d", i), "user1"); err != nil {
+			t.Fatalf("failed to create group: %v", err)
+		}
+	}
+
+	if err := leader.raft.Snapshot().Error(); err != nil {
+		t.Fatalf("failed to take snapshot: %v", err)
+	}
+
+	snapshotIndex := leader.fsm.applied.Load()
+
+	for _, a := range transports {
+		for _, b := range transports {
+			a.Connect(b.LocalAddr(), b)
+		}
+	}
+
+	restored := nodes[lagged]
+
+	deadline := time.Now().Add(5 * time.Second)
+	for restored.fsm.applied.Load() < snapshotIndex {
+		if time.Now().After(deadline) {
+			t.Fatalf("applied index of restored replica mismatch: exp: %d, act: %d", snapshotIndex,
+				restored.fsm.applied.Load())
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if groups, _ := restored.Groups(ctx); len(groups) != 10 {
+		t.Errorf("groups amount mismatch: exp: 10, act: %d", len(groups))
+	}
+
+	// a change included in the snapshot is not waited for
+	if err := restored.waitApplied(ctx, snapshotIndex); err != nil {
+		t.Errorf("failed to wait for a change included in snapshot: %v", err)
+	}
+
+	if restored.raft.State() != raft.Follower {
+		t.Fatalf("restored replica is not a follower: %s", restored.raft.State())
+	}
+
+	if err := restored.JoinGroup(ctx, "group0", "user2"); err != nil {
+		t.Fatalf("failed to forward change: %v", err)
+	}
+
+	if members, _ := restored.Members(ctx, "group0"); !reflect.DeepEqual(members, []string{"user1", "user2"}) {
+		t.Errorf("members mismatch: exp: [user1 user2], act: %v", members)
+	}
+}
+
+func Test_LocalSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	l := NewLocal("node1")
+	l.CreateGroup(ctx, "group1", "user1")
+	l.SetPresence(ctx, "user1", "node1")
+	l.Block(ctx, "user1", "user3")
+	l.Block(ctx, "user1", "user2")
+	l.PutKeys(ctx, "user1", entity.KeyBundle{IdentityKey: []byte("identity")})
+
+	restored := NewLocal("node2")
+	restored.restore(l.snapshot())
+
+	if members, _ := restored.Members(ctx, "group1"); !reflect.DeepEqual(members, []string{"user1"}) {
+		t.Errorf("members mismatch: exp: [user1], act: %v", members)
+	}
+
+	if node, _ := restored.Presence(ctx, "user1"); node != "node1" {
+		t.Errorf("presence mismatch: exp: node1, act: %q", node)
+	}
+
+	if blocked, _ := restored.Blocked(ctx, "user1"); !reflect.DeepEqual(blocked, []string{"user2", "user3"}) {
+		t.Errorf("blocked users mismatch: exp: [user2 user3], act: %v", blocked)
+	}
+}
@@ -0,0 +1,124 @@
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/hashicorp/raft"
+)
+
+const (
+	DriverLocal = "local"
+	DriverRaft  = "raft"
+
+	transportMaxPool = 3
+)
+
+var errNoClusterTLS = errors.New("raft registry requires certificate, key and CA files of registry tls")
+
+// ICertificates provides mutual TLS configs of replicas, server one requires and verifies client certificates
+type ICertificates interface {
+	Config() *tls.Config
+	PeerConfig() *tls.Config
+}
+
+// Registry keeps groups, user presence, blocklists and public keys shared by replicas
+type Registry interface {
+	NodeID() string
+	CreateGroup(ctx context.Context, group, owner string) error
+	JoinGroup(ctx context.Context, group, user string) error
+	LeaveGroup(ctx context.Context, group, user string) (bool, error)
+	DeleteGroup(ctx context.Context, group string) error
+	Groups(ctx context.Context) (entity.Channels, error)
+	Members(ctx context.Context, group string) ([]string, error)
+	SetPresence(ctx context.Context, user, node string) error
+	RemovePresence(ctx context.Context, user, node string) error
+	Presence(ctx context.Context, user string) (string, error)
+	Connected(ctx context.Context) (map[string]string, error)
+	Block(ctx context.Context, user, blocked string) error
+	Unblock(ctx context.Context, user, blocked string) error
+	Blocked(ctx context.Context, user string) ([]string, error)
+	PutKeys(ctx context.Context, user string, keys entity.KeyBundle) error
+	TakeKeys(ctx context.Context, user string) (entity.KeyBundle, error)
+	Close() error
+}
+
+// New creates a registry of the configured driver, local registry is used by default. Raft registry listens
+// on raft and api addresses of the peer with its node id, both are served over mutual TLS of certificates
+func New(cfg config.Registry, certificates ICertificates) (Registry, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocal(cfg.NodeID), nil
+
+	case DriverRaft:
+		if certificates == nil || cfg.TLS.CertFile == "" || cfg.TLS.KeyFile == "" || cfg.TLS.CAFile == "" {
+			return nil, errNoClusterTLS
+		}
+
+		return newRaftWithTLS(cfg, certificates)
+	}
+
+	return nil, fmt.Errorf("unknown registry driver %q", cfg.Driver)
+}
+
+func newRaftWithTLS(cfg config.Registry, certificates ICertificates) (*raftRegistry, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	var self *config.RegistryPeer
+	for i := range cfg.Peers {
+		if cfg.Peers[i].ID == cfg.NodeID {
+			self = &cfg.Peers[i]
+		}
+	}
+
+	if self == nil {
+		return nil, fmt.Errorf("node %q is not found in registry peers", cfg.NodeID)
+	}
+
+	stream, err := newTLSStreamLayer(self.RaftAddr, certificates)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := raft.NewNetworkTransport(stream, transportMaxPool, cfg.Timeout, os.Stderr)
+
+	client := &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: &http.Transport{TLSClientConfig: certificates.PeerConfig()},
+	}
+
+	r, err := NewRaft(cfg, transport, HTTPForwarder(cfg.Peers, client))
+	if err != nil {
+		transport.Close()
+		return nil, err
+	}
+
+	listener, err := tls.Listen("tcp", self.APIAddr, certificates.Config())
+	if err != nil {
+		r.Close()
+		transport.Close()
+		return nil, err
+	}
+
+	srv := &http.Server{Handler: Handler(r), ReadHeaderTimeout: 5 * time.Second}
+	go srv.Serve(listener)
+
+	r.closers = append(r.closers, transport.Close, func() error {
+		if err := srv.Close(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+
+		return nil
+	})
+
+	return r, nil
+}
@@ -0,0 +1,48 @@
+package registry
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// tlsStreamLayer carries raft transport over mutual TLS, so only replicas holding a certificate of cluster CA
+// take part in the cluster
+type tlsStreamLayer struct {
+	net.Listener
+	advertise net.Addr
+	peer      *tls.Config
+}
+
+func newTLSStreamLayer(addr string, certificates ICertificates) (*tlsStreamLayer, error) {
+	advertise, err := net.ResolveTCPAddr("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := tls.Listen("tcp", addr, certificates.Config())
+	if err != nil {
+		return nil, err
+	}
+
+	return &tlsStreamLayer{Listener: listener, advertise: advertise, peer: certificates.PeerConfig()}, nil
+}
+
+// Dial connects to a replica, its certificate is verified for the host of address
+func (l *tlsStreamLayer) Dial(address raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(string(address))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := l.peer.Clone()
+	cfg.ServerName = host
+
+	return tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", string(address), cfg)
+}
+
+func (l *tlsStreamLayer) Addr() net.Addr {
+	return l.advertise
+}
@@ -0,0 +1,302 @@
+//go:build unit_tests
+// +build unit_tests
+
+package registry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/certs"
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/hashicorp/raft"
+	"go.uber.org/zap"
+)
+
+// testCA issues replica certificates
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string
+}
+
+func newTestCA(t *testing.T, dir, name string) *testCA {
+	t.Helper()
+
+	ca := &testCA{key: newTestKey(t), file: filepath.Join(dir, name+".pem")}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &ca.key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+
+	if ca.cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatalf("failed to parse CA certificate: %v", err)
+	}
+
+	writePEM(t, ca.file, "CERTIFICATE", der)
+
+	return ca
+}
+
+// issue writes a replica certificate valid for localhost, it's both server and client one
+func (ca *testCA) issue(t *testing.T, dir, name string) config.RegistryTLS {
+	t.Helper()
+
+	key := newTestKey(t)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	res := config.RegistryTLS{
+		CertFile: filepath.Join(dir, name+".pem"),
+		KeyFile:  filepath.Join(dir, name+"-key.pem"),
+		CAFile:   ca.file,
+	}
+
+	writePEM(t, res.KeyFile, "EC PRIVATE KEY", keyDER)
+	writePEM(t, res.CertFile, "CERTIFICATE", der)
+
+	return res
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	return key
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func newReloader(t *testing.T, cfg config.RegistryTLS) ICertificates {
+	t.Helper()
+
+	r, err := certs.NewReloader(config.TLS{CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, ClientCAFile: cfg.CAFile}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to load certificates: %v", err)
+	}
+
+	return r
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer l.Close()
+
+	return l.Addr().String()
+}
+
+func Test_ClusterTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newTestCA(t, dir, "cluster-ca")
+	replica := ca.issue(t, dir, "node1")
+	rogue := newTestCA(t, dir, "rogue-ca").issue(t, dir, "rogue")
+
+	replicaCerts := newReloader(t, replica)
+
+	// rogue client trusts cluster CA, but presents a certificate of another CA
+	rogueCert, err := tls.LoadX509KeyPair(rogue.CertFile, rogue.KeyFile)
+	if err != nil {
+		t.Fatalf("failed to load certificate: %v", err)
+	}
+
+	clusterPool := x509.NewCertPool()
+	clusterPool.AddCert(ca.cert)
+
+	rogueConfig := &tls.Config{Certificates: []tls.Certificate{rogueCert}, RootCAs: clusterPool, MinVersion: tls.VersionTLS12}
+
+	t.Run("test raft driver requires tls", func(t *testing.T) {
+		cfg := config.Registry{Driver: DriverRaft, NodeID: "node1", TLS: replica}
+
+		if _, err := New(cfg, nil); !errors.Is(err, errNoClusterTLS) {
+			t.Errorf("error mismatch: exp: %v, act: %v", errNoClusterTLS, err)
+		}
+
+		cfg.TLS.CAFile = ""
+		if _, err := New(cfg, replicaCerts); !errors.Is(err, errNoClusterTLS) {
+			t.Errorf("error mismatch: exp: %v, act: %v", errNoClusterTLS, err)
+		}
+	})
+
+	t.Run("test raft transport accepts replicas only", func(t *testing.T) {
+		server, err := newTLSStreamLayer("127.0.0.1:0", replicaCerts)
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		defer server.Close()
+
+		received := make(chan error, 1)
+		go func() {
+			for {
+				conn, err := server.Accept()
+				if err != nil {
+					return
+				}
+
+				buf := make([]byte, 1)
+				_, err = io.ReadFull(conn, buf)
+				conn.Close()
+				received <- err
+			}
+		}()
+
+		addr := raft.ServerAddress(server.Listener.Addr().String())
+
+		client, err := newTLSStreamLayer("127.0.0.1:0", replicaCerts)
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		defer client.Close()
+
+		conn, err := client.Dial(addr, time.Second)
+		if err != nil {
+			t.Fatalf("failed to dial replica: %v", err)
+		}
+		defer conn.Close()
+
+		if _, err = conn.Write([]byte("x")); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+
+		if err = <-received; err != nil {
+			t.Errorf("replica connection is rejected: %v", err)
+		}
+
+		conn, err = tls.Dial("tcp", string(addr), rogueConfig)
+		if err == nil {
+			conn.Write([]byte("x"))
+			defer conn.Close()
+		}
+
+		if err = <-received; err == nil {
+			t.Error("expected connection of a certificate of another CA to be rejected")
+		}
+	})
+
+	t.Run("test registry changes are forwarded by replicas only", func(t *testing.T) {
+		cfg := config.Registry{
+			Driver:  DriverRaft,
+			NodeID:  "node1",
+			Peers:   []config.RegistryPeer{{ID: "node1", RaftAddr: freeAddr(t), APIAddr: freeAddr(t)}},
+			Timeout: 2 * time.Second,
+			TLS:     replica,
+		}
+
+		reg, err := New(cfg, replicaCerts)
+		if err != nil {
+			t.Fatalf("failed to start registry: %v", err)
+		}
+		defer reg.Close()
+
+		deadline := time.Now().Add(10 * time.Second)
+		for reg.(*raftRegistry).raft.State() != raft.Leader {
+			if time.Now().After(deadline) {
+				t.Fatal("leader is not elected")
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		ctx := context.Background()
+		cmd, _ := json.Marshal(command{Op: opCreateGroup, Group: "group1", User: "user1"})
+
+		forward := HTTPForwarder(cfg.Peers, &http.Client{
+			Timeout:   time.Second,
+			Transport: &http.Transport{TLSClientConfig: replicaCerts.PeerConfig()},
+		})
+
+		res, err := forward(ctx, "node1", cmd)
+		if err != nil || res.Error != "" {
+			t.Fatalf("failed to forward change: %v %s", err, res.Error)
+		}
+
+		if members, _ := reg.Members(ctx, "group1"); len(members) != 1 {
+			t.Errorf("expected forwarded group to be created, got members %v", members)
+		}
+
+		clients := map[string]*tls.Config{
+			"without certificate":            {RootCAs: clusterPool, MinVersion: tls.VersionTLS12},
+			"with certificate of another CA": rogueConfig,
+		}
+
+		for name, tlsConfig := range clients {
+			forward := HTTPForwarder(cfg.Peers, &http.Client{
+				Timeout:   time.Second,
+				Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			})
+
+			if _, err = forward(ctx, "node1", cmd); err == nil {
+				t.Errorf("expected change forwarded %s to be rejected", name)
+			}
+		}
+
+		resp, err := http.Post("http://"+cfg.Peers[0].APIAddr+applyPath, "application/json", nil)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				t.Error("expected plain http change to be rejected")
+			}
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const idLen = 16
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying request id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns request id stored in ctx, empty string if there is none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// New generates a random request id
+func New() string {
+	b := make([]byte, idLen)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
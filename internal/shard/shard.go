@@ -0,0 +1,114 @@
+// Package shard provides a map split into independently locked shards, so operations on unrelated keys
+// proceed in parallel instead of waiting for a single lock
+package shard
+
+import (
+	"sync"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+)
+
+// count is an amount of shards, it must be a power of two
+const count = 64
+
+type (
+	Map[V any] struct {
+		shards [count]shard[V]
+	}
+
+	shard[V any] struct {
+		mu    sync.RWMutex
+		items map[string]V
+	}
+)
+
+func New[V any]() *Map[V] {
+	m := &Map[V]{}
+	for i := range m.shards {
+		m.shards[i].items = make(map[string]V)
+	}
+
+	return m
+}
+
+// With runs fn holding a lock of the shard the key belongs to. fn is given the shard items,
+// it must access the key only
+func (m *Map[V]) With(key string, safe entity.Lock, fn func(items map[string]V) error) error {
+	s := &m.shards[index(key)]
+
+	switch safe {
+	case entity.SafeRead:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	default:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+
+	return fn(s.items)
+}
+
+// Range calls fn for every item until fn returns false. Shards are locked one by one,
+// so the items don't form a snapshot of the whole map
+func (m *Map[V]) Range(fn func(key string, value V) bool) {
+	for i := range m.shards {
+		if !m.shards[i].rangeItems(fn) {
+			return
+		}
+	}
+}
+
+// Len provides amount of items
+func (m *Map[V]) Len() int {
+	var n int
+
+	for i := range m.shards {
+		s := &m.shards[i]
+
+		s.mu.RLock()
+		n += len(s.items)
+		s.mu.RUnlock()
+	}
+
+	return n
+}
+
+// Clear removes all items
+func (m *Map[V]) Clear() {
+	for i := range m.shards {
+		s := &m.shards[i]
+
+		s.mu.Lock()
+		s.items = make(map[string]V)
+		s.mu.Unlock()
+	}
+}
+
+func (s *shard[V]) rangeItems(fn func(key string, value V) bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for key, value := range s.items {
+		if !fn(key, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// index hashes the key with 32-bit FNV-1a, which doesn't allocate unlike hash/fnv
+func index(key string) int {
+	const (
+		offset = 2166136261
+		prime  = 16777619
+	)
+
+	h := uint32(offset)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= prime
+	}
+
+	return int(h & (count - 1))
+}
@@ -0,0 +1,63 @@
+//go:build unit_tests
+// +build unit_tests
+
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"go.opentelemetry.io/otel"
+)
+
+func Test_New(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("test file exporter writes spans on shutdown", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+		shutdown, err := New(ctx, config.Tracing{Exporter: ExporterFile, File: path}, "chat-test")
+		if err != nil {
+			t.Fatalf("failed to create tracer provider: %v", err)
+		}
+
+		_, span := otel.Tracer("test").Start(ctx, "test.Span")
+		span.End()
+
+		if err = shutdown(ctx); err != nil {
+			t.Fatalf("failed to shut down tracer provider: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read traces: %v", err)
+		}
+
+		for _, exp := range []string{`"Name":"test.Span"`, `"Value":"chat-test"`} {
+			if !strings.Contains(string(data), exp) {
+				t.Errorf("traces don't contain %s: %s", exp, data)
+			}
+		}
+	})
+
+	t.Run("test disabled exporter", func(t *testing.T) {
+		shutdown, err := New(ctx, config.Tracing{Exporter: ExporterNone}, "chat-test")
+		if err != nil {
+			t.Fatalf("failed to create tracer provider: %v", err)
+		}
+
+		if err = shutdown(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("test unknown exporter", func(t *testing.T) {
+		if _, err := New(ctx, config.Tracing{Exporter: "jaeger"}, "chat-test"); err == nil {
+			t.Error("expected unknown exporter to fail")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"go.uber.org/zap"
+)
+
+var errUserIsNotConnected = fmt.Errorf("%w: user is not connected", entity.ErrNotFound)
+
+// ListSessions provides open sessions of users connected to any replica ordered by user name, connection time
+// and queue depth are known for sessions of this replica only
+func (c *chat) ListSessions(ctx context.Context) (_ []entity.SessionInfo, err error) {
+	ctx, span := tracer.Start(ctx, "chat.ListSessions")
+	defer func() { endSpan(span, err) }()
+
+	connected, err := c.registry.Connected(ctx)
+	if err != nil {
+		c.log.Error("failed to list sessions", zap.Error(err))
+		return nil, err
+	}
+
+	nodeID := c.registry.NodeID()
+	res := make([]entity.SessionInfo, 0, len(connected))
+
+	c.connPipe.Range(func(user string, conn *connection) bool {
+		info := conn.Info()
+		info.Node = nodeID
+		res = append(res, info)
+		delete(connected, user)
+
+		return true
+	})
+
+	for user, node := range connected {
+		// the user is leaving this replica, presence is not removed yet
+		if node == nodeID {
+			continue
+		}
+
+		res = append(res, entity.SessionInfo{User: user, Node: node})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].User < res[j].User })
+
+	return res, ctx.Err()
+}
+
+// DisconnectUser ends the user session on any replica, the user is able to connect again
+func (c *chat) DisconnectUser(ctx context.Context, userName, adminName string) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.DisconnectUser")
+	defer func() { endSpan(span, err) }()
+
+	if err := c.broker.Publish(ctx, userTopic(userName), entity.Message{Kind: entity.Disconnected}); err != nil {
+		if errors.Is(err, entity.ErrNotFound) {
+			err = errUserIsNotConnected
+		}
+
+		c.log.Error("failed to disconnect user", zap.Error(err))
+		return err
+	}
+
+	c.recordAudit(ctx, entity.AuditUserDisconnect, adminName, userName)
+
+	return ctx.Err()
+}
+
+// GetGroup provides group members along with their connection state
+func (c *chat) GetGroup(ctx context.Context, channelName string) (_ entity.GroupInfo, err error) {
+	ctx, span := tracer.Start(ctx, "chat.GetGroup")
+	defer func() { endSpan(span, err) }()
+
+	members, err := c.registry.Members(ctx, channelName)
+	if err != nil {
+		c.log.Error("failed to get group", zap.Error(err))
+		return entity.GroupInfo{}, err
+	}
+
+	res := entity.GroupInfo{Name: channelName, Members: make([]entity.MemberInfo, len(members))}
+	for i, member := range members {
+		node, err := c.registry.Presence(ctx, member)
+		if err != nil {
+			return entity.GroupInfo{}, err
+		}
+
+		res.Members[i] = entity.MemberInfo{User: member, Node: node, Connected: node != ""}
+	}
+
+	// queue depth is known for users connected to this replica only
+	for i := range res.Members {
+		if session := c.isUserConnected(res.Members[i].User); session != nil {
+			res.Members[i].QueueDepth = len(session.Queue)
+		}
+	}
+
+	return res, ctx.Err()
+}
+
+// DeleteGroup removes a group channel regardless of its members
+func (c *chat) DeleteGroup(ctx context.Context, channelName, adminName string) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.DeleteGroup")
+	defer func() { endSpan(span, err) }()
+
+	if err := c.registry.DeleteGroup(ctx, channelName); err != nil {
+		c.log.Error("failed to delete group", zap.Error(err))
+		return err
+	}
+
+	c.updateActiveGroups(ctx)
+	c.recordAudit(ctx, entity.AuditGroupDelete, adminName, channelName)
+
+	return ctx.Err()
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"go.uber.org/zap"
+)
+
+const (
+	// announcementsTopic and cancelledAnnouncementsTopic are subscribed by every replica, so announcements reach
+	// users connected to any of them
+	announcementsTopic          = "announcements"
+	cancelledAnnouncementsTopic = "announcements.cancelled"
+)
+
+var (
+	errAnnouncementNotFound  = fmt.Errorf("%w: announcement was not found", entity.ErrNotFound)
+	errInvalidValidityWindow = fmt.Errorf("%w: announcement expires before it starts", entity.ErrInvalidArgument)
+)
+
+// announcement is a scheduled announcement along with its delivery or expiration timer
+type announcement struct {
+	entity.Announcement
+	// started is set once the announcement is delivered to connected users
+	started bool
+	timer   *time.Timer
+}
+
+// Broadcast schedules an announcement for every connected user, users connecting within its validity window
+// receive it as well. Users with a full queue miss the announcement
+func (c *chat) Broadcast(ctx context.Context, a entity.Announcement) (_ entity.Announcement, err error) {
+	ctx, span := tracer.Start(ctx, "chat.Broadcast")
+	defer func() { endSpan(span, err) }()
+
+	if err := c.limits.checkMessage(announcementMessage(a)); err != nil {
+		return entity.Announcement{}, err
+	}
+
+	now := time.Now().UTC()
+	if a.StartAt.Before(now) {
+		a.StartAt = now
+	}
+
+	if a.ExpireAt.IsZero() {
+		a.ExpireAt = a.StartAt
+	}
+
+	if a.ExpireAt.Before(a.StartAt) {
+		return entity.Announcement{}, errInvalidValidityWindow
+	}
+
+	if a.ID, err = newID(); err != nil {
+		return entity.Announcement{}, err
+	}
+
+	if err = c.broker.Publish(ctx, announcementsTopic, entity.Message{Announcement: &a}); err != nil {
+		c.log.Error("failed to broadcast announcement", zap.Error(err))
+		return entity.Announcement{}, err
+	}
+
+	c.recordAudit(ctx, entity.AuditAnnouncementCreate, a.Author, a.ID)
+
+	return a, ctx.Err()
+}
+
+// ListAnnouncements provides scheduled and active announcements ordered by start time
+func (c *chat) ListAnnouncements(ctx context.Context) (_ []entity.Announcement, err error) {
+	ctx, span := tracer.Start(ctx, "chat.ListAnnouncements")
+	defer func() { endSpan(span, err) }()
+
+	res := make([]entity.Announcement, 0)
+
+	c.withSafeFunc(c.mu, entity.SafeRead, func() error {
+		for _, a := range c.announcements {
+			res = append(res, a.Announcement)
+		}
+
+		return nil
+	})
+
+	sort.Slice(res, func(i, j int) bool { return res[i].StartAt.Before(res[j].StartAt) })
+
+	return res, ctx.Err()
+}
+
+// CancelAnnouncement stops a scheduled announcement or ends validity window of an active one
+func (c *chat) CancelAnnouncement(ctx context.Context, id, adminName string) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.CancelAnnouncement")
+	defer func() { endSpan(span, err) }()
+
+	// every replica keeps the same announcements, so the local ones tell whether it exists
+	if err := c.withSafeFunc(c.mu, entity.SafeRead, func() error {
+		if _, ok := c.announcements[id]; !ok {
+			return errAnnouncementNotFound
+		}
+
+		return nil
+	}); err != nil {
+		c.log.Error("failed to cancel announcement", zap.Error(err))
+		return err
+	}
+
+	msg := entity.Message{Announcement: &entity.Announcement{ID: id}}
+	if err := c.broker.Publish(ctx, cancelledAnnouncementsTopic, msg); err != nil {
+		c.log.Error("failed to cancel announcement", zap.Error(err))
+		return err
+	}
+
+	c.recordAudit(ctx, entity.AuditAnnouncementCancel, adminName, id)
+
+	return ctx.Err()
+}
+
+// subscribeAnnouncements lets the replica receive announcement changes made through any replica
+func (c *chat) subscribeAnnouncements() error {
+	if _, err := c.broker.Subscribe(announcementsTopic, func(msg entity.Message) {
+		if msg.Announcement != nil {
+			c.addAnnouncement(*msg.Announcement)
+		}
+	}); err != nil {
+		return err
+	}
+
+	_, err := c.broker.Subscribe(cancelledAnnouncementsTopic, func(msg entity.Message) {
+		if msg.Announcement != nil {
+			c.removeAnnouncement(msg.Announcement.ID)
+		}
+	})
+
+	return err
+}
+
+// addAnnouncement keeps an announcement and schedules its delivery to users of the replica, every replica keeps
+// its own copy, so announcements survive a restart of any of them
+func (c *chat) addAnnouncement(a entity.Announcement) {
+	// an announcement delivered right away to connected users only has nothing to keep
+	if c.announcementStore != nil && !a.IsExpired(time.Now()) {
+		if err := c.announcementStore.Save(context.Background(), a); err != nil {
+			c.log.Error("failed to save announcement", zap.Error(err), zap.String("announcement", a.ID))
+		}
+	}
+
+	c.withSafeFunc(c.mu, entity.SafeWrite, func() error {
+		if _, ok := c.announcements[a.ID]; !ok {
+			c.scheduleAnnouncement(a)
+		}
+
+		return nil
+	})
+}
+
+// removeAnnouncement stops a scheduled announcement or ends validity window of an active one
+func (c *chat) removeAnnouncement(id string) {
+	c.withSafeFunc(c.mu, entity.SafeWrite, func() error {
+		if a, ok := c.announcements[id]; ok {
+			a.timer.Stop()
+			delete(c.announcements, id)
+		}
+
+		return nil
+	})
+
+	c.deleteStoredAnnouncement(id)
+}
+
+// loadAnnouncements schedules announcements kept before restart, expired ones are removed
+func (c *chat) loadAnnouncements(ctx context.Context) error {
+	if c.announcementStore == nil {
+		return nil
+	}
+
+	items, err := c.announcementStore.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, a := range items {
+		if a.IsExpired(now) {
+			c.deleteStoredAnnouncement(a.ID)
+			continue
+		}
+
+		c.scheduleAnnouncement(a)
+	}
+
+	return nil
+}
+
+// scheduleAnnouncement must be called with write lock held
+func (c *chat) scheduleAnnouncement(a entity.Announcement) {
+	c.announcements[a.ID] = &announcement{
+		Announcement: a,
+		timer:        time.AfterFunc(time.Until(a.StartAt), func() { c.startAnnouncement(a.ID) }),
+	}
+}
+
+// startAnnouncement delivers an announcement to connected users, then waits for its validity window to end
+func (c *chat) startAnnouncement(id string) {
+	var isExpired bool
+
+	c.withSafeFunc(c.mu, entity.SafeWrite, func() error {
+		a, ok := c.announcements[id]
+		if !ok {
+			return nil
+		}
+
+		a.started = true
+		c.connPipe.Range(func(_ string, conn *connection) bool {
+			c.pushAnnouncement(conn.Session, a.Announcement)
+			return true
+		})
+
+		if a.IsExpired(time.Now()) {
+			delete(c.announcements, id)
+			isExpired = true
+
+			return nil
+		}
+
+		a.timer = time.AfterFunc(time.Until(a.ExpireAt), func() { c.expireAnnouncement(id) })
+
+		return nil
+	})
+
+	if isExpired {
+		c.deleteStoredAnnouncement(id)
+	}
+}
+
+func (c *chat) expireAnnouncement(id string) {
+	c.withSafeFunc(c.mu, entity.SafeWrite, func() error {
+		delete(c.announcements, id)
+
+		return nil
+	})
+
+	c.deleteStoredAnnouncement(id)
+}
+
+// pushActiveAnnouncements delivers announcements within validity window to a new session,
+// it must be called with mu held
+func (c *chat) pushActiveAnnouncements(session *entity.Session) {
+	now := time.Now()
+
+	for _, a := range c.announcements {
+		if a.started && !a.IsExpired(now) {
+			c.pushAnnouncement(session, a.Announcement)
+		}
+	}
+}
+
+func (c *chat) pushAnnouncement(session *entity.Session, a entity.Announcement) {
+	select {
+	case session.Queue <- announcementMessage(a):
+	default:
+		c.log.Warn("failed to deliver announcement, queue is full",
+			zap.String("user", session.User), zap.String("announcement", a.ID))
+		c.metrics.DeliveryDropped()
+	}
+}
+
+func (c *chat) deleteStoredAnnouncement(id string) {
+	if c.announcementStore == nil {
+		return
+	}
+
+	if err := c.announcementStore.Delete(context.Background(), id); err != nil {
+		c.log.Error("failed to delete announcement", zap.Error(err), zap.String("announcement", id))
+	}
+}
+
+func announcementMessage(a entity.Announcement) entity.Message {
+	return entity.Message{
+		Message:     a.Message,
+		ContentType: entity.PlainText,
+		Kind:        entity.SystemAnnouncement,
+	}
+}
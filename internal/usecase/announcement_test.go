@@ -0,0 +1,181 @@
+//go:build unit_tests
+// +build unit_tests
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	announcementStore "github.com/ITheCorgi/grpc-chat-room/internal/announcement"
+	"github.com/ITheCorgi/grpc-chat-room/internal/backlog"
+	"github.com/ITheCorgi/grpc-chat-room/internal/broker"
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/registry"
+	"go.uber.org/zap"
+)
+
+func Test_Announcements(t *testing.T) {
+	ctx := context.Background()
+
+	newChat := func(t *testing.T, path string) (*chat, IAnnouncementStore) {
+		t.Helper()
+
+		store, err := announcementStore.NewFile(path, nil)
+		if err != nil {
+			t.Fatalf("failed to create announcement store: %v", err)
+		}
+
+		c, err := New(config.Chat{}, registry.NewLocal("test"), broker.NewLocal(), nil, noopMetrics{}, nil, store, nil,
+			backlog.NewMemory(backlogLimit), zap.NewNop())
+		if err != nil {
+			t.Fatalf("failed to create chat: %v", err)
+		}
+
+		return c, store
+	}
+
+	t.Run("test announcement is delivered at start and to users connecting within its window", func(t *testing.T) {
+		c, store := newChat(t, filepath.Join(t.TempDir(), "announcements.json"))
+
+		early, err := c.Connect(ctx, "user1")
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer early.Close()
+
+		now := time.Now().UTC()
+		a, err := c.Broadcast(ctx, entity.Announcement{
+			Message:  "maintenance",
+			Author:   "admin",
+			StartAt:  now.Add(50 * time.Millisecond),
+			ExpireAt: now.Add(300 * time.Millisecond),
+		})
+		if err != nil {
+			t.Fatalf("failed to broadcast: %v", err)
+		}
+
+		select {
+		case msg := <-early.Queue:
+			t.Fatalf("announcement is delivered before start: %v", msg)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		expectAnnouncement(t, early, a.Message)
+
+		late, err := c.Connect(ctx, "user2")
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer late.Close()
+
+		expectAnnouncement(t, late, a.Message)
+
+		time.Sleep(350 * time.Millisecond)
+
+		if items, _ := c.ListAnnouncements(ctx); len(items) != 0 {
+			t.Errorf("expected expired announcement to be removed, got %v", items)
+		}
+
+		if items, _ := store.List(ctx); len(items) != 0 {
+			t.Errorf("expected expired announcement to be deleted from store, got %v", items)
+		}
+
+		afterExpiry, err := c.Connect(ctx, "user3")
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer afterExpiry.Close()
+
+		select {
+		case msg := <-afterExpiry.Queue:
+			t.Errorf("expired announcement is delivered: %v", msg)
+		case <-time.After(20 * time.Millisecond):
+		}
+	})
+
+	t.Run("test scheduled announcement survives restart until cancelled", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "announcements.json")
+		c, _ := newChat(t, path)
+
+		start := time.Now().UTC().Add(time.Hour)
+		a, err := c.Broadcast(ctx, entity.Announcement{Message: "release", Author: "admin", StartAt: start, ExpireAt: start.Add(time.Hour)})
+		if err != nil {
+			t.Fatalf("failed to broadcast: %v", err)
+		}
+
+		restarted, store := newChat(t, path)
+
+		items, err := restarted.ListAnnouncements(ctx)
+		if err != nil {
+			t.Fatalf("failed to list announcements: %v", err)
+		}
+
+		if len(items) != 1 || items[0].ID != a.ID || !items[0].StartAt.Equal(a.StartAt) {
+			t.Fatalf("announcements mismatch after restart: exp: [%v], act: %v", a, items)
+		}
+
+		if err = restarted.CancelAnnouncement(ctx, a.ID, "admin"); err != nil {
+			t.Fatalf("failed to cancel announcement: %v", err)
+		}
+
+		if items, _ = store.List(ctx); len(items) != 0 {
+			t.Errorf("expected cancelled announcement to be deleted from store, got %v", items)
+		}
+
+		if err = restarted.CancelAnnouncement(ctx, a.ID, "admin"); !errors.Is(err, entity.ErrNotFound) {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("test expired announcements are dropped on start", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "announcements.json")
+
+		store, err := announcementStore.NewFile(path, nil)
+		if err != nil {
+			t.Fatalf("failed to create announcement store: %v", err)
+		}
+
+		past := time.Now().UTC().Add(-time.Hour)
+		if err = store.Save(ctx, entity.Announcement{ID: "a1", Message: "old", StartAt: past, ExpireAt: past}); err != nil {
+			t.Fatalf("failed to save announcement: %v", err)
+		}
+
+		c, _ := newChat(t, path)
+
+		if items, _ := c.ListAnnouncements(ctx); len(items) != 0 {
+			t.Errorf("expected no announcements, got %v", items)
+		}
+
+		if items, _ := store.List(ctx); len(items) != 0 {
+			t.Errorf("expected expired announcement to be deleted from store, got %v", items)
+		}
+	})
+
+	t.Run("test invalid validity window is rejected", func(t *testing.T) {
+		c, _ := newChat(t, filepath.Join(t.TempDir(), "announcements.json"))
+
+		start := time.Now().UTC().Add(time.Hour)
+		_, err := c.Broadcast(ctx, entity.Announcement{Message: "oops", StartAt: start, ExpireAt: start.Add(-time.Minute)})
+		if !errors.Is(err, entity.ErrInvalidArgument) {
+			t.Errorf("expected invalid argument error, got %v", err)
+		}
+	})
+}
+
+func expectAnnouncement(t *testing.T, session *entity.Session, text string) {
+	t.Helper()
+
+	select {
+	case msg := <-session.Queue:
+		if msg.Kind != entity.SystemAnnouncement || msg.Message != text {
+			t.Errorf("announcement mismatch: exp: %q, act: %v", text, msg)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("announcement %q is not delivered", text)
+	}
+}
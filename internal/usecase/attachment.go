@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"go.uber.org/zap"
+)
+
+const (
+	idLen    = 16
+	sniffLen = 512
+)
+
+var (
+	errAttachmentNotFound      = fmt.Errorf("%w: attachment was not found", entity.ErrNotFound)
+	errAttachmentAccessDenied  = fmt.Errorf("%w: attachment is posted to a channel user has no access to", entity.ErrPermissionDenied)
+	errAttachmentTooLarge      = fmt.Errorf("%w: attachment exceeds max size", entity.ErrInvalidArgument)
+	errAttachmentMimeType      = fmt.Errorf("%w: attachment media type is not allowed", entity.ErrInvalidArgument)
+	errAttachmentDestination   = fmt.Errorf("%w: attachment is posted to another channel", entity.ErrInvalidArgument)
+	errAttachmentsNotAvailable = errors.New("attachments storage is not configured")
+)
+
+// UploadAttachment stores attachment content read from r, returns attachment id
+func (c *chat) UploadAttachment(ctx context.Context, attachment entity.Attachment, userName string, r io.Reader) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "chat.UploadAttachment")
+	defer func() { endSpan(span, err) }()
+
+	if err := c.checkParticipants(attachment.To, attachment.ChatType, userName); err != nil {
+		return "", err
+	}
+
+	if c.blobs == nil {
+		return "", errAttachmentsNotAvailable
+	}
+
+	mimeType, err := c.checkMimeType(attachment.MimeType)
+	if err != nil {
+		return "", err
+	}
+
+	if attachment.ChatType == entity.OneToMany {
+		members, err := c.registry.Members(ctx, attachment.To)
+		if err == nil && !isMember(members, userName) {
+			err = errUserNotFound
+		}
+
+		if err != nil {
+			c.log.Error("failed to upload attachment", zap.Error(err))
+			return "", err
+		}
+	}
+
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	attachment.Owner = userName
+
+	size, err := c.storeAttachment(ctx, attachmentChannel(attachment), id, mimeType, r)
+	if err != nil {
+		c.log.Error("failed to store attachment", zap.Error(err))
+		return "", err
+	}
+
+	attachment.ID = id
+	attachment.MimeType = mimeType
+	attachment.Size = size
+
+	// info is stored next to the blob, so attachments are restored after restart
+	if err = c.blobs.SaveInfo(ctx, attachment); err != nil {
+		c.log.Error("failed to store attachment info", zap.Error(err))
+		c.blobs.Delete(ctx, id)
+		return "", err
+	}
+
+	if err = c.attachments.With(id, entity.SafeWrite, func(attachments map[string]*entity.Attachment) error {
+		attachments[id] = &attachment
+
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	return id, ctx.Err()
+}
+
+// DownloadAttachment checks user access to the attachment channel, returns attachment info and its content
+func (c *chat) DownloadAttachment(ctx context.Context, id, userName string) (_ entity.Attachment, _ io.ReadCloser, err error) {
+	ctx, span := tracer.Start(ctx, "chat.DownloadAttachment")
+	defer func() { endSpan(span, err) }()
+
+	if c.blobs == nil {
+		return entity.Attachment{}, nil, errAttachmentsNotAvailable
+	}
+
+	var attachment entity.Attachment
+
+	if err := c.attachments.With(id, entity.SafeRead, func(attachments map[string]*entity.Attachment) error {
+		a, ok := attachments[id]
+		if !ok {
+			return errAttachmentNotFound
+		}
+
+		if !c.hasAttachmentAccess(ctx, a, userName) {
+			return errAttachmentAccessDenied
+		}
+
+		attachment = *a
+
+		return nil
+	}); err != nil {
+		c.log.Error("failed to download attachment", zap.Error(err))
+		return entity.Attachment{}, nil, err
+	}
+
+	content, err := c.blobs.Open(ctx, attachmentChannel(attachment), id)
+	if err != nil {
+		return entity.Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// storeAttachment checks content looks like mimeType and stores it, blob store discards content failing checks
+func (c *chat) storeAttachment(ctx context.Context, channel, id, mimeType string, r io.Reader) (int64, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+
+	head, err := br.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return 0, err
+	}
+
+	if !c.isSniffedTypeAllowed(mimeType, head) {
+		return 0, errAttachmentMimeType
+	}
+
+	return c.blobs.Put(ctx, channel, id, &sizeLimiter{r: br, left: c.cfg.Attachments.MaxSize})
+}
+
+// loadAttachments restores info of attachments stored before restart
+func (c *chat) loadAttachments(ctx context.Context) error {
+	if c.blobs == nil {
+		return nil
+	}
+
+	items, err := c.blobs.ListInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		attachment := items[i]
+
+		c.attachments.With(attachment.ID, entity.SafeWrite, func(attachments map[string]*entity.Attachment) error {
+			attachments[attachment.ID] = &attachment
+
+			return nil
+		})
+	}
+
+	return nil
+}
+
+// attachmentChannel names a channel attachment content is encrypted for, both sides of a direct chat share it
+func attachmentChannel(a entity.Attachment) string {
+	if a.ChatType == entity.OneToMany {
+		return "group:" + a.To
+	}
+
+	users := []string{a.Owner, a.To}
+	sort.Strings(users)
+
+	return "direct:" + strings.Join(users, ":")
+}
+
+func (c *chat) checkMimeType(mimeType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return "", errAttachmentMimeType
+	}
+
+	if !c.isMimeTypeAllowed(mediaType) {
+		return "", errAttachmentMimeType
+	}
+
+	return mediaType, nil
+}
+
+// isSniffedTypeAllowed checks content doesn't look like a media type other than declared and allowed ones
+func (c *chat) isSniffedTypeAllowed(declared string, head []byte) bool {
+	sniffed, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return false
+	}
+
+	if sniffed == declared || sniffed == "application/octet-stream" {
+		return true
+	}
+
+	return c.isMimeTypeAllowed(sniffed)
+}
+
+func (c *chat) isMimeTypeAllowed(mediaType string) bool {
+	for _, allowed := range c.cfg.Attachments.AllowedMimeTypes {
+		if allowed == mediaType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *chat) hasAttachmentAccess(ctx context.Context, attachment *entity.Attachment, userName string) bool {
+	switch attachment.ChatType {
+	case entity.OneToOne:
+		return attachment.Owner == userName || attachment.To == userName
+
+	case entity.OneToMany:
+		members, err := c.registry.Members(ctx, attachment.To)
+		if err != nil {
+			return false
+		}
+
+		return isMember(members, userName)
+	}
+
+	return false
+}
+
+// checkAttachment makes sure message refers to an attachment uploaded by sender to the same destination
+func (c *chat) checkAttachment(message entity.Message, userName string) error {
+	if message.AttachmentID == "" {
+		return nil
+	}
+
+	return c.attachments.With(message.AttachmentID, entity.SafeRead, func(attachments map[string]*entity.Attachment) error {
+		attachment, ok := attachments[message.AttachmentID]
+		if !ok {
+			return errAttachmentNotFound
+		}
+
+		if attachment.Owner != userName || attachment.To != message.To || attachment.ChatType != message.ChatType {
+			return errAttachmentDestination
+		}
+
+		return nil
+	})
+}
+
+// sizeLimiter fails once more than left bytes are read, so an oversized attachment is never stored
+type sizeLimiter struct {
+	r    io.Reader
+	left int64
+}
+
+func (l *sizeLimiter) Read(p []byte) (int, error) {
+	if l.left < 0 {
+		return 0, errAttachmentTooLarge
+	}
+
+	// a byte over the limit is read to tell an attachment of max size from a larger one
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+
+	if l.left < 0 {
+		return n, errAttachmentTooLarge
+	}
+
+	return n, err
+}
+
+func newID() (string, error) {
+	b := make([]byte, idLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
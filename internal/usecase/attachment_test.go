@@ -0,0 +1,125 @@
+//go:build unit_tests
+// +build unit_tests
+
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/broker"
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/registry"
+	"github.com/ITheCorgi/grpc-chat-room/internal/usecase/blobstore"
+	"go.uber.org/zap"
+)
+
+func Test_Attachments(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	cfg := config.Chat{Attachments: config.Attachments{MaxSize: 16, AllowedMimeTypes: []string{"text/plain"}}}
+
+	newChat := func() *chat {
+		blobs, err := blobstore.NewLocal(dir, nil)
+		if err != nil {
+			t.Fatalf("failed to create blob store: %v", err)
+		}
+
+		c, err := New(cfg, registry.NewLocal("test"), broker.NewLocal(), blobs, noopMetrics{}, nil, nil, nil, nil, zap.NewNop())
+		if err != nil {
+			t.Fatalf("failed to create chat: %v", err)
+		}
+
+		return c
+	}
+
+	c := newChat()
+	attachment := entity.Attachment{FileName: "a.txt", MimeType: "text/plain", To: "user2", ChatType: entity.OneToOne}
+
+	t.Run("test rejected uploads leave no files", func(t *testing.T) {
+		tcs := []struct {
+			name    string
+			content []byte
+		}{
+			{name: "too large", content: bytes.Repeat([]byte("a"), 17)},
+			{name: "wrong media type", content: []byte("\x89PNG\r\n\x1a\n")},
+		}
+
+		for _, tc := range tcs {
+			if _, err := c.UploadAttachment(ctx, attachment, "user1", bytes.NewReader(tc.content)); !errors.Is(err, entity.ErrInvalidArgument) {
+				t.Errorf("%s: expected invalid argument error, got %v", tc.name, err)
+			}
+		}
+
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("expected no files to be left, got %d", len(entries))
+		}
+	})
+
+	t.Run("test attachment is downloaded after restart", func(t *testing.T) {
+		id, err := c.UploadAttachment(ctx, attachment, "user1", bytes.NewReader(bytes.Repeat([]byte("a"), 16)))
+		if err != nil {
+			t.Fatalf("failed to upload attachment: %v", err)
+		}
+
+		restarted := newChat()
+
+		if _, _, err = restarted.DownloadAttachment(ctx, id, "user3"); !errors.Is(err, entity.ErrPermissionDenied) {
+			t.Errorf("expected permission denied error, got %v", err)
+		}
+
+		info, content, err := restarted.DownloadAttachment(ctx, id, "user2")
+		if err != nil {
+			t.Fatalf("failed to download attachment: %v", err)
+		}
+		defer content.Close()
+
+		if info.FileName != "a.txt" || info.Owner != "user1" || info.Size != 16 {
+			t.Errorf("unexpected attachment info: %+v", info)
+		}
+
+		if data, _ := io.ReadAll(content); len(data) != 16 {
+			t.Errorf("expected 16 bytes, got %d", len(data))
+		}
+	})
+	t.Run("test default max size is used when it's not set", func(t *testing.T) {
+		blobs, err := blobstore.NewLocal(t.TempDir(), nil)
+		if err != nil {
+			t.Fatalf("failed to create blob store: %v", err)
+		}
+
+		unset := cfg
+		unset.Attachments.MaxSize = 0
+
+		c, err := New(unset, registry.NewLocal("test"), broker.NewLocal(), blobs, noopMetrics{}, nil, nil, nil, nil, zap.NewNop())
+		if err != nil {
+			t.Fatalf("failed to create chat: %v", err)
+		}
+
+		if _, err = c.UploadAttachment(ctx, attachment, "user1", bytes.NewReader(bytes.Repeat([]byte("a"), 1024))); err != nil {
+			t.Errorf("failed to upload attachment: %v", err)
+		}
+
+		tooLarge := io.LimitReader(neverEnding('a'), defaultAttachmentMaxSize+1)
+		if _, err = c.UploadAttachment(ctx, attachment, "user1", tooLarge); !errors.Is(err, entity.ErrInvalidArgument) {
+			t.Errorf("expected invalid argument error, got %v", err)
+		}
+	})
+}
+
+// neverEnding reads the same byte forever
+type neverEnding byte
+
+func (b neverEnding) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(b)
+	}
+
+	return len(p), nil
+}
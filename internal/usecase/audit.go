@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/requestid"
+	"go.uber.org/zap"
+)
+
+var errAuditLogNotAvailable = errors.New("audit log is not configured")
+
+// QueryAuditLog provides audit records matching filter
+func (c *chat) QueryAuditLog(ctx context.Context, filter entity.AuditFilter) (_ []entity.AuditRecord, err error) {
+	ctx, span := tracer.Start(ctx, "chat.QueryAuditLog")
+	defer func() { endSpan(span, err) }()
+
+	if c.audit == nil {
+		return nil, errAuditLogNotAvailable
+	}
+
+	records, err := c.audit.Query(ctx, filter)
+	if err != nil {
+		c.log.Error("failed to query audit log", zap.Error(err))
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// recordAudit writes an audit record even if request is already cancelled, failures are only logged
+// since the action is performed anyway
+func (c *chat) recordAudit(ctx context.Context, action, actor, target string) {
+	if c.audit == nil {
+		return
+	}
+
+	rec := entity.AuditRecord{
+		Time:      time.Now().UTC(),
+		RequestID: requestid.FromContext(ctx),
+		Actor:     actor,
+		Action:    action,
+		Target:    target,
+	}
+
+	if err := c.audit.Record(context.Background(), rec); err != nil {
+		c.log.Error("failed to write audit record", zap.Error(err), zap.Any("record", rec))
+	}
+}
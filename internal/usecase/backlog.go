@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"go.uber.org/zap"
+)
+
+// takeBacklog provides an activity summary followed by messages the user has missed in groups still joined,
+// restore puts the backlog back if the session fails to start
+func (c *chat) takeBacklog(ctx context.Context, userName string) (_ []entity.Message, restore func()) {
+	restore = func() {}
+	if c.backlog == nil {
+		return nil, restore
+	}
+
+	activity, missed, err := c.backlog.Take(ctx, userName)
+	if err != nil {
+		c.log.Error("failed to take backlog", zap.Error(err))
+		return nil, restore
+	}
+
+	if len(activity) > 0 {
+		restore = func() {
+			// the request context is likely done once the session has failed
+			if err := c.backlog.Restore(context.Background(), userName, activity, missed); err != nil {
+				c.log.Error("failed to restore backlog", zap.Error(err))
+			}
+		}
+	}
+
+	// the user may have left a group or the group may have been deleted while the user was offline
+	joined := make(map[string]bool, len(activity))
+	active := make([]entity.GroupActivity, 0, len(activity))
+
+	for _, a := range activity {
+		members, err := c.registry.Members(ctx, a.Group)
+		if err != nil || !isMember(members, userName) {
+			continue
+		}
+
+		joined[a.Group] = true
+		active = append(active, a)
+	}
+
+	if len(active) == 0 {
+		return nil, restore
+	}
+
+	res := make([]entity.Message, 0, len(missed)+1)
+	res = append(res, activitySummary(active))
+
+	for _, msg := range missed {
+		if joined[msg.To] {
+			msg.Kind = entity.Backlog
+			res = append(res, msg)
+		}
+	}
+
+	return res, restore
+}
+
+func activitySummary(activity []entity.GroupActivity) entity.Message {
+	groups := make([]string, len(activity))
+	for i, a := range activity {
+		groups[i] = fmt.Sprintf("%s (%d)", a.Group, a.Unread)
+	}
+
+	return entity.Message{
+		Message:     "new messages in groups: " + strings.Join(groups, ", "),
+		ContentType: entity.PlainText,
+		Kind:        entity.ActivitySummary,
+		Activity:    activity,
+	}
+}
@@ -0,0 +1,214 @@
+package blobstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/fsutil"
+)
+
+const (
+	// infoExt is an extension of attachment info files kept next to blobs
+	infoExt = ".info"
+	// infoChannel is a channel attachment info is encrypted for, info tells a channel of the blob itself
+	infoChannel = "attachments"
+)
+
+var errInvalidBlobID = errors.New("invalid blob id")
+
+// local keeps blobs as files inside a single directory along with attachment info files, both are encrypted
+// when sealer is set
+type local struct {
+	dir    string
+	sealer fsutil.IStreamSealer
+}
+
+// NewLocal creates a blob store in dir, nil sealer keeps blobs in plaintext
+func NewLocal(dir string, sealer fsutil.IStreamSealer) (*local, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &local{dir: dir, sealer: sealer}, nil
+}
+
+// Put stores a blob of the channel read from r, blob is written into a temporary file moved in place once
+// the whole content is written, so a failed upload leaves nothing behind
+func (l *local) Put(ctx context.Context, channel, id string, r io.Reader) (int64, error) {
+	path, err := l.path(id)
+	if err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(l.dir, ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := l.write(channel, tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+
+	return size, ctx.Err()
+}
+
+func (l *local) write(channel string, f *os.File, r io.Reader) (int64, error) {
+	if l.sealer == nil {
+		return io.Copy(f, r)
+	}
+
+	w, err := l.sealer.NewWriter(channel, f)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := io.Copy(w, r)
+	if err != nil {
+		return 0, err
+	}
+
+	// the last encrypted segment is written on close
+	return size, w.Close()
+}
+
+// Open opens an existing blob of the channel for reading. Blobs written before encryption was enabled are
+// read as they are
+func (l *local) Open(ctx context.Context, channel, id string) (io.ReadCloser, error) {
+	path, err := l.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.sealer == nil {
+		return f, ctx.Err()
+	}
+
+	// blobs written before encryption was enabled are read within plaintext migration window
+	r, err := l.sealer.NewReader(channel, f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return readCloser{Reader: r, Closer: f}, ctx.Err()
+}
+
+// Delete removes a blob along with its attachment info, missing blob is not an error
+func (l *local) Delete(ctx context.Context, id string) error {
+	path, err := l.path(id)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range []string{path + infoExt, path} {
+		if err = os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
+
+// SaveInfo stores attachment info next to its blob
+func (l *local) SaveInfo(ctx context.Context, a entity.Attachment) error {
+	path, err := l.path(a.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	if l.sealer != nil {
+		if data, err = l.sealer.Seal(infoChannel, data); err != nil {
+			return err
+		}
+	}
+
+	if err = fsutil.WriteAtomic(path+infoExt, data); err != nil {
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// ListInfo provides info of all stored attachments
+func (l *local) ListInfo(ctx context.Context) ([]entity.Attachment, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []entity.Attachment{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) != infoExt {
+			continue
+		}
+
+		a, err := l.readInfo(filepath.Join(l.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("attachment info %s: %w", entry.Name(), err)
+		}
+
+		res = append(res, a)
+	}
+
+	return res, ctx.Err()
+}
+
+func (l *local) readInfo(path string) (entity.Attachment, error) {
+	var a entity.Attachment
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return a, err
+	}
+
+	if l.sealer != nil {
+		if data, err = l.sealer.Open(infoChannel, data); err != nil {
+			return a, err
+		}
+	}
+
+	err = json.Unmarshal(data, &a)
+
+	return a, err
+}
+
+func (l *local) path(id string) (string, error) {
+	if id == "" || filepath.Base(id) != id {
+		return "", errInvalidBlobID
+	}
+
+	return filepath.Join(l.dir, id), nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,201 @@
+//go:build unit_tests
+// +build unit_tests
+
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/envelope"
+	"github.com/ITheCorgi/grpc-chat-room/internal/fsutil"
+)
+
+var errBrokenReader = errors.New("connection is lost")
+
+// brokenReader fails after data is read
+type brokenReader struct {
+	data []byte
+}
+
+func (r *brokenReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, errBrokenReader
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+
+	return n, nil
+}
+
+func Test_Local(t *testing.T) {
+	ctx := context.Background()
+
+	stores := []struct {
+		name   string
+		sealer func(t *testing.T) fsutil.IStreamSealer
+	}{
+		{name: "plaintext", sealer: func(*testing.T) fsutil.IStreamSealer { return nil }},
+		{name: "encrypted", sealer: newKeyring},
+	}
+
+	for _, store := range stores {
+		t.Run("test "+store.name+" blob and its info are stored", func(t *testing.T) {
+			dir := t.TempDir()
+
+			l, err := NewLocal(dir, store.sealer(t))
+			if err != nil {
+				t.Fatalf("failed to create store: %v", err)
+			}
+
+			size, err := l.Put(ctx, "group:g1", "id1", bytes.NewReader([]byte("hello")))
+			if err != nil {
+				t.Fatalf("failed to put blob: %v", err)
+			}
+			if size != 5 {
+				t.Errorf("size mismatch: exp: 5, act: %d", size)
+			}
+
+			exp := entity.Attachment{ID: "id1", FileName: "a.txt", MimeType: "text/plain", Size: 5, Owner: "user1", To: "g1"}
+			if err = l.SaveInfo(ctx, exp); err != nil {
+				t.Fatalf("failed to save info: %v", err)
+			}
+
+			r, err := l.Open(ctx, "group:g1", "id1")
+			if err != nil {
+				t.Fatalf("failed to open blob: %v", err)
+			}
+			defer r.Close()
+
+			if data, _ := io.ReadAll(r); string(data) != "hello" {
+				t.Errorf("data mismatch: exp: hello, act: %s", data)
+			}
+
+			items, err := l.ListInfo(ctx)
+			if err != nil {
+				t.Fatalf("failed to list info: %v", err)
+			}
+			if len(items) != 1 || items[0] != exp {
+				t.Errorf("info mismatch: exp: [%+v], act: %+v", exp, items)
+			}
+
+			if err = l.Delete(ctx, "id1"); err != nil {
+				t.Fatalf("failed to delete blob: %v", err)
+			}
+			assertEmpty(t, dir)
+		})
+
+		t.Run("test "+store.name+" failed upload leaves nothing", func(t *testing.T) {
+			dir := t.TempDir()
+
+			l, err := NewLocal(dir, store.sealer(t))
+			if err != nil {
+				t.Fatalf("failed to create store: %v", err)
+			}
+
+			if _, err = l.Put(ctx, "group:g1", "id1", &brokenReader{data: []byte("hello")}); !errors.Is(err, errBrokenReader) {
+				t.Errorf("error mismatch: exp: %v, act: %v", errBrokenReader, err)
+			}
+			assertEmpty(t, dir)
+		})
+	}
+
+	t.Run("test blob written before encryption is read within migration window only", func(t *testing.T) {
+		dir := t.TempDir()
+
+		plain, err := NewLocal(dir, nil)
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+
+		if _, err = plain.Put(ctx, "group:g1", "id1", bytes.NewReader([]byte("hello"))); err != nil {
+			t.Fatalf("failed to put blob: %v", err)
+		}
+
+		migrating, err := NewLocal(dir, newMigratingKeyring(t, time.Now().Add(time.Hour)))
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+
+		r, err := migrating.Open(ctx, "group:g1", "id1")
+		if err != nil {
+			t.Fatalf("failed to open blob: %v", err)
+		}
+		defer r.Close()
+
+		if data, _ := io.ReadAll(r); string(data) != "hello" {
+			t.Errorf("data mismatch: exp: hello, act: %s", data)
+		}
+
+		encrypted, err := NewLocal(dir, newKeyring(t))
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+
+		if _, err = encrypted.Open(ctx, "group:g1", "id1"); !errors.Is(err, envelope.ErrPlaintext) {
+			t.Errorf("error mismatch: exp: %v, act: %v", envelope.ErrPlaintext, err)
+		}
+	})
+
+	t.Run("test blob id must not be a path", func(t *testing.T) {
+		l, err := NewLocal(t.TempDir(), nil)
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+
+		if _, err = l.Put(ctx, "group:g1", "../id1", bytes.NewReader(nil)); !errors.Is(err, errInvalidBlobID) {
+			t.Errorf("error mismatch: exp: %v, act: %v", errInvalidBlobID, err)
+		}
+	})
+}
+
+func assertEmpty(t *testing.T, dir string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+
+	for _, entry := range entries {
+		t.Errorf("unexpected file is left: %s", entry.Name())
+	}
+}
+
+func newKeyring(t *testing.T) fsutil.IStreamSealer {
+	t.Helper()
+
+	return newMigratingKeyring(t, time.Time{})
+}
+
+// newMigratingKeyring creates a keyring reading plaintext until the time
+func newMigratingKeyring(t *testing.T, plaintextUntil time.Time) fsutil.IStreamSealer {
+	t.Helper()
+
+	key := make([]byte, envelope.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	k, err := envelope.New(config.Encryption{
+		MasterKey:           base64.StdEncoding.EncodeToString(key),
+		KeysFile:            filepath.Join(t.TempDir(), "keys.json"),
+		AllowPlaintextUntil: plaintextUntil,
+	})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	return k
+}
@@ -3,41 +3,142 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
 	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/shard"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 var (
-	errDuplicateChannelGroupName   = errors.New("duplicate channel name")
-	errChannelGroupDoesntExist     = errors.New("channel group with such name is not found")
-	errUserIsAlreadyInGroupChannel = errors.New("user is already inside the group channel")
-	errUserNotFound                = errors.New("user was not found in the specified group channel")
-	errDestinationAddrDoesntExist  = errors.New("channel group or user is not exist")
+	errUserNotFound               = errors.New("user was not found in the specified group channel")
+	errDestinationAddrDoesntExist = errors.New("channel group or user is not exist")
+	errRecipientOffline           = fmt.Errorf("%w: recipient is offline, direct message is not delivered", entity.ErrNotFound)
+	errSelfBlock                  = fmt.Errorf("%w: user can't block himself", entity.ErrInvalidArgument)
+	errSenderIsBlocked            = fmt.Errorf("%w: recipient has blocked the sender", entity.ErrPermissionDenied)
+	errServerIsDraining           = fmt.Errorf("%w: server is shutting down", entity.ErrUnavailable)
+	errUnknownSlowConsumerPolicy  = errors.New("unknown slow consumer policy")
+)
+
+const (
+	defaultQueueSize = 100
+	// defaultAttachmentMaxSize is used when attachments max size is not configured
+	defaultAttachmentMaxSize = 10 << 20
+
+	// slowConsumerDrop and slowConsumerDisconnect are policies applied to a user whose queue is full
+	slowConsumerDrop       = "drop"
+	slowConsumerDisconnect = "disconnect"
+
+	// registryRetryInterval and registryRetryTimeout bound waiting for registry cluster to elect a leader on start
+	registryRetryInterval = time.Second
+	registryRetryTimeout  = time.Minute
 )
 
 type (
 	chat struct {
 		log *zap.Logger
+		cfg config.Chat
+		// limits is a content policy applied to names and messages
+		limits limits
 
+		// mu guards draining state and announcements, per-user and per-attachment state is sharded
+		// to let independent conversations proceed in parallel
 		mu *sync.RWMutex
-		// channels keeps a list of active chat rooms (map[chat_name]chat
-		channels map[string]*entity.Chatroom
-		// connPipe is a pool of client grpc connections (map[user_name]stream queue)
-		connPipe map[string]chan entity.Message
+		// registry keeps groups and users presence shared by replicas
+		registry IRegistry
+		// connPipe is a pool of client grpc connections (map[user_name]connection)
+		connPipe *shard.Map[*connection]
+		// broker routes messages to sessions connected to any replica
+		broker IBroker
+		// blocklistChanges serializes blocklist changes of a user made by this replica, so the stored copy
+		// doesn't fall behind registry, blocklists themselves are kept by registry
+		blocklistChanges *shard.Map[struct{}]
+		// blocklistStore persists blocklists across restarts
+		blocklistStore IBlocklistStore
+		// attachments keeps uploaded attachments info (map[attachment_id]attachment)
+		attachments *shard.Map[*entity.Attachment]
+		// blobs stores attachments content
+		blobs IBlobStore
+		// metrics collects domain metrics
+		metrics IMetrics
+		// audit keeps records of administrative actions
+		audit IAuditLog
+		// announcements keeps scheduled and active announcements (map[announcement_id]announcement)
+		announcements map[string]*announcement
+		// announcementStore persists announcements across restarts
+		announcementStore IAnnouncementStore
+		// backlog keeps group messages missed by offline members, group membership doesn't depend on connection
+		backlog IBacklogStore
+		// draining is set once server starts shutting down, new connections and messages are rejected then
+		draining bool
+		// inflight tracks messages being delivered to subscriber queues
+		inflight *sync.WaitGroup
+		// dispatcher delivers group messages in the background keeping their order
+		dispatcher *dispatcher
 		// withSafeFunc provides goroutine safe access to pool and channel list
 		withSafeFunc func(mu *sync.RWMutex, safe entity.Lock, fn func() error) error
 	}
+
+	// connection is a session of a user connected to this replica along with its broker subscription
+	connection struct {
+		*entity.Session
+		// unsubscribe stops the broker subscription feeding the session queue
+		unsubscribe func()
+	}
 )
 
-func New(log *zap.Logger) *chat {
-	return &chat{
-		log: log,
+func New(cfg config.Chat, registry IRegistry, broker IBroker, blobs IBlobStore, metrics IMetrics, audit IAuditLog,
+	announcements IAnnouncementStore, blocklists IBlocklistStore, backlog IBacklogStore, log *zap.Logger) (*chat, error) {
+	l, err := newLimits(cfg.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+
+	if cfg.Attachments.MaxSize <= 0 {
+		cfg.Attachments.MaxSize = defaultAttachmentMaxSize
+	}
+
+	switch cfg.SlowConsumerPolicy {
+	case "":
+		cfg.SlowConsumerPolicy = slowConsumerDrop
+	case slowConsumerDrop, slowConsumerDisconnect:
+	default:
+		return nil, fmt.Errorf("%w %q", errUnknownSlowConsumerPolicy, cfg.SlowConsumerPolicy)
+	}
+
+	c := &chat{
+		log:    log,
+		cfg:    cfg,
+		limits: l,
+
+		mu:          &sync.RWMutex{},
+		registry:    registry,
+		connPipe:    shard.New[*connection](),
+		broker:      broker,
+		attachments: shard.New[*entity.Attachment](),
+		blobs:       blobs,
+		metrics:     metrics,
+		audit:       audit,
+		inflight:    &sync.WaitGroup{},
+
+		blocklistChanges: shard.New[struct{}](),
+
+		announcements:     make(map[string]*announcement),
+		announcementStore: announcements,
+		blocklistStore:    blocklists,
+		backlog:           backlog,
 
-		mu:       &sync.RWMutex{},
-		channels: make(map[string]*entity.Chatroom),
-		connPipe: make(map[string]chan entity.Message),
 		withSafeFunc: func(mu *sync.RWMutex, safe entity.Lock, fn func() error) error {
 			switch safe {
 			case entity.SafeRead:
@@ -55,108 +156,372 @@ func New(log *zap.Logger) *chat {
 			return nil
 		},
 	}
+
+	c.dispatcher = newDispatcher(cfg.DeliveryWorkers, cfg.QueueSize, c.deliverMessage, c.dropMessage)
+
+	if err = c.loadAnnouncements(context.Background()); err != nil {
+		return nil, err
+	}
+
+	if err = c.subscribeAnnouncements(); err != nil {
+		return nil, err
+	}
+
+	if err = c.loadBlocklists(context.Background()); err != nil {
+		return nil, err
+	}
+
+	if err = c.loadAttachments(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return c, nil
 }
 
 // Connect establishes connection with server, returns stream of messages
-func (c *chat) Connect(ctx context.Context, userName string) (chan entity.Message, error) {
-	queue := make(chan entity.Message, 100)
-	if err := c.withSafeFunc(c.mu, entity.SafeWrite, func() error {
-		c.connPipe[userName] = queue
+func (c *chat) Connect(ctx context.Context, userName string) (_ *entity.Session, err error) {
+	ctx, span := tracer.Start(ctx, "chat.Connect")
+	defer func() { endSpan(span, err) }()
 
-		return nil
+	if err := c.limits.checkUserName(userName); err != nil {
+		return nil, err
+	}
+
+	// missed messages are queued before the subscription starts, so they precede new ones
+	missed, restoreBacklog := c.takeBacklog(ctx, userName)
+	session := entity.NewSession(userName, c.cfg.QueueSize+len(missed))
+	for _, msg := range missed {
+		session.Queue <- msg
+	}
+
+	// subscription changes may take a broker round trip, so they are made without locks held
+	unsubscribe, err := c.broker.Subscribe(userTopic(userName), c.sessionHandler(session))
+	if err != nil {
+		restoreBacklog()
+		c.log.Error("failed to create user chat", zap.Error(err))
+		return nil, err
+	}
+
+	var prev *connection
+
+	// read lock keeps announcements from starting while the session is registered, so none of them is missed
+	if err := c.withSafeFunc(c.mu, entity.SafeRead, func() error {
+		if c.draining {
+			return errServerIsDraining
+		}
+
+		return c.connPipe.With(userName, entity.SafeWrite, func(conns map[string]*connection) error {
+			// a user has a single session, the previous stream is ended once user reconnects
+			if prev = conns[userName]; prev != nil {
+				prev.Close()
+			}
+
+			conns[userName] = &connection{Session: session, unsubscribe: unsubscribe}
+			c.pushActiveAnnouncements(session)
+
+			return nil
+		})
 	}); err != nil {
+		unsubscribe()
+		restoreBacklog()
 		c.log.Error("failed to create user chat", zap.Error(err))
 		return nil, err
 	}
 
-	return queue, nil
+	if prev != nil {
+		prev.unsubscribe()
+	}
+
+	c.metrics.SetConnectedUsers(c.connPipe.Len())
+
+	// presence is informational, the session works even if other replicas are not aware of it
+	if err := c.registry.SetPresence(ctx, userName, c.registry.NodeID()); err != nil {
+		c.log.Error("failed to set user presence", zap.Error(err))
+	}
+
+	return session, nil
 }
 
-// CreateGroupChat creates a group chat, in case there is one it returns an error
-func (c *chat) CreateGroupChat(ctx context.Context, channelName, userName string) error {
-	if err := c.withSafeFunc(c.mu, entity.SafeWrite, func() error {
-		if chatroom := c.isChatExist(channelName); chatroom != nil {
-			return errDuplicateChannelGroupName
-		}
+// Disconnect ends the session once its stream is closed, a newer session of the same user is kept
+func (c *chat) Disconnect(ctx context.Context, session *entity.Session) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.Disconnect")
+	defer func() { endSpan(span, err) }()
 
-		chatRoom, err := c.createAndSubscribe(channelName, userName, entity.OneToMany)
-		if err != nil {
-			return err
+	c.removeSession(ctx, session)
+
+	return ctx.Err()
+}
+
+// ReapStaleSessions periodically closes sessions whose streams have not been writable for the configured time,
+// so messages don't pile up in queues nobody reads. It returns once ctx is done
+func (c *chat) ReapStaleSessions(ctx context.Context) {
+	if c.cfg.StaleSessionTimeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.cfg.StaleSessionTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			c.reapStaleSessions(ctx, now)
 		}
+	}
+}
 
-		c.addChatRoom(chatRoom)
+// CreateGroupChat creates a group chat, in case there is one it returns an error
+func (c *chat) CreateGroupChat(ctx context.Context, channelName, userName string) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.CreateGroupChat")
+	defer func() { endSpan(span, err) }()
 
-		return nil
-	}); err != nil {
+	if err := c.limits.checkGroupName(channelName); err != nil {
+		return err
+	}
+
+	if err := c.limits.checkUserName(userName); err != nil {
+		return err
+	}
+
+	if err := c.registry.CreateGroup(ctx, channelName, userName); err != nil {
 		c.log.Error("failed to create group chat", zap.Error(err))
 		return err
 	}
 
+	c.updateActiveGroups(ctx)
+	c.recordAudit(ctx, entity.AuditGroupCreate, userName, channelName)
+
 	return ctx.Err()
 }
 
 // JoinGroupChat checks whether chat exists, then subscribes user to chat room
-func (c *chat) JoinGroupChat(ctx context.Context, channelName, userName string) error {
+func (c *chat) JoinGroupChat(ctx context.Context, channelName, userName string) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.JoinGroupChat")
+	defer func() { endSpan(span, err) }()
+
+	if err := c.limits.checkUserName(userName); err != nil {
+		return err
+	}
+
+	if err := c.registry.JoinGroup(ctx, channelName, userName); err != nil {
+		c.log.Error("failed to join group chat", zap.Error(err))
+		return err
+	}
+
+	c.recordAudit(ctx, entity.AuditGroupJoin, userName, channelName)
+
+	return ctx.Err()
+}
+
+// LeaveGroupChat checks chat for existing, then unsubscribes user from chat
+func (c *chat) LeaveGroupChat(ctx context.Context, channelName, userName string) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.LeaveGroupChat")
+	defer func() { endSpan(span, err) }()
+
+	isDeleted, err := c.registry.LeaveGroup(ctx, channelName, userName)
+	if err != nil {
+		c.log.Error("failed to leave group chat", zap.Error(err))
+		return err
+	}
+
+	c.recordAudit(ctx, entity.AuditGroupLeave, userName, channelName)
+	if isDeleted {
+		c.updateActiveGroups(ctx)
+		c.recordAudit(ctx, entity.AuditGroupDelete, userName, channelName)
+	}
+
+	return ctx.Err()
+}
+
+// ListChannels provides a list of existing chat rooms
+func (c *chat) ListChannels(ctx context.Context) (_ entity.Channels, err error) {
+	ctx, span := tracer.Start(ctx, "chat.ListChannels")
+	defer func() { endSpan(span, err) }()
+
+	res, err := c.registry.Groups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, ctx.Err()
+}
+
+// SendMessage pushes a message to private or public chats
+func (c *chat) SendMessage(ctx context.Context, message entity.Message, userName string) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.SendMessage")
+	defer func() { endSpan(span, err) }()
+
+	if err := c.checkParticipants(message.To, message.ChatType, userName); err != nil {
+		return err
+	}
+
+	if err := c.limits.checkMessage(message); err != nil {
+		return err
+	}
+
+	// group members don't share keys, so group messages are never encrypted
+	if message.ContentType == entity.Encrypted && message.ChatType != entity.OneToOne {
+		return errEncryptedGroupMessage
+	}
+
+	// drain waits for messages accepted before it has started
 	if err := c.withSafeFunc(c.mu, entity.SafeRead, func() error {
-		channel := c.isChatExist(channelName)
-		if channel == nil {
-			return errChannelGroupDoesntExist
+		if c.draining {
+			return errServerIsDraining
 		}
 
-		isSucceed := channel.AddSubscriber(userName)
-		if !isSucceed {
-			return errUserIsAlreadyInGroupChannel
-		}
+		c.inflight.Add(1)
 
 		return nil
 	}); err != nil {
-		c.log.Error("failed to join group chat", zap.Error(err))
+		c.log.Error("failed to send message", zap.Error(err))
 		return err
 	}
+	defer c.inflight.Done()
 
-	return ctx.Err()
+	recipients, err := c.resolveRecipients(ctx, message, userName)
+	if err != nil {
+		c.log.Error("failed to send message", zap.Error(err))
+		return err
+	}
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	switch message.ChatType {
+	case entity.OneToOne:
+		message.To = userName
+
+		// direct messages are not kept for offline users, the sender is told to send it again later
+		if err := c.broker.Publish(ctx, userTopic(recipients[0]), message); err != nil {
+			if errors.Is(err, entity.ErrNotFound) {
+				err = errRecipientOffline
+			}
+
+			c.log.Error("failed to send message", zap.Error(err))
+			return err
+		}
+
+	case entity.OneToMany:
+		c.distributeMessage(ctx, message, userName, recipients)
+	}
+
+	c.metrics.MessageSent(message.ChatType)
+
+	return nil
 }
 
-// LeaveGroupChat checks chat for existing, then unsubscribes user from chat
-func (c *chat) LeaveGroupChat(ctx context.Context, channelName, userName string) error {
-	if err := c.withSafeFunc(c.mu, entity.SafeWrite, func() error {
-		channel := c.isChatExist(channelName)
-		if channel == nil {
-			return errChannelGroupDoesntExist
+// resolveRecipients provides users the message is published to, no recipients means the message is dropped
+func (c *chat) resolveRecipients(ctx context.Context, message entity.Message, userName string) ([]string, error) {
+	if err := c.checkAttachment(message, userName); err != nil {
+		return nil, err
+	}
+
+	switch message.ChatType {
+	case entity.OneToOne:
+		isBlocked, err := c.isBlockedBy(ctx, message.To, userName)
+		if err != nil {
+			return nil, err
+		}
+
+		if isBlocked {
+			if c.cfg.RejectBlocked {
+				return nil, errSenderIsBlocked
+			}
+
+			c.log.Debug("message from blocked user is dropped",
+				zap.String("from", userName), zap.String("to", message.To))
+			return nil, nil
+		}
+
+		return []string{message.To}, nil
+
+	case entity.OneToMany:
+		members, err := c.registry.Members(ctx, message.To)
+		if errors.Is(err, entity.ErrGroupNotFound) {
+			return nil, nil
 		}
 
-		isSucceed := channel.RemoveSubscriber(userName)
-		if !isSucceed {
-			return errUserNotFound
+		if err != nil {
+			return nil, err
 		}
 
-		if channel.SubscribersLen() == 0 {
-			delete(c.channels, channelName)
+		if !isMember(members, userName) {
+			return nil, errUserNotFound
+		}
+
+		return members, nil
+	}
+
+	return nil, nil
+}
+
+// BlockUser stops delivering direct messages from blockedUser to userName
+func (c *chat) BlockUser(ctx context.Context, userName, blockedUser string) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.BlockUser")
+	defer func() { endSpan(span, err) }()
+
+	if err := c.checkParticipants(blockedUser, entity.OneToOne, userName); err != nil {
+		return err
+	}
+
+	if userName == blockedUser {
+		return errSelfBlock
+	}
+
+	if err := c.blocklistChanges.With(userName, entity.SafeWrite, func(map[string]struct{}) error {
+		if err := c.registry.Block(ctx, userName, blockedUser); err != nil {
+			return err
+		}
+
+		// change is kept only once it's stored, otherwise it would be lost on restart silently
+		if err := c.saveBlocklist(ctx, userName); err != nil {
+			c.registry.Unblock(ctx, userName, blockedUser)
+			return err
 		}
 
 		return nil
 	}); err != nil {
-		c.log.Error("failed to leave group chat", zap.Error(err))
+		c.log.Error("failed to block user", zap.Error(err))
 		return err
 	}
 
 	return ctx.Err()
 }
 
-// ListChannels provides a list of existing chat rooms
-func (c *chat) ListChannels(ctx context.Context) (entity.Channels, error) {
-	res := make(entity.Channels, 0, len(c.channels))
-
-	err := c.withSafeFunc(c.mu, entity.SafeRead, func() error {
-		for k := range c.channels {
-			res = append(res, entity.Channel{
-				Name: c.channels[k].Name,
-				Type: c.channels[k].Type,
-			})
+// UnblockUser resumes delivering direct messages from blockedUser to userName
+func (c *chat) UnblockUser(ctx context.Context, userName, blockedUser string) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.UnblockUser")
+	defer func() { endSpan(span, err) }()
+
+	if err := c.blocklistChanges.With(userName, entity.SafeWrite, func(map[string]struct{}) error {
+		if err := c.registry.Unblock(ctx, userName, blockedUser); err != nil {
+			return err
+		}
+
+		if err := c.saveBlocklist(ctx, userName); err != nil {
+			c.registry.Block(ctx, userName, blockedUser)
+			return err
 		}
 
 		return nil
-	})
+	}); err != nil {
+		c.log.Error("failed to unblock user", zap.Error(err))
+		return err
+	}
+
+	return ctx.Err()
+}
+
+// ListBlocked provides a list of users blocked by userName
+func (c *chat) ListBlocked(ctx context.Context, userName string) (_ []string, err error) {
+	ctx, span := tracer.Start(ctx, "chat.ListBlocked")
+	defer func() { endSpan(span, err) }()
+
+	res, err := c.registry.Blocked(ctx, userName)
 	if err != nil {
 		return nil, err
 	}
@@ -164,104 +529,321 @@ func (c *chat) ListChannels(ctx context.Context) (entity.Channels, error) {
 	return res, ctx.Err()
 }
 
-// SendMessage pushes a message to private or public chats
-func (c *chat) SendMessage(ctx context.Context, message entity.Message, userName string) error {
-	if err := c.withSafeFunc(c.mu, entity.SafeWrite, func() error {
-		switch message.ChatType {
-		case entity.OneToOne:
-			queue, ok := c.connPipe[message.To]
-			if !ok {
-				return errUserNotFound
-			}
+// checkParticipants applies user name limits to the sender and to the recipient of a direct chat
+func (c *chat) checkParticipants(to string, chatType uint8, userName string) error {
+	if err := c.limits.checkUserName(userName); err != nil {
+		return err
+	}
 
-			message.To = userName
-			queue <- message
+	if chatType == entity.OneToOne {
+		return c.limits.checkUserName(to)
+	}
 
-		case entity.OneToMany:
-			chatroom := c.isChatExist(message.To)
-			if chatroom == nil {
-				return nil
-			}
+	return nil
+}
 
-			isBelongs := chatroom.IsSubscribed(userName)
-			if !isBelongs {
-				return errUserNotFound
-			}
+// loadBlocklists restores blocklists kept before restart. Registry is shared by replicas, so blocklists of users
+// known to registry already are kept, a replica joining a running cluster doesn't bring back stale entries
+func (c *chat) loadBlocklists(ctx context.Context) error {
+	if c.blocklistStore == nil {
+		return nil
+	}
+
+	items, err := c.blocklistStore.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for userName, blocked := range items {
+		known, err := c.registry.Blocked(ctx, userName)
+		if err != nil {
+			return err
+		}
+
+		if len(known) > 0 {
+			continue
+		}
 
-			c.distributeMessage(ctx, message, chatroom.GetSubscribers())
+		for _, user := range blocked {
+			err := withRegistryRetry(ctx, func() error { return c.registry.Block(ctx, userName, user) })
+			if err != nil && !errors.Is(err, entity.ErrUserBlocked) {
+				return err
+			}
 		}
+	}
 
+	return nil
+}
+
+// saveBlocklist stores users blocked by the user as registry has them, it must be called with the user
+// blocklist changes serialized
+func (c *chat) saveBlocklist(ctx context.Context, userName string) error {
+	if c.blocklistStore == nil {
 		return nil
-	}); err != nil {
-		c.log.Error("failed to send message", zap.Error(err))
+	}
+
+	blocked, err := c.registry.Blocked(ctx, userName)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return c.blocklistStore.Save(ctx, userName, blocked)
 }
 
-func (c *chat) createAndSubscribe(chat, user string, roomType uint8) (*entity.Chatroom, error) {
-	chatRoom := new(entity.Chatroom).
-		AddChannelInfo(entity.Channel{
-			Name: chat,
-			Type: roomType,
-		})
+// withRegistryRetry repeats a registry change while registry is unavailable, a registry cluster starting along
+// with the replica has no leader for a while
+func withRegistryRetry(ctx context.Context, fn func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, registryRetryTimeout)
+	defer cancel()
 
-	isAdded := chatRoom.AddSubscriber(user)
-	if !isAdded {
-		return nil, errUserIsAlreadyInGroupChannel
+	for {
+		err := fn()
+		if !errors.Is(err, entity.ErrUnavailable) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(registryRetryInterval):
+		}
 	}
+}
+
+// Drain stops accepting new connections and messages, waits for messages being delivered and
+// ends every open stream with a server going away event
+func (c *chat) Drain(ctx context.Context) error {
+	c.withSafeFunc(c.mu, entity.SafeWrite, func() error {
+		c.draining = true
+
+		return nil
+	})
+
+	// delivery workers exit once messages accepted before draining are delivered
+	defer c.dispatcher.close()
+
+	delivered := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(delivered)
+	}()
 
-	return chatRoom, nil
+	select {
+	case <-delivered:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	event := entity.Message{
+		Message:     "server is shutting down",
+		ContentType: entity.PlainText,
+		Kind:        entity.ServerGoingAway,
+	}
+
+	var err error
+
+	c.connPipe.Range(func(user string, conn *connection) bool {
+		select {
+		case conn.Queue <- event:
+			return true
+		case <-ctx.Done():
+			c.log.Warn("failed to notify user about shutdown", zap.String("user", user))
+			err = ctx.Err()
+			return false
+		}
+	})
+
+	return err
 }
 
-func (c *chat) addChatRoom(chatroom *entity.Chatroom) {
-	c.channels[chatroom.Name] = chatroom
+// QueueDepths provides amount of messages waiting in each connection queue
+func (c *chat) QueueDepths() map[string]int {
+	res := make(map[string]int)
+
+	c.connPipe.Range(func(user string, conn *connection) bool {
+		res[user] = len(conn.Queue)
+		return true
+	})
+
+	return res
 }
 
-func (c *chat) isChatExist(channelName string) *entity.Chatroom {
-	channel, ok := c.channels[channelName]
-	if ok {
-		return channel
+// updateActiveGroups refreshes active groups metric, registry is shared by replicas, so the amount is read back
+func (c *chat) updateActiveGroups(ctx context.Context) {
+	groups, err := c.registry.Groups(ctx)
+	if err != nil {
+		return
 	}
 
-	return nil
+	c.metrics.SetActiveGroups(len(groups))
+}
+
+func (c *chat) reapStaleSessions(ctx context.Context, now time.Time) {
+	var stale []*entity.Session
+
+	c.connPipe.Range(func(_ string, conn *connection) bool {
+		if conn.IsStale(now, c.cfg.StaleSessionTimeout) {
+			stale = append(stale, conn.Session)
+		}
+
+		return true
+	})
+
+	for _, session := range stale {
+		if c.removeSession(ctx, session) {
+			c.log.Warn("stale session is closed", zap.String("user", session.User))
+		}
+	}
 }
 
-func (c *chat) isUserConnected(user string) chan entity.Message {
-	ch, ok := c.connPipe[user]
-	if !ok {
+// removeSession closes the session and forgets it unless the user has connected again meanwhile
+func (c *chat) removeSession(ctx context.Context, session *entity.Session) bool {
+	var unsubscribe func()
+
+	c.connPipe.With(session.User, entity.SafeWrite, func(conns map[string]*connection) error {
+		conn, ok := conns[session.User]
+		if !ok || conn.Session != session {
+			return nil
+		}
+
+		conn.Close()
+		delete(conns, session.User)
+		unsubscribe = conn.unsubscribe
+
 		return nil
+	})
+
+	if unsubscribe == nil {
+		return false
+	}
+
+	unsubscribe()
+
+	c.metrics.SetConnectedUsers(c.connPipe.Len())
+
+	if err := c.registry.RemovePresence(ctx, session.User, c.registry.NodeID()); err != nil {
+		c.log.Error("failed to remove user presence", zap.Error(err))
 	}
 
-	return ch
+	return true
 }
 
-func (c *chat) distributeMessage(ctx context.Context, msg entity.Message, subscribers []string) {
-	wg := &sync.WaitGroup{}
+func (c *chat) isUserConnected(user string) *entity.Session {
+	var session *entity.Session
 
-	for _, subscriber := range subscribers {
-		select {
-		case <-ctx.Done():
-			c.log.Info("context is done, exiting from message distribution")
+	c.connPipe.With(user, entity.SafeRead, func(conns map[string]*connection) error {
+		if conn, ok := conns[user]; ok {
+			session = conn.Session
+		}
+
+		return nil
+	})
+
+	return session
+}
+
+func (c *chat) isBlockedBy(ctx context.Context, recipient, sender string) (bool, error) {
+	blocked, err := c.registry.Blocked(ctx, recipient)
+	if err != nil {
+		return false, err
+	}
+
+	return isMember(blocked, sender), nil
+}
+
+// dropMessage gives up a group message whose recipient has too many messages waiting for delivery
+func (c *chat) dropMessage(subscriber string, d delivery) {
+	defer d.done()
+
+	c.log.Warn("failed to send message, too many messages are waiting", zap.String("subscriber", subscriber))
+	c.metrics.DeliveryDropped()
+}
+
+// distributeMessage queues a group message for subscribers, it is delivered in the background
+func (c *chat) distributeMessage(ctx context.Context, msg entity.Message, sender string, subscribers []string) {
+	start := time.Now()
+	remaining := int64(len(subscribers))
+
+	c.inflight.Add(len(subscribers))
+	c.dispatcher.enqueue(subscribers, delivery{
+		// deliveries outlive the request, so they keep its trace but not its cancellation
+		ctx:    trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx)),
+		msg:    msg,
+		sender: sender,
+		done: func() {
+			defer c.inflight.Done()
+
+			if atomic.AddInt64(&remaining, -1) == 0 {
+				c.metrics.ObserveFanout(time.Since(start))
+			}
+		},
+	})
+}
+
+// deliverMessage publishes a group message to a single subscriber, the message is kept in backlog
+// if the subscriber is offline
+func (c *chat) deliverMessage(subscriber string, d delivery) {
+	defer d.done()
+
+	ctx, span := tracer.Start(d.ctx, "chat.deliver", trace.WithAttributes(attribute.String("subscriber", subscriber)))
+	defer span.End()
+
+	err := c.broker.Publish(ctx, userTopic(subscriber), d.msg)
+	if errors.Is(err, entity.ErrNotFound) && c.backlog != nil {
+		// the sender has seen the message already
+		if subscriber == d.sender {
+			return
+		}
+
+		err = c.backlog.Append(ctx, subscriber, d.msg)
+	}
+
+	if err != nil {
+		c.log.Error("failed to send message", zap.String("subscriber", subscriber), zap.Error(err))
+		c.metrics.DeliveryDropped()
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// sessionHandler queues messages published to the session user, a message for a user whose queue is full is
+// handled by the slow consumer policy. A disconnect event published by an operator on any replica ends the session
+func (c *chat) sessionHandler(session *entity.Session) func(entity.Message) {
+	return func(msg entity.Message) {
+		if msg.Kind == entity.Disconnected {
+			c.removeSession(context.Background(), session)
 			return
+		}
 
+		// a user not reading the stream never holds up the publisher
+		select {
+		case session.Queue <- msg:
+			return
 		default:
-			wg.Add(1)
+		}
+
+		c.metrics.DeliveryDropped()
+
+		if c.cfg.SlowConsumerPolicy == slowConsumerDisconnect {
+			c.log.Warn("session is closed, queue is full", zap.String("user", session.User))
+			c.removeSession(context.Background(), session)
+
+			return
+		}
 
-			go func(subscriber string) {
-				defer wg.Done()
+		c.log.Warn("failed to deliver message, queue is full", zap.String("user", session.User))
+	}
+}
 
-				ch, ok := c.connPipe[subscriber]
-				if !ok {
-					c.log.Error("failed to send message", zap.String("subscriber", subscriber))
-					return
-				}
+func userTopic(userName string) string {
+	return "user." + userName
+}
 
-				ch <- msg
-			}(subscriber)
+func isMember(members []string, user string) bool {
+	for _, member := range members {
+		if member == user {
+			return true
 		}
 	}
 
-	go wg.Wait()
+	return false
 }
@@ -0,0 +1,488 @@
+//go:build unit_tests
+// +build unit_tests
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/backlog"
+	"github.com/ITheCorgi/grpc-chat-room/internal/blocklist"
+	"github.com/ITheCorgi/grpc-chat-room/internal/broker"
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/registry"
+	"go.uber.org/zap"
+)
+
+const backlogLimit = 3
+
+// benchSenders are amounts of concurrent senders, each sender has its own conversation
+var benchSenders = []int{1000, 5000}
+
+type noopMetrics struct{}
+
+func (noopMetrics) SetConnectedUsers(int)       {}
+func (noopMetrics) SetActiveGroups(int)         {}
+func (noopMetrics) MessageSent(uint8)           {}
+func (noopMetrics) ObserveFanout(time.Duration) {}
+func (noopMetrics) DeliveryDropped()            {}
+
+func newTestChat(tb testing.TB, cfg config.Chat) *chat {
+	tb.Helper()
+
+	c, err := New(cfg, registry.NewLocal("test"), broker.NewLocal(), nil, noopMetrics{}, nil, nil, nil, backlog.NewMemory(backlogLimit), zap.NewNop())
+	if err != nil {
+		tb.Fatalf("failed to create chat: %v", err)
+	}
+
+	return c
+}
+
+// connectConsumer connects a user whose queue is drained until the benchmark ends
+func connectConsumer(b *testing.B, c *chat, userName string) {
+	b.Helper()
+
+	session, err := c.Connect(context.Background(), userName)
+	if err != nil {
+		b.Fatalf("failed to connect %s: %v", userName, err)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-session.Queue:
+			case <-session.Done():
+				return
+			}
+		}
+	}()
+
+	b.Cleanup(session.Close)
+}
+
+func Test_SessionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	dm := entity.Message{To: "user1", Message: "hello", ChatType: entity.OneToOne, ContentType: entity.PlainText}
+
+	t.Run("test closed stream deregisters its session only", func(t *testing.T) {
+		c := newTestChat(t, config.Chat{})
+
+		prev, err := c.Connect(ctx, "user1")
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+
+		session, err := c.Connect(ctx, "user1")
+		if err != nil {
+			t.Fatalf("failed to reconnect: %v", err)
+		}
+
+		// the stream of replaced session ends after the user has reconnected
+		c.Disconnect(ctx, prev)
+
+		if err = c.SendMessage(ctx, dm, "user2"); err != nil {
+			t.Fatalf("expected message to reach the new session, got %v", err)
+		}
+
+		c.Disconnect(ctx, session)
+
+		select {
+		case <-session.Done():
+		default:
+			t.Error("expected session to be closed")
+		}
+
+		if err = c.SendMessage(ctx, dm, "user2"); !errors.Is(err, errRecipientOffline) {
+			t.Errorf("expected recipient offline error after disconnect, got %v", err)
+		}
+
+		if node, _ := c.registry.Presence(ctx, "user1"); node != "" {
+			t.Errorf("expected user to be offline, got presence on %q", node)
+		}
+	})
+
+	t.Run("test session not writable for timeout is reaped", func(t *testing.T) {
+		c := newTestChat(t, config.Chat{StaleSessionTimeout: time.Minute})
+
+		stale, err := c.Connect(ctx, "user1")
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+
+		active, err := c.Connect(ctx, "user2")
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+
+		stale.StartWrite()
+		active.StartWrite()
+		active.EndWrite()
+
+		c.reapStaleSessions(ctx, time.Now().Add(time.Minute))
+
+		if c.isUserConnected("user1") != nil {
+			t.Error("expected stale session to be removed")
+		}
+
+		if c.isUserConnected("user2") == nil {
+			t.Error("expected active session to be kept")
+		}
+	})
+}
+
+func Test_GroupBacklog(t *testing.T) {
+	t.Run("test offline members get activity summary and latest missed messages on connect", func(t *testing.T) {
+		c := newTestChat(t, config.Chat{})
+		ctx := context.Background()
+
+		if err := c.CreateGroupChat(ctx, "group1", "user1"); err != nil {
+			t.Fatalf("failed to create group: %v", err)
+		}
+
+		for _, user := range []string{"user2", "user3"} {
+			if err := c.JoinGroupChat(ctx, "group1", user); err != nil {
+				t.Fatalf("failed to join group: %v", err)
+			}
+		}
+
+		// the sender has no open stream either, but it doesn't miss its own messages
+		for i := 0; i < 5; i++ {
+			msg := entity.Message{To: "group1", Message: fmt.Sprint(i), ChatType: entity.OneToMany, ContentType: entity.PlainText}
+			if err := c.SendMessage(ctx, msg, "user1"); err != nil {
+				t.Fatalf("failed to send message: %v", err)
+			}
+		}
+
+		c.inflight.Wait()
+
+		if err := c.LeaveGroupChat(ctx, "group1", "user3"); err != nil {
+			t.Fatalf("failed to leave group: %v", err)
+		}
+
+		tests := []struct {
+			user string
+			exp  []string
+		}{
+			{user: "user1"},
+			{user: "user2", exp: []string{"new messages in groups: group1 (5)", "2", "3", "4"}},
+			{user: "user3"},
+			// backlog is delivered once
+			{user: "user2"},
+		}
+
+		for _, tt := range tests {
+			session, err := c.Connect(ctx, tt.user)
+			if err != nil {
+				t.Fatalf("failed to connect %s: %v", tt.user, err)
+			}
+
+			act := make([]string, 0)
+			for len(session.Queue) > 0 {
+				msg := <-session.Queue
+				act = append(act, msg.Message)
+
+				if len(act) == 1 && (msg.Kind != entity.ActivitySummary || len(msg.Activity) != 1 || msg.Activity[0].Unread != 5) {
+					t.Errorf("expected activity summary of group1, got %+v", msg)
+				}
+
+				if len(act) > 1 && msg.Kind != entity.Backlog {
+					t.Errorf("expected backlog message, got %+v", msg)
+				}
+			}
+
+			if len(act) != len(tt.exp) {
+				t.Fatalf("expected %v for %s, got %v", tt.exp, tt.user, act)
+			}
+
+			for i := range act {
+				if act[i] != tt.exp[i] {
+					t.Errorf("expected %v for %s, got %v", tt.exp, tt.user, act)
+				}
+			}
+		}
+	})
+}
+
+func Test_BacklogOnFailedConnect(t *testing.T) {
+	ctx := context.Background()
+
+	// replicas share registry, broker and backlog, the draining one is replaced by the other
+	reg := registry.NewLocal("test")
+	b := broker.NewLocal()
+	store := backlog.NewMemory(backlogLimit)
+
+	newChat := func() *chat {
+		c, err := New(config.Chat{}, reg, b, nil, noopMetrics{}, nil, nil, nil, store, zap.NewNop())
+		if err != nil {
+			t.Fatalf("failed to create chat: %v", err)
+		}
+
+		return c
+	}
+
+	draining := newChat()
+
+	if err := draining.CreateGroupChat(ctx, "group1", "user1"); err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+
+	if err := draining.JoinGroupChat(ctx, "group1", "user2"); err != nil {
+		t.Fatalf("failed to join group: %v", err)
+	}
+
+	msg := entity.Message{To: "group1", Message: "1", ChatType: entity.OneToMany, ContentType: entity.PlainText}
+	if err := draining.SendMessage(ctx, msg, "user1"); err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+
+	if err := draining.Drain(ctx); err != nil {
+		t.Fatalf("failed to drain: %v", err)
+	}
+
+	if _, err := draining.Connect(ctx, "user2"); !errors.Is(err, errServerIsDraining) {
+		t.Fatalf("expected draining error, got %v", err)
+	}
+
+	// a message missed after the failed connect follows the restored ones
+	msg.Message = "2"
+	if err := store.Append(ctx, "user2", msg); err != nil {
+		t.Fatalf("failed to append backlog: %v", err)
+	}
+
+	session, err := newChat().Connect(ctx, "user2")
+	if err != nil {
+		t.Fatalf("failed to reconnect: %v", err)
+	}
+
+	act := make([]string, 0)
+	for len(session.Queue) > 0 {
+		act = append(act, (<-session.Queue).Message)
+	}
+
+	exp := []string{"new messages in groups: group1 (2)", "1", "2"}
+	if len(act) != len(exp) {
+		t.Fatalf("expected %v, got %v", exp, act)
+	}
+
+	for i := range act {
+		if act[i] != exp[i] {
+			t.Errorf("expected %v, got %v", exp, act)
+		}
+	}
+}
+
+func Test_Blocking(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := blocklist.NewFile(filepath.Join(t.TempDir(), "blocklists.json"), nil)
+	if err != nil {
+		t.Fatalf("failed to create blocklists storage: %v", err)
+	}
+
+	newChat := func() *chat {
+		c, err := New(config.Chat{RejectBlocked: true}, registry.NewLocal("test"), broker.NewLocal(), nil, noopMetrics{},
+			nil, nil, store, nil, zap.NewNop())
+		if err != nil {
+			t.Fatalf("failed to create chat: %v", err)
+		}
+
+		return c
+	}
+
+	c := newChat()
+
+	t.Run("test repeated changes fail with proper kinds", func(t *testing.T) {
+		if err := c.BlockUser(ctx, "user1", "user2"); err != nil {
+			t.Fatalf("failed to block user: %v", err)
+		}
+
+		if err := c.BlockUser(ctx, "user1", "user2"); !errors.Is(err, entity.ErrAlreadyExists) {
+			t.Errorf("expected already exists error, got %v", err)
+		}
+
+		if err := c.UnblockUser(ctx, "user1", "user3"); !errors.Is(err, entity.ErrNotFound) {
+			t.Errorf("expected not found error, got %v", err)
+		}
+
+		if err := c.UnblockUser(ctx, "user3", "user1"); !errors.Is(err, entity.ErrNotFound) {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("test blocklists are restored after restart", func(t *testing.T) {
+		if err := c.BlockUser(ctx, "user1", "user3"); err != nil {
+			t.Fatalf("failed to block user: %v", err)
+		}
+
+		if err := c.UnblockUser(ctx, "user1", "user3"); err != nil {
+			t.Fatalf("failed to unblock user: %v", err)
+		}
+
+		restarted := newChat()
+
+		blocked, err := restarted.ListBlocked(ctx, "user1")
+		if err != nil {
+			t.Fatalf("failed to list blocked users: %v", err)
+		}
+
+		if len(blocked) != 1 || blocked[0] != "user2" {
+			t.Errorf("expected [user2] to be blocked, got %v", blocked)
+		}
+
+		if _, err = restarted.Connect(ctx, "user1"); err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+
+		dm := entity.Message{To: "user1", Message: "hello", ChatType: entity.OneToOne, ContentType: entity.PlainText}
+		if err = restarted.SendMessage(ctx, dm, "user2"); !errors.Is(err, entity.ErrPermissionDenied) {
+			t.Errorf("expected permission denied error, got %v", err)
+		}
+	})
+}
+
+func Test_SlowConsumer(t *testing.T) {
+	ctx := context.Background()
+	dm := entity.Message{To: "user1", Message: "hello", ChatType: entity.OneToOne, ContentType: entity.PlainText}
+
+	tcs := []struct {
+		name           string
+		policy         string
+		isDisconnected bool
+	}{
+		{name: "test messages above queue size are dropped", policy: slowConsumerDrop},
+		{name: "test session is closed once its queue is full", policy: slowConsumerDisconnect, isDisconnected: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestChat(t, config.Chat{QueueSize: 2, SlowConsumerPolicy: tc.policy})
+
+			session, err := c.Connect(ctx, "user1")
+			if err != nil {
+				t.Fatalf("failed to connect: %v", err)
+			}
+
+			// the user never reads the stream, senders are not held up by it
+			for i := 0; i < 3; i++ {
+				if err = c.SendMessage(ctx, dm, "user2"); err != nil {
+					t.Fatalf("failed to send message %d: %v", i, err)
+				}
+			}
+
+			if len(session.Queue) != 2 {
+				t.Errorf("queue length mismatch: exp: 2, act: %d", len(session.Queue))
+			}
+
+			if isConnected := c.isUserConnected("user1") != nil; isConnected == tc.isDisconnected {
+				t.Errorf("connection state mismatch: exp connected: %t, act: %t", !tc.isDisconnected, isConnected)
+			}
+		})
+	}
+
+	t.Run("test unknown policy is rejected", func(t *testing.T) {
+		_, err := New(config.Chat{SlowConsumerPolicy: "block"}, registry.NewLocal("test"), broker.NewLocal(), nil,
+			noopMetrics{}, nil, nil, nil, nil, zap.NewNop())
+		if !errors.Is(err, errUnknownSlowConsumerPolicy) {
+			t.Errorf("expected unknown policy error, got %v", err)
+		}
+	})
+}
+
+// runSenders runs senders concurrently, each of them calls send with its own index
+func runSenders(b *testing.B, senders int, send func(i int) error) {
+	b.Helper()
+
+	var next int64
+
+	b.SetParallelism((senders + runtime.GOMAXPROCS(0) - 1) / runtime.GOMAXPROCS(0))
+	b.ResetTimer()
+	start := time.Now()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(atomic.AddInt64(&next, 1)-1) % senders
+
+		for pb.Next() {
+			if err := send(i); err != nil {
+				b.Errorf("failed to send message: %v", err)
+				return
+			}
+		}
+	})
+
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "msgs/s")
+}
+
+func Benchmark_SendDirectMessage(b *testing.B) {
+	for _, senders := range benchSenders {
+		b.Run(fmt.Sprintf("senders=%d", senders), func(b *testing.B) {
+			c := newTestChat(b, config.Chat{})
+
+			for i := 0; i < senders; i++ {
+				connectConsumer(b, c, fmt.Sprintf("recipient%d", i))
+			}
+
+			runSenders(b, senders, func(i int) error {
+				msg := entity.Message{
+					To:          fmt.Sprintf("recipient%d", i),
+					Message:     "hello",
+					ChatType:    entity.OneToOne,
+					ContentType: entity.PlainText,
+				}
+
+				return c.SendMessage(context.Background(), msg, fmt.Sprintf("sender%d", i))
+			})
+		})
+	}
+}
+
+func Benchmark_SendGroupMessage(b *testing.B) {
+	const groupSize = 4
+
+	for _, senders := range benchSenders {
+		b.Run(fmt.Sprintf("senders=%d", senders), func(b *testing.B) {
+			c := newTestChat(b, config.Chat{})
+			ctx := context.Background()
+
+			// every sender owns a group with members connected to the replica
+			for i := 0; i < senders; i++ {
+				group := fmt.Sprintf("group%d", i)
+				if err := c.CreateGroupChat(ctx, group, fmt.Sprintf("sender%d", i)); err != nil {
+					b.Fatalf("failed to create group: %v", err)
+				}
+
+				for j := 0; j < groupSize; j++ {
+					member := fmt.Sprintf("member%d_%d", i, j)
+					if err := c.JoinGroupChat(ctx, group, member); err != nil {
+						b.Fatalf("failed to join group: %v", err)
+					}
+
+					connectConsumer(b, c, member)
+				}
+			}
+
+			runSenders(b, senders, func(i int) error {
+				msg := entity.Message{
+					To:          fmt.Sprintf("group%d", i),
+					Message:     "hello",
+					ChatType:    entity.OneToMany,
+					ContentType: entity.PlainText,
+				}
+
+				return c.SendMessage(ctx, msg, fmt.Sprintf("sender%d", i))
+			})
+
+			// group deliveries are asynchronous, drain waits for them before consumers are stopped
+			if err := c.Drain(ctx); err != nil {
+				b.Fatalf("failed to wait for deliveries: %v", err)
+			}
+		})
+	}
+}
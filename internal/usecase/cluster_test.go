@@ -0,0 +1,151 @@
+//go:build unit_tests
+// +build unit_tests
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/broker"
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"github.com/ITheCorgi/grpc-chat-room/internal/registry"
+	"go.uber.org/zap"
+)
+
+// nodeRegistry lets replicas of a test cluster share a registry while keeping their own ids
+type nodeRegistry struct {
+	IRegistry
+	id string
+}
+
+func (r nodeRegistry) NodeID() string {
+	return r.id
+}
+
+// newTestCluster creates replicas sharing registry and broker, like replicas of a real cluster do
+func newTestCluster(t *testing.T, cfg config.Chat) (*chat, *chat) {
+	t.Helper()
+
+	shared := registry.NewLocal("")
+	b := broker.NewLocal()
+
+	replicas := make([]*chat, 2)
+	for i, id := range []string{"node1", "node2"} {
+		c, err := New(cfg, nodeRegistry{IRegistry: shared, id: id}, b, nil, noopMetrics{}, nil, nil, nil, nil, zap.NewNop())
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", id, err)
+		}
+
+		replicas[i] = c
+	}
+
+	return replicas[0], replicas[1]
+}
+
+func Test_Cluster(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("test blocklist set on one replica applies to messages sent through another", func(t *testing.T) {
+		a, b := newTestCluster(t, config.Chat{RejectBlocked: true})
+
+		if _, err := a.Connect(ctx, "user1"); err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+
+		if err := a.BlockUser(ctx, "user1", "user2"); err != nil {
+			t.Fatalf("failed to block user: %v", err)
+		}
+
+		dm := entity.Message{To: "user1", Message: "hello", ChatType: entity.OneToOne, ContentType: entity.PlainText}
+		if err := b.SendMessage(ctx, dm, "user2"); !errors.Is(err, entity.ErrPermissionDenied) {
+			t.Errorf("expected permission denied error, got %v", err)
+		}
+
+		if err := b.UnblockUser(ctx, "user1", "user2"); err != nil {
+			t.Fatalf("failed to unblock user through another replica: %v", err)
+		}
+
+		if err := b.SendMessage(ctx, dm, "user2"); err != nil {
+			t.Errorf("failed to send message after unblock: %v", err)
+		}
+	})
+
+	t.Run("test user is disconnected and listed through any replica", func(t *testing.T) {
+		a, b := newTestCluster(t, config.Chat{})
+
+		session, err := b.Connect(ctx, "user1")
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+
+		if _, err = a.Connect(ctx, "user2"); err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+
+		sessions, err := a.ListSessions(ctx)
+		if err != nil {
+			t.Fatalf("failed to list sessions: %v", err)
+		}
+
+		if len(sessions) != 2 || sessions[0].User != "user1" || sessions[0].Node != "node2" ||
+			sessions[1].User != "user2" || sessions[1].Node != "node1" || sessions[1].ConnectedAt.IsZero() {
+			t.Errorf("unexpected sessions: %v", sessions)
+		}
+
+		if err = a.DisconnectUser(ctx, "user1", "admin"); err != nil {
+			t.Fatalf("failed to disconnect user on another replica: %v", err)
+		}
+
+		select {
+		case <-session.Done():
+		case <-time.After(time.Second):
+			t.Fatal("session on another replica is not closed")
+		}
+
+		if err = a.DisconnectUser(ctx, "user1", "admin"); !errors.Is(err, entity.ErrNotFound) {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("test announcement reaches users of every replica and is cancelled everywhere", func(t *testing.T) {
+		a, b := newTestCluster(t, config.Chat{})
+
+		session, err := b.Connect(ctx, "user1")
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+
+		if _, err = a.Broadcast(ctx, entity.Announcement{Message: "maintenance", Author: "admin"}); err != nil {
+			t.Fatalf("failed to broadcast: %v", err)
+		}
+
+		expectAnnouncement(t, session, "maintenance")
+
+		start := time.Now().UTC().Add(time.Hour)
+		scheduled, err := a.Broadcast(ctx, entity.Announcement{Message: "release", StartAt: start, ExpireAt: start})
+		if err != nil {
+			t.Fatalf("failed to broadcast: %v", err)
+		}
+
+		if items, _ := b.ListAnnouncements(ctx); len(items) != 1 || items[0].ID != scheduled.ID {
+			t.Fatalf("expected scheduled announcement on another replica, got %v", items)
+		}
+
+		if err = b.CancelAnnouncement(ctx, scheduled.ID, "admin"); err != nil {
+			t.Fatalf("failed to cancel announcement: %v", err)
+		}
+
+		for _, c := range []*chat{a, b} {
+			items, _ := c.ListAnnouncements(ctx)
+			for _, item := range items {
+				if item.ID == scheduled.ID {
+					t.Errorf("expected cancelled announcement to be removed, got %v", items)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+)
+
+const defaultDeliveryWorkers = 64
+
+type (
+	// dispatcher delivers group messages through a fixed pool of workers. Messages are queued in a per-recipient
+	// outbox handled by one worker at a time, so every recipient gets messages in the order they were sent
+	dispatcher struct {
+		deliver func(recipient string, d delivery)
+		// drop is called for a message not queued, since the recipient outbox is full or dispatcher is closed
+		drop func(recipient string, d delivery)
+		// limit is a max amount of messages waiting in an outbox
+		limit int
+
+		mu   sync.Mutex
+		cond *sync.Cond
+		// outboxes keeps recipients having messages queued or being delivered (map[user_name]outbox)
+		outboxes map[string]*outbox
+		// ready is a queue of outboxes waiting for a worker
+		ready []*outbox
+		// closed is set once dispatcher stops accepting messages, workers exit when no outbox is ready
+		closed bool
+	}
+
+	outbox struct {
+		recipient string
+		pending   []delivery
+	}
+
+	delivery struct {
+		// ctx carries the trace of the request the message was sent in
+		ctx context.Context
+		msg entity.Message
+		// sender is a user who has sent the message
+		sender string
+		// done is called once the message is delivered or dropped
+		done func()
+	}
+)
+
+func newDispatcher(workers, limit int, deliver, drop func(recipient string, d delivery)) *dispatcher {
+	if workers <= 0 {
+		workers = defaultDeliveryWorkers
+	}
+
+	d := &dispatcher{
+		deliver:  deliver,
+		drop:     drop,
+		limit:    limit,
+		outboxes: make(map[string]*outbox),
+	}
+	d.cond = sync.NewCond(&d.mu)
+
+	for i := 0; i < workers; i++ {
+		go d.work()
+	}
+
+	return d
+}
+
+// enqueue queues a message for every recipient, recipients whose outbox is full miss the message
+func (d *dispatcher) enqueue(recipients []string, msg delivery) {
+	var dropped []string
+
+	d.mu.Lock()
+	for _, recipient := range recipients {
+		if d.closed {
+			dropped = append(dropped, recipient)
+			continue
+		}
+
+		ob, ok := d.outboxes[recipient]
+		if !ok {
+			// a new outbox has no worker yet
+			ob = &outbox{recipient: recipient}
+			d.outboxes[recipient] = ob
+			d.ready = append(d.ready, ob)
+			d.cond.Signal()
+		}
+
+		if len(ob.pending) >= d.limit {
+			dropped = append(dropped, recipient)
+			continue
+		}
+
+		ob.pending = append(ob.pending, msg)
+	}
+	d.mu.Unlock()
+
+	// drop is called without lock held, it may take a while
+	for _, recipient := range dropped {
+		d.drop(recipient, msg)
+	}
+}
+
+// close stops accepting messages, workers exit once messages queued before are delivered
+func (d *dispatcher) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.closed = true
+	d.cond.Broadcast()
+}
+
+// work takes ready outboxes one by one and delivers their messages in order
+func (d *dispatcher) work() {
+	for {
+		d.mu.Lock()
+		for len(d.ready) == 0 && !d.closed {
+			d.cond.Wait()
+		}
+
+		if len(d.ready) == 0 {
+			d.mu.Unlock()
+			return
+		}
+
+		ob := d.ready[0]
+		d.ready[0] = nil
+		d.ready = d.ready[1:]
+
+		batch := ob.pending
+		ob.pending = nil
+		d.mu.Unlock()
+
+		for _, msg := range batch {
+			d.deliver(ob.recipient, msg)
+		}
+
+		d.mu.Lock()
+		if len(ob.pending) > 0 {
+			// messages queued meanwhile wait for a worker behind other recipients, so a busy recipient
+			// doesn't hold a worker forever
+			d.ready = append(d.ready, ob)
+		} else {
+			delete(d.outboxes, ob.recipient)
+		}
+		d.mu.Unlock()
+	}
+}
@@ -0,0 +1,155 @@
+//go:build unit_tests
+// +build unit_tests
+
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+)
+
+func Test_Dispatcher(t *testing.T) {
+	t.Run("test every recipient gets messages in sending order", func(t *testing.T) {
+		const (
+			workers    = 4
+			recipients = 500
+			messages   = 50
+		)
+
+		var (
+			mu       sync.Mutex
+			received = make(map[string][]string)
+			wg       sync.WaitGroup
+		)
+
+		before := runtime.NumGoroutine()
+
+		d := newDispatcher(workers, messages, func(recipient string, msg delivery) {
+			defer msg.done()
+
+			// deliveries of different recipients interleave
+			runtime.Gosched()
+
+			mu.Lock()
+			received[recipient] = append(received[recipient], msg.msg.Message)
+			mu.Unlock()
+		}, func(recipient string, _ delivery) {
+			t.Errorf("message for %s is dropped", recipient)
+		})
+		defer d.close()
+
+		group := make([]string, recipients)
+		for i := range group {
+			group[i] = fmt.Sprintf("user%d", i)
+		}
+
+		for i := 0; i < messages; i++ {
+			wg.Add(recipients)
+			d.enqueue(group, delivery{
+				ctx:  context.Background(),
+				msg:  entity.Message{Message: fmt.Sprint(i)},
+				done: wg.Done,
+			})
+		}
+
+		if n := runtime.NumGoroutine() - before; n > workers {
+			t.Errorf("expected at most %d delivery goroutines, got %d", workers, n)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("messages are not delivered")
+		}
+
+		for _, recipient := range group {
+			if len(received[recipient]) != messages {
+				t.Fatalf("expected %d messages for %s, got %d", messages, recipient, len(received[recipient]))
+			}
+
+			for i, msg := range received[recipient] {
+				if msg != fmt.Sprint(i) {
+					t.Fatalf("expected message %d for %s at position %d, got %s", i, recipient, i, msg)
+				}
+			}
+		}
+	})
+
+	t.Run("test messages above outbox limit are dropped", func(t *testing.T) {
+		const limit = 3
+
+		var dropped, delivered atomic.Int32
+
+		// the first message holds the worker, so the rest wait in the outbox
+		started, release := make(chan struct{}), make(chan struct{})
+		d := newDispatcher(1, limit, func(_ string, msg delivery) {
+			if msg.msg.Message == "0" {
+				close(started)
+				<-release
+			}
+
+			delivered.Add(1)
+		}, func(string, delivery) {
+			dropped.Add(1)
+		})
+		defer d.close()
+
+		d.enqueue([]string{"user1"}, delivery{msg: entity.Message{Message: "0"}})
+		<-started
+
+		for i := 1; i <= limit+2; i++ {
+			d.enqueue([]string{"user1"}, delivery{msg: entity.Message{Message: fmt.Sprint(i)}})
+		}
+
+		if act := dropped.Load(); act != 2 {
+			t.Errorf("dropped mismatch: exp: 2, act: %d", act)
+		}
+
+		close(release)
+		waitFor(t, func() bool { return delivered.Load() == limit+1 })
+	})
+
+	t.Run("test workers exit once closed", func(t *testing.T) {
+		const workers = 8
+
+		before := runtime.NumGoroutine()
+
+		var dropped atomic.Int32
+		d := newDispatcher(workers, 1, func(string, delivery) {}, func(string, delivery) { dropped.Add(1) })
+
+		d.close()
+		waitFor(t, func() bool { return runtime.NumGoroutine() <= before })
+
+		d.enqueue([]string{"user1"}, delivery{})
+		if act := dropped.Load(); act != 1 {
+			t.Errorf("expected message enqueued after close to be dropped, got %d drops", act)
+		}
+	})
+}
+
+// waitFor waits a second at most for the condition to be met
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition is not met in time")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
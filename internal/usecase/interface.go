@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+)
+
+type IBlobStore interface {
+	// Put stores a blob of the channel read from r, returns its size. Nothing is stored when reading r fails
+	Put(ctx context.Context, channel, id string, r io.Reader) (int64, error)
+	// Open opens an existing blob of the channel for reading
+	Open(ctx context.Context, channel, id string) (io.ReadCloser, error)
+	// Delete removes a blob along with its attachment info
+	Delete(ctx context.Context, id string) error
+	// SaveInfo stores attachment info next to its blob
+	SaveInfo(ctx context.Context, a entity.Attachment) error
+	// ListInfo provides info of all stored attachments
+	ListInfo(ctx context.Context) ([]entity.Attachment, error)
+}
+
+type IMetrics interface {
+	// SetConnectedUsers sets amount of users with an open message stream
+	SetConnectedUsers(n int)
+	// SetActiveGroups sets amount of existing group channels
+	SetActiveGroups(n int)
+	// MessageSent counts a sent message of the chat type
+	MessageSent(chatType uint8)
+	// ObserveFanout records time spent delivering a group message to all subscribers
+	ObserveFanout(d time.Duration)
+	// DeliveryDropped counts a message not delivered to a subscriber
+	DeliveryDropped()
+}
+
+type IAuditLog interface {
+	// Record appends a record to audit log
+	Record(ctx context.Context, rec entity.AuditRecord) error
+	// Query provides records matching filter ordered by time
+	Query(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error)
+}
+
+type IBlocklistStore interface {
+	// List provides users blocked by each user
+	List(ctx context.Context) (map[string][]string, error)
+	// Save replaces users blocked by the user, empty blocked removes the user
+	Save(ctx context.Context, user string, blocked []string) error
+}
+
+type IBacklogStore interface {
+	// Append keeps a group message missed by an offline member, message destination is the group
+	Append(ctx context.Context, user string, msg entity.Message) error
+	// Take removes the user backlog, returns activity of groups along with the latest missed messages
+	// ordered by time
+	Take(ctx context.Context, user string) ([]entity.GroupActivity, []entity.Message, error)
+	// Restore puts back a taken backlog, restored messages precede the ones missed since it was taken
+	Restore(ctx context.Context, user string, activity []entity.GroupActivity, msgs []entity.Message) error
+}
+
+type IAnnouncementStore interface {
+	// List provides all stored announcements
+	List(ctx context.Context) ([]entity.Announcement, error)
+	// Save stores an announcement, an announcement with the same id is replaced
+	Save(ctx context.Context, a entity.Announcement) error
+	// Delete removes an announcement
+	Delete(ctx context.Context, id string) error
+}
+
+type IBroker interface {
+	// Publish delivers a message to subscribers of the topic on any replica, it fails with entity.ErrNotFound
+	// when the topic has no subscribers
+	Publish(ctx context.Context, topic string, msg entity.Message) error
+	// Subscribe registers handler for messages published to the topic, returns a function stopping the subscription
+	Subscribe(topic string, handler func(entity.Message)) (func(), error)
+}
+
+type IRegistry interface {
+	// NodeID provides an id of the replica
+	NodeID() string
+	// CreateGroup registers a group with its first member
+	CreateGroup(ctx context.Context, group, owner string) error
+	// JoinGroup adds a member to the group
+	JoinGroup(ctx context.Context, group, user string) error
+	// LeaveGroup removes a member from the group, the group is removed once its last member has left
+	LeaveGroup(ctx context.Context, group, user string) (isDeleted bool, err error)
+	// DeleteGroup removes a group regardless of its members
+	DeleteGroup(ctx context.Context, group string) error
+	// Groups provides existing groups ordered by name
+	Groups(ctx context.Context) (entity.Channels, error)
+	// Members provides group members ordered by name
+	Members(ctx context.Context, group string) ([]string, error)
+	// SetPresence records a replica the user is connected to
+	SetPresence(ctx context.Context, user, node string) error
+	// RemovePresence forgets the user connection, unless the user has reconnected to another replica meanwhile
+	RemovePresence(ctx context.Context, user, node string) error
+	// Presence provides a replica the user is connected to, empty string means the user is offline
+	Presence(ctx context.Context, user string) (string, error)
+	// Connected provides users connected to any replica along with ids of their replicas
+	Connected(ctx context.Context) (map[string]string, error)
+	// Block stops accepting direct messages of blocked by the user, it fails with entity.ErrUserBlocked
+	// when blocked is blocked already
+	Block(ctx context.Context, user, blocked string) error
+	// Unblock resumes accepting direct messages of blocked by the user, it fails with entity.ErrUserNotBlocked
+	// when blocked is not blocked
+	Unblock(ctx context.Context, user, blocked string) error
+	// Blocked provides users blocked by the user ordered by name
+	Blocked(ctx context.Context, user string) ([]string, error)
+	// PutKeys replaces public keys of direct message encryption of the user
+	PutKeys(ctx context.Context, user string, keys entity.KeyBundle) error
+	// TakeKeys provides identity key of the user along with one prekey at most, the prekey is removed,
+	// so it is handed out once. It fails with entity.ErrKeysNotFound when the user has no keys
+	TakeKeys(ctx context.Context, user string) (entity.KeyBundle, error)
+}
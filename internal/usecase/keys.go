@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"go.uber.org/zap"
+)
+
+var (
+	errEncryptedGroupMessage = fmt.Errorf("%w: only direct messages may be encrypted", entity.ErrInvalidArgument)
+	errDuplicatePrekey       = fmt.Errorf("%w: prekey ids must be unique", entity.ErrInvalidArgument)
+)
+
+// UploadKeys replaces public keys other users encrypt direct messages to the user with. Prekeys uploaded
+// before are dropped, since they may belong to a previous identity key
+func (c *chat) UploadKeys(ctx context.Context, userName string, keys entity.KeyBundle) (err error) {
+	ctx, span := tracer.Start(ctx, "chat.UploadKeys")
+	defer func() { endSpan(span, err) }()
+
+	if err := c.limits.checkUserName(userName); err != nil {
+		return err
+	}
+
+	ids := make(map[string]struct{}, len(keys.Prekeys))
+	for _, prekey := range keys.Prekeys {
+		if _, ok := ids[prekey.ID]; ok {
+			return errDuplicatePrekey
+		}
+
+		ids[prekey.ID] = struct{}{}
+	}
+
+	if err := c.registry.PutKeys(ctx, userName, keys); err != nil {
+		c.log.Error("failed to upload keys", zap.Error(err))
+		return err
+	}
+
+	c.recordAudit(ctx, entity.AuditKeysUpload, userName, userName)
+
+	return ctx.Err()
+}
+
+// GetKeys provides public keys to encrypt a direct message to the user with, a prekey handed out is removed
+// from the directory, so every prekey is used once
+func (c *chat) GetKeys(ctx context.Context, userName string) (keys entity.KeyBundle, err error) {
+	ctx, span := tracer.Start(ctx, "chat.GetKeys")
+	defer func() { endSpan(span, err) }()
+
+	keys, err = c.registry.TakeKeys(ctx, userName)
+	if err != nil {
+		c.log.Error("failed to get keys", zap.Error(err))
+		return entity.KeyBundle{}, err
+	}
+
+	return keys, ctx.Err()
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+)
+
+// Hard limits of api requests, proto validation rejects longer values before they reach usecase. Configured
+// limits may only be stricter, so a value passing the config never fails the api and vice versa
+const (
+	apiMaxUserNameLen  = 64
+	apiMaxGroupNameLen = 128
+	apiMaxMessageLen   = 65536
+	// encryptedOverhead is a size of an encrypted payload beyond its text: content framing, link preview
+	// metadata and authentication tag
+	encryptedOverhead = 4096
+)
+
+// limits keeps content policy compiled from config
+type limits struct {
+	maxMessageLen   int
+	maxGroupNameLen int
+	userName        *regexp.Regexp
+	groupName       *regexp.Regexp
+}
+
+func newLimits(cfg config.Limits) (limits, error) {
+	if cfg.MaxMessageLength > apiMaxMessageLen {
+		return limits{}, fmt.Errorf("max message length must not exceed %d", apiMaxMessageLen)
+	}
+
+	if cfg.MaxGroupNameLength > apiMaxGroupNameLen {
+		return limits{}, fmt.Errorf("max group name length must not exceed %d", apiMaxGroupNameLen)
+	}
+
+	l := limits{
+		maxMessageLen:   cfg.MaxMessageLength,
+		maxGroupNameLen: cfg.MaxGroupNameLength,
+	}
+
+	if l.maxMessageLen == 0 {
+		l.maxMessageLen = apiMaxMessageLen
+	}
+
+	if l.maxGroupNameLen == 0 {
+		l.maxGroupNameLen = apiMaxGroupNameLen
+	}
+
+	var err error
+	if cfg.UsernamePattern != "" {
+		l.userName, err = regexp.Compile(cfg.UsernamePattern)
+		if err != nil {
+			return limits{}, fmt.Errorf("invalid username pattern: %w", err)
+		}
+	}
+
+	if cfg.GroupNamePattern != "" {
+		l.groupName, err = regexp.Compile(cfg.GroupNamePattern)
+		if err != nil {
+			return limits{}, fmt.Errorf("invalid group name pattern: %w", err)
+		}
+	}
+
+	return l, nil
+}
+
+// checkUserName is applied to user names of every request, including the caller name of request metadata
+// which isn't covered by proto validation
+func (l limits) checkUserName(name string) error {
+	if name == "" || utf8.RuneCountInString(name) > apiMaxUserNameLen {
+		return fmt.Errorf("%w: user name must be 1 to %d characters long", entity.ErrInvalidArgument, apiMaxUserNameLen)
+	}
+
+	if l.userName != nil && !l.userName.MatchString(name) {
+		return fmt.Errorf("%w: user name must match %q", entity.ErrInvalidArgument, l.userName)
+	}
+
+	return nil
+}
+
+func (l limits) checkGroupName(name string) error {
+	if utf8.RuneCountInString(name) > l.maxGroupNameLen {
+		return fmt.Errorf("%w: group channel name is longer than %d characters", entity.ErrInvalidArgument, l.maxGroupNameLen)
+	}
+
+	if l.groupName != nil && !l.groupName.MatchString(name) {
+		return fmt.Errorf("%w: group channel name must match %q", entity.ErrInvalidArgument, l.groupName)
+	}
+
+	return nil
+}
+
+// checkMessage applies max length to any text shown to a recipient: message text, markdown, code, link url
+// along with its preview. Encrypted content can't be counted, so its payload size is limited instead
+func (l limits) checkMessage(message entity.Message) error {
+	length := utf8.RuneCountInString(message.Message)
+	if message.Preview != nil {
+		length += utf8.RuneCountInString(message.Preview.Title) + utf8.RuneCountInString(message.Preview.Description)
+	}
+
+	if length > l.maxMessageLen {
+		return fmt.Errorf("%w: message is longer than %d characters", entity.ErrInvalidArgument, l.maxMessageLen)
+	}
+
+	if message.Ciphertext != nil && len(message.Ciphertext.Payload) > l.maxMessageLen*utf8.UTFMax+encryptedOverhead {
+		return fmt.Errorf("%w: encrypted message is longer than %d characters", entity.ErrInvalidArgument, l.maxMessageLen)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+//go:build unit_tests
+// +build unit_tests
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+)
+
+func Test_NewLimits(t *testing.T) {
+	tcs := []struct {
+		name    string
+		cfg     config.Limits
+		isValid bool
+	}{
+		{name: "test default limits", isValid: true},
+		{name: "test limits of api", cfg: config.Limits{MaxMessageLength: 65536, MaxGroupNameLength: 128}, isValid: true},
+		{name: "test message limit over api", cfg: config.Limits{MaxMessageLength: 65537}},
+		{name: "test group name limit over api", cfg: config.Limits{MaxGroupNameLength: 129}},
+		{name: "test invalid pattern", cfg: config.Limits{UsernamePattern: "["}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := newLimits(tc.cfg); (err == nil) != tc.isValid {
+				t.Errorf("validity mismatch: exp: %t, act error: %v", tc.isValid, err)
+			}
+		})
+	}
+}
+
+func Test_Limits(t *testing.T) {
+	ctx := context.Background()
+
+	c := newTestChat(t, config.Chat{Limits: config.Limits{MaxMessageLength: 10, UsernamePattern: "^[a-z0-9]+$"}})
+
+	if err := c.CreateGroupChat(ctx, "group1", "user1"); err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+
+	dm := func(to, text string) entity.Message {
+		return entity.Message{To: to, Message: text, ChatType: entity.OneToOne, ContentType: entity.PlainText}
+	}
+
+	tcs := []struct {
+		name string
+		call func() error
+	}{
+		{name: "test sender name of metadata", call: func() error { return c.SendMessage(ctx, dm("user2", "hi"), "User 1") }},
+		{name: "test too long sender name", call: func() error { return c.SendMessage(ctx, dm("user2", "hi"), strings.Repeat("a", 65)) }},
+		{name: "test direct message recipient", call: func() error { return c.SendMessage(ctx, dm("User 2", "hi"), "user1") }},
+		{name: "test group member name", call: func() error { return c.JoinGroupChat(ctx, "group1", "User 2") }},
+		{name: "test group owner name", call: func() error { return c.CreateGroupChat(ctx, "group2", "User 2") }},
+		{name: "test blocked user name", call: func() error { return c.BlockUser(ctx, "user1", "User 2") }},
+		{name: "test attachment recipient", call: func() error {
+			_, err := c.UploadAttachment(ctx, entity.Attachment{To: "User 2", ChatType: entity.OneToOne}, "user1", strings.NewReader(""))
+			return err
+		}},
+		{name: "test key owner name", call: func() error { return c.UploadKeys(ctx, "User 1", entity.KeyBundle{}) }},
+		{name: "test too long text", call: func() error { return c.SendMessage(ctx, dm("user2", "hello world"), "user1") }},
+		{name: "test too long link preview", call: func() error {
+			msg := dm("user2", "x.io")
+			msg.ContentType = entity.Link
+			msg.Preview = &entity.LinkPreview{Title: "title", Description: "description"}
+
+			return c.SendMessage(ctx, msg, "user1")
+		}},
+		{name: "test too long encrypted message", call: func() error {
+			msg := dm("user2", "")
+			msg.ContentType = entity.Encrypted
+			msg.Ciphertext = &entity.Ciphertext{Payload: make([]byte, 10*4+encryptedOverhead+1)}
+
+			return c.SendMessage(ctx, msg, "user1")
+		}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.call(); !errors.Is(err, entity.ErrInvalidArgument) {
+				t.Errorf("expected invalid argument error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+//go:build unit_tests
+// +build unit_tests
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/config"
+	"github.com/ITheCorgi/grpc-chat-room/internal/entity"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	testProvider = sdktrace.NewTracerProvider()
+	setProvider  sync.Once
+)
+
+func Test_Tracing(t *testing.T) {
+	// tracer of the package is bound to the first global provider, so recorders are attached to the same one
+	setProvider.Do(func() { otel.SetTracerProvider(testProvider) })
+
+	recorder := tracetest.NewSpanRecorder()
+	testProvider.RegisterSpanProcessor(recorder)
+	t.Cleanup(func() { testProvider.UnregisterSpanProcessor(recorder) })
+
+	ctx := context.Background()
+	c := newTestChat(t, config.Chat{})
+
+	if err := c.CreateGroupChat(ctx, "group1", "user1"); err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+
+	if err := c.JoinGroupChat(ctx, "group1", "user2"); err != nil {
+		t.Fatalf("failed to join group: %v", err)
+	}
+
+	t.Run("test fan-out spans belong to the trace of the message", func(t *testing.T) {
+		msg := entity.Message{To: "group1", Message: "hello", ChatType: entity.OneToMany, ContentType: entity.PlainText}
+		if err := c.SendMessage(ctx, msg, "user1"); err != nil {
+			t.Fatalf("failed to send message: %v", err)
+		}
+
+		c.inflight.Wait()
+
+		var (
+			send       sdktrace.ReadOnlySpan
+			deliveries []sdktrace.ReadOnlySpan
+		)
+
+		for _, span := range recorder.Ended() {
+			switch span.Name() {
+			case "chat.SendMessage":
+				send = span
+			case "chat.deliver":
+				deliveries = append(deliveries, span)
+			}
+		}
+
+		if send == nil || len(deliveries) != 2 {
+			t.Fatalf("expected send span and 2 delivery spans, got %v and %d", send, len(deliveries))
+		}
+
+		for _, span := range deliveries {
+			if span.SpanContext().TraceID() != send.SpanContext().TraceID() {
+				t.Errorf("delivery span is in another trace")
+			}
+		}
+	})
+
+	t.Run("test failed call marks its span", func(t *testing.T) {
+		if err := c.JoinGroupChat(ctx, "group2", "user2"); !errors.Is(err, entity.ErrNotFound) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+
+		spans := recorder.Ended()
+		last := spans[len(spans)-1]
+
+		if last.Name() != "chat.JoinGroupChat" || last.Status().Code != codes.Error || len(last.Events()) == 0 {
+			t.Errorf("expected failed join span with recorded error, got %s %+v", last.Name(), last.Status())
+		}
+	})
+}
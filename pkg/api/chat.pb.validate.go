@@ -57,10 +57,21 @@ func (m *ConnectRequest) validate(all bool) error {
 
 	var errors []error
 
-	if utf8.RuneCountInString(m.GetUsername()) < 1 {
+	if l := utf8.RuneCountInString(m.GetUsername()); l < 1 || l > 64 {
 		err := ConnectRequestValidationError{
 			field:  "Username",
-			reason: "value length must be at least 1 runes",
+			reason: "value length must be between 1 and 64 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_ConnectRequest_Username_Pattern.MatchString(m.GetUsername()) {
+		err := ConnectRequestValidationError{
+			field:  "Username",
+			reason: "value does not match regex pattern \"^[^\\\\s\\\\p{Cc}]+$\"",
 		}
 		if !all {
 			return err
@@ -81,7 +92,3520 @@ func (m *ConnectRequest) validate(all bool) error {
 type ConnectRequestMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m ConnectRequestMultiError) Error() string {
+func (m ConnectRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ConnectRequestMultiError) AllErrors() []error { return m }
+
+// ConnectRequestValidationError is the validation error returned by
+// ConnectRequest.Validate if the designated constraints aren't met.
+type ConnectRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ConnectRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ConnectRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ConnectRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ConnectRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ConnectRequestValidationError) ErrorName() string { return "ConnectRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ConnectRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sConnectRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ConnectRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ConnectRequestValidationError{}
+
+var _ConnectRequest_Username_Pattern = regexp.MustCompile("^[^\\s\\p{Cc}]+$")
+
+// Validate checks the field values on GroupChannelNameRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *GroupChannelNameRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GroupChannelNameRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// GroupChannelNameRequestMultiError, or nil if none found.
+func (m *GroupChannelNameRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GroupChannelNameRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetGroupChannelName()); l < 1 || l > 128 {
+		err := GroupChannelNameRequestValidationError{
+			field:  "GroupChannelName",
+			reason: "value length must be between 1 and 128 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_GroupChannelNameRequest_GroupChannelName_Pattern.MatchString(m.GetGroupChannelName()) {
+		err := GroupChannelNameRequestValidationError{
+			field:  "GroupChannelName",
+			reason: "value does not match regex pattern \"^[^\\\\s\\\\p{Cc}](?:[^\\\\p{Cc}]*[^\\\\s\\\\p{Cc}])?$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return GroupChannelNameRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// GroupChannelNameRequestMultiError is an error wrapping multiple validation
+// errors returned by GroupChannelNameRequest.ValidateAll() if the designated
+// constraints aren't met.
+type GroupChannelNameRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GroupChannelNameRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GroupChannelNameRequestMultiError) AllErrors() []error { return m }
+
+// GroupChannelNameRequestValidationError is the validation error returned by
+// GroupChannelNameRequest.Validate if the designated constraints aren't met.
+type GroupChannelNameRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GroupChannelNameRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GroupChannelNameRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GroupChannelNameRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GroupChannelNameRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GroupChannelNameRequestValidationError) ErrorName() string {
+	return "GroupChannelNameRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e GroupChannelNameRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGroupChannelNameRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GroupChannelNameRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GroupChannelNameRequestValidationError{}
+
+var _GroupChannelNameRequest_GroupChannelName_Pattern = regexp.MustCompile("^[^\\s\\p{Cc}](?:[^\\p{Cc}]*[^\\s\\p{Cc}])?$")
+
+// Validate checks the field values on UsernameRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *UsernameRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UsernameRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UsernameRequestMultiError, or nil if none found.
+func (m *UsernameRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UsernameRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetUsername()); l < 1 || l > 64 {
+		err := UsernameRequestValidationError{
+			field:  "Username",
+			reason: "value length must be between 1 and 64 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_UsernameRequest_Username_Pattern.MatchString(m.GetUsername()) {
+		err := UsernameRequestValidationError{
+			field:  "Username",
+			reason: "value does not match regex pattern \"^[^\\\\s\\\\p{Cc}]+$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return UsernameRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// UsernameRequestMultiError is an error wrapping multiple validation errors
+// returned by UsernameRequest.ValidateAll() if the designated constraints
+// aren't met.
+type UsernameRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UsernameRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UsernameRequestMultiError) AllErrors() []error { return m }
+
+// UsernameRequestValidationError is the validation error returned by
+// UsernameRequest.Validate if the designated constraints aren't met.
+type UsernameRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UsernameRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UsernameRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UsernameRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UsernameRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UsernameRequestValidationError) ErrorName() string { return "UsernameRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e UsernameRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUsernameRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UsernameRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UsernameRequestValidationError{}
+
+var _UsernameRequest_Username_Pattern = regexp.MustCompile("^[^\\s\\p{Cc}]+$")
+
+// Validate checks the field values on ChatMessage with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ChatMessage) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ChatMessage with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ChatMessageMultiError, or
+// nil if none found.
+func (m *ChatMessage) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ChatMessage) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AttachmentId
+
+	// no validation rules for Kind
+
+	for idx, item := range m.GetActivity() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ChatMessageValidationError{
+						field:  fmt.Sprintf("Activity[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ChatMessageValidationError{
+						field:  fmt.Sprintf("Activity[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ChatMessageValidationError{
+					field:  fmt.Sprintf("Activity[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	switch m.Destination.(type) {
+
+	case *ChatMessage_GroupChannelName:
+
+		if l := utf8.RuneCountInString(m.GetGroupChannelName()); l < 1 || l > 128 {
+			err := ChatMessageValidationError{
+				field:  "GroupChannelName",
+				reason: "value length must be between 1 and 128 runes, inclusive",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+		if !_ChatMessage_GroupChannelName_Pattern.MatchString(m.GetGroupChannelName()) {
+			err := ChatMessageValidationError{
+				field:  "GroupChannelName",
+				reason: "value does not match regex pattern \"^[^\\\\s\\\\p{Cc}](?:[^\\\\p{Cc}]*[^\\\\s\\\\p{Cc}])?$\"",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	case *ChatMessage_Username:
+
+		if l := utf8.RuneCountInString(m.GetUsername()); l < 1 || l > 64 {
+			err := ChatMessageValidationError{
+				field:  "Username",
+				reason: "value length must be between 1 and 64 runes, inclusive",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+		if !_ChatMessage_Username_Pattern.MatchString(m.GetUsername()) {
+			err := ChatMessageValidationError{
+				field:  "Username",
+				reason: "value does not match regex pattern \"^[^\\\\s\\\\p{Cc}]+$\"",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	switch m.Content.(type) {
+
+	case *ChatMessage_Message:
+
+		if l := utf8.RuneCountInString(m.GetMessage()); l < 1 || l > 65536 {
+			err := ChatMessageValidationError{
+				field:  "Message",
+				reason: "value length must be between 1 and 65536 runes, inclusive",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	case *ChatMessage_Markdown:
+
+		if all {
+			switch v := interface{}(m.GetMarkdown()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ChatMessageValidationError{
+						field:  "Markdown",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ChatMessageValidationError{
+						field:  "Markdown",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetMarkdown()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ChatMessageValidationError{
+					field:  "Markdown",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *ChatMessage_Code:
+
+		if all {
+			switch v := interface{}(m.GetCode()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ChatMessageValidationError{
+						field:  "Code",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ChatMessageValidationError{
+						field:  "Code",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetCode()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ChatMessageValidationError{
+					field:  "Code",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *ChatMessage_LinkPreview:
+
+		if all {
+			switch v := interface{}(m.GetLinkPreview()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ChatMessageValidationError{
+						field:  "LinkPreview",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ChatMessageValidationError{
+						field:  "LinkPreview",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetLinkPreview()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ChatMessageValidationError{
+					field:  "LinkPreview",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *ChatMessage_Ciphertext:
+
+		if all {
+			switch v := interface{}(m.GetCiphertext()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ChatMessageValidationError{
+						field:  "Ciphertext",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ChatMessageValidationError{
+						field:  "Ciphertext",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetCiphertext()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ChatMessageValidationError{
+					field:  "Ciphertext",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	default:
+		err := ChatMessageValidationError{
+			field:  "Content",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+
+	}
+
+	if len(errors) > 0 {
+		return ChatMessageMultiError(errors)
+	}
+
+	return nil
+}
+
+// ChatMessageMultiError is an error wrapping multiple validation errors
+// returned by ChatMessage.ValidateAll() if the designated constraints aren't met.
+type ChatMessageMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ChatMessageMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ChatMessageMultiError) AllErrors() []error { return m }
+
+// ChatMessageValidationError is the validation error returned by
+// ChatMessage.Validate if the designated constraints aren't met.
+type ChatMessageValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ChatMessageValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ChatMessageValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ChatMessageValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ChatMessageValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ChatMessageValidationError) ErrorName() string { return "ChatMessageValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ChatMessageValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sChatMessage.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ChatMessageValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ChatMessageValidationError{}
+
+var _ChatMessage_GroupChannelName_Pattern = regexp.MustCompile("^[^\\s\\p{Cc}](?:[^\\p{Cc}]*[^\\s\\p{Cc}])?$")
+
+var _ChatMessage_Username_Pattern = regexp.MustCompile("^[^\\s\\p{Cc}]+$")
+
+// Validate checks the field values on Ciphertext with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Ciphertext) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Ciphertext with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CiphertextMultiError, or
+// nil if none found.
+func (m *Ciphertext) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Ciphertext) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(m.GetSenderIdentityKey()) != 32 {
+		err := CiphertextValidationError{
+			field:  "SenderIdentityKey",
+			reason: "value length must be 32 bytes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(m.GetEphemeralKey()) != 32 {
+		err := CiphertextValidationError{
+			field:  "EphemeralKey",
+			reason: "value length must be 32 bytes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetPrekeyId()) > 64 {
+		err := CiphertextValidationError{
+			field:  "PrekeyId",
+			reason: "value length must be at most 64 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(m.GetNonce()) != 12 {
+		err := CiphertextValidationError{
+			field:  "Nonce",
+			reason: "value length must be 12 bytes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if l := len(m.GetPayload()); l < 1 || l > 262144 {
+		err := CiphertextValidationError{
+			field:  "Payload",
+			reason: "value length must be between 1 and 262144 bytes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return CiphertextMultiError(errors)
+	}
+
+	return nil
+}
+
+// CiphertextMultiError is an error wrapping multiple validation errors
+// returned by Ciphertext.ValidateAll() if the designated constraints aren't met.
+type CiphertextMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CiphertextMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CiphertextMultiError) AllErrors() []error { return m }
+
+// CiphertextValidationError is the validation error returned by
+// Ciphertext.Validate if the designated constraints aren't met.
+type CiphertextValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CiphertextValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CiphertextValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CiphertextValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CiphertextValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CiphertextValidationError) ErrorName() string { return "CiphertextValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CiphertextValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCiphertext.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CiphertextValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CiphertextValidationError{}
+
+// Validate checks the field values on KeyBundle with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *KeyBundle) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on KeyBundle with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in KeyBundleMultiError, or nil
+// if none found.
+func (m *KeyBundle) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *KeyBundle) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(m.GetIdentityKey()) != 32 {
+		err := KeyBundleValidationError{
+			field:  "IdentityKey",
+			reason: "value length must be 32 bytes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(m.GetPrekeys()) > 100 {
+		err := KeyBundleValidationError{
+			field:  "Prekeys",
+			reason: "value must contain no more than 100 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	for idx, item := range m.GetPrekeys() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, KeyBundleValidationError{
+						field:  fmt.Sprintf("Prekeys[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, KeyBundleValidationError{
+						field:  fmt.Sprintf("Prekeys[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return KeyBundleValidationError{
+					field:  fmt.Sprintf("Prekeys[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return KeyBundleMultiError(errors)
+	}
+
+	return nil
+}
+
+// KeyBundleMultiError is an error wrapping multiple validation errors returned
+// by KeyBundle.ValidateAll() if the designated constraints aren't met.
+type KeyBundleMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m KeyBundleMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m KeyBundleMultiError) AllErrors() []error { return m }
+
+// KeyBundleValidationError is the validation error returned by
+// KeyBundle.Validate if the designated constraints aren't met.
+type KeyBundleValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e KeyBundleValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e KeyBundleValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e KeyBundleValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e KeyBundleValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e KeyBundleValidationError) ErrorName() string { return "KeyBundleValidationError" }
+
+// Error satisfies the builtin error interface
+func (e KeyBundleValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sKeyBundle.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = KeyBundleValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = KeyBundleValidationError{}
+
+// Validate checks the field values on Prekey with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Prekey) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Prekey with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in PrekeyMultiError, or nil if none found.
+func (m *Prekey) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Prekey) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetId()); l < 1 || l > 64 {
+		err := PrekeyValidationError{
+			field:  "Id",
+			reason: "value length must be between 1 and 64 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(m.GetPublicKey()) != 32 {
+		err := PrekeyValidationError{
+			field:  "PublicKey",
+			reason: "value length must be 32 bytes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return PrekeyMultiError(errors)
+	}
+
+	return nil
+}
+
+// PrekeyMultiError is an error wrapping multiple validation errors returned by
+// Prekey.ValidateAll() if the designated constraints aren't met.
+type PrekeyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PrekeyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PrekeyMultiError) AllErrors() []error { return m }
+
+// PrekeyValidationError is the validation error returned by Prekey.Validate if
+// the designated constraints aren't met.
+type PrekeyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PrekeyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PrekeyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PrekeyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PrekeyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PrekeyValidationError) ErrorName() string { return "PrekeyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e PrekeyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPrekey.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PrekeyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PrekeyValidationError{}
+
+// Validate checks the field values on GroupActivity with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *GroupActivity) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on GroupActivity with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in GroupActivityMultiError, or
+// nil if none found.
+func (m *GroupActivity) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *GroupActivity) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for GroupChannelName
+
+	// no validation rules for Unread
+
+	if all {
+		switch v := interface{}(m.GetLastMessageAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, GroupActivityValidationError{
+					field:  "LastMessageAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, GroupActivityValidationError{
+					field:  "LastMessageAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetLastMessageAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return GroupActivityValidationError{
+				field:  "LastMessageAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return GroupActivityMultiError(errors)
+	}
+
+	return nil
+}
+
+// GroupActivityMultiError is an error wrapping multiple validation errors
+// returned by GroupActivity.ValidateAll() if the designated constraints
+// aren't met.
+type GroupActivityMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GroupActivityMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GroupActivityMultiError) AllErrors() []error { return m }
+
+// GroupActivityValidationError is the validation error returned by
+// GroupActivity.Validate if the designated constraints aren't met.
+type GroupActivityValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GroupActivityValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GroupActivityValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GroupActivityValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GroupActivityValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GroupActivityValidationError) ErrorName() string { return "GroupActivityValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GroupActivityValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGroupActivity.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GroupActivityValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GroupActivityValidationError{}
+
+// Validate checks the field values on Markdown with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Markdown) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Markdown with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in MarkdownMultiError, or nil
+// if none found.
+func (m *Markdown) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Markdown) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetText()); l < 1 || l > 65536 {
+		err := MarkdownValidationError{
+			field:  "Text",
+			reason: "value length must be between 1 and 65536 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_Markdown_Text_Pattern.MatchString(m.GetText()) {
+		err := MarkdownValidationError{
+			field:  "Text",
+			reason: "value does not match regex pattern \"^(?:[^<!]|!+[^\\\\[<!]|!*<+[^A-Za-z/!?<])*!*<*$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return MarkdownMultiError(errors)
+	}
+
+	return nil
+}
+
+// MarkdownMultiError is an error wrapping multiple validation errors returned
+// by Markdown.ValidateAll() if the designated constraints aren't met.
+type MarkdownMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MarkdownMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MarkdownMultiError) AllErrors() []error { return m }
+
+// MarkdownValidationError is the validation error returned by
+// Markdown.Validate if the designated constraints aren't met.
+type MarkdownValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MarkdownValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MarkdownValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MarkdownValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MarkdownValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MarkdownValidationError) ErrorName() string { return "MarkdownValidationError" }
+
+// Error satisfies the builtin error interface
+func (e MarkdownValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMarkdown.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MarkdownValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MarkdownValidationError{}
+
+var _Markdown_Text_Pattern = regexp.MustCompile("^(?:[^<!]|!+[^\\[<!]|!*<+[^A-Za-z/!?<])*!*<*$")
+
+// Validate checks the field values on CodeBlock with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *CodeBlock) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CodeBlock with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CodeBlockMultiError, or nil
+// if none found.
+func (m *CodeBlock) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CodeBlock) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if !_CodeBlock_Language_Pattern.MatchString(m.GetLanguage()) {
+		err := CodeBlockValidationError{
+			field:  "Language",
+			reason: "value does not match regex pattern \"^[a-z0-9+#._-]{0,32}$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if l := utf8.RuneCountInString(m.GetCode()); l < 1 || l > 65536 {
+		err := CodeBlockValidationError{
+			field:  "Code",
+			reason: "value length must be between 1 and 65536 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return CodeBlockMultiError(errors)
+	}
+
+	return nil
+}
+
+// CodeBlockMultiError is an error wrapping multiple validation errors returned
+// by CodeBlock.ValidateAll() if the designated constraints aren't met.
+type CodeBlockMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CodeBlockMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CodeBlockMultiError) AllErrors() []error { return m }
+
+// CodeBlockValidationError is the validation error returned by
+// CodeBlock.Validate if the designated constraints aren't met.
+type CodeBlockValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CodeBlockValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CodeBlockValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CodeBlockValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CodeBlockValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CodeBlockValidationError) ErrorName() string { return "CodeBlockValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CodeBlockValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCodeBlock.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CodeBlockValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CodeBlockValidationError{}
+
+var _CodeBlock_Language_Pattern = regexp.MustCompile("^[a-z0-9+#._-]{0,32}$")
+
+// Validate checks the field values on LinkPreview with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *LinkPreview) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on LinkPreview with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in LinkPreviewMultiError, or
+// nil if none found.
+func (m *LinkPreview) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *LinkPreview) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if uri, err := url.Parse(m.GetUrl()); err != nil {
+		err = LinkPreviewValidationError{
+			field:  "Url",
+			reason: "value must be a valid URI",
+			cause:  err,
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	} else if !uri.IsAbs() {
+		err := LinkPreviewValidationError{
+			field:  "Url",
+			reason: "value must be absolute",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetTitle()) > 256 {
+		err := LinkPreviewValidationError{
+			field:  "Title",
+			reason: "value length must be at most 256 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetDescription()) > 1024 {
+		err := LinkPreviewValidationError{
+			field:  "Description",
+			reason: "value length must be at most 1024 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetImageUrl() != "" {
+
+		if uri, err := url.Parse(m.GetImageUrl()); err != nil {
+			err = LinkPreviewValidationError{
+				field:  "ImageUrl",
+				reason: "value must be a valid URI",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else if !uri.IsAbs() {
+			err := LinkPreviewValidationError{
+				field:  "ImageUrl",
+				reason: "value must be absolute",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return LinkPreviewMultiError(errors)
+	}
+
+	return nil
+}
+
+// LinkPreviewMultiError is an error wrapping multiple validation errors
+// returned by LinkPreview.ValidateAll() if the designated constraints aren't met.
+type LinkPreviewMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m LinkPreviewMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m LinkPreviewMultiError) AllErrors() []error { return m }
+
+// LinkPreviewValidationError is the validation error returned by
+// LinkPreview.Validate if the designated constraints aren't met.
+type LinkPreviewValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e LinkPreviewValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e LinkPreviewValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e LinkPreviewValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e LinkPreviewValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e LinkPreviewValidationError) ErrorName() string { return "LinkPreviewValidationError" }
+
+// Error satisfies the builtin error interface
+func (e LinkPreviewValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sLinkPreview.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = LinkPreviewValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = LinkPreviewValidationError{}
+
+// Validate checks the field values on Channels with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Channels) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Channels with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ChannelsMultiError, or nil
+// if none found.
+func (m *Channels) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Channels) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetItems() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ChannelsValidationError{
+						field:  fmt.Sprintf("Items[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ChannelsValidationError{
+						field:  fmt.Sprintf("Items[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ChannelsValidationError{
+					field:  fmt.Sprintf("Items[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return ChannelsMultiError(errors)
+	}
+
+	return nil
+}
+
+// ChannelsMultiError is an error wrapping multiple validation errors returned
+// by Channels.ValidateAll() if the designated constraints aren't met.
+type ChannelsMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ChannelsMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ChannelsMultiError) AllErrors() []error { return m }
+
+// ChannelsValidationError is the validation error returned by
+// Channels.Validate if the designated constraints aren't met.
+type ChannelsValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ChannelsValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ChannelsValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ChannelsValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ChannelsValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ChannelsValidationError) ErrorName() string { return "ChannelsValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ChannelsValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sChannels.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ChannelsValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ChannelsValidationError{}
+
+// Validate checks the field values on Usernames with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Usernames) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Usernames with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in UsernamesMultiError, or nil
+// if none found.
+func (m *Usernames) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Usernames) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return UsernamesMultiError(errors)
+	}
+
+	return nil
+}
+
+// UsernamesMultiError is an error wrapping multiple validation errors returned
+// by Usernames.ValidateAll() if the designated constraints aren't met.
+type UsernamesMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UsernamesMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UsernamesMultiError) AllErrors() []error { return m }
+
+// UsernamesValidationError is the validation error returned by
+// Usernames.Validate if the designated constraints aren't met.
+type UsernamesValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UsernamesValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UsernamesValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UsernamesValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UsernamesValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UsernamesValidationError) ErrorName() string { return "UsernamesValidationError" }
+
+// Error satisfies the builtin error interface
+func (e UsernamesValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUsernames.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UsernamesValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UsernamesValidationError{}
+
+// Validate checks the field values on AttachmentChunk with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AttachmentChunk) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AttachmentChunk with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AttachmentChunkMultiError, or nil if none found.
+func (m *AttachmentChunk) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AttachmentChunk) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	switch m.Payload.(type) {
+
+	case *AttachmentChunk_Info:
+
+		if all {
+			switch v := interface{}(m.GetInfo()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AttachmentChunkValidationError{
+						field:  "Info",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AttachmentChunkValidationError{
+						field:  "Info",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetInfo()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AttachmentChunkValidationError{
+					field:  "Info",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *AttachmentChunk_Data:
+
+		if len(m.GetData()) < 1 {
+			err := AttachmentChunkValidationError{
+				field:  "Data",
+				reason: "value length must be at least 1 bytes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	default:
+		err := AttachmentChunkValidationError{
+			field:  "Payload",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+
+	}
+
+	if len(errors) > 0 {
+		return AttachmentChunkMultiError(errors)
+	}
+
+	return nil
+}
+
+// AttachmentChunkMultiError is an error wrapping multiple validation errors
+// returned by AttachmentChunk.ValidateAll() if the designated constraints
+// aren't met.
+type AttachmentChunkMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AttachmentChunkMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AttachmentChunkMultiError) AllErrors() []error { return m }
+
+// AttachmentChunkValidationError is the validation error returned by
+// AttachmentChunk.Validate if the designated constraints aren't met.
+type AttachmentChunkValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AttachmentChunkValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AttachmentChunkValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AttachmentChunkValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AttachmentChunkValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AttachmentChunkValidationError) ErrorName() string { return "AttachmentChunkValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AttachmentChunkValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAttachmentChunk.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AttachmentChunkValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AttachmentChunkValidationError{}
+
+// Validate checks the field values on AttachmentInfo with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *AttachmentInfo) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AttachmentInfo with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in AttachmentInfoMultiError,
+// or nil if none found.
+func (m *AttachmentInfo) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AttachmentInfo) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetFileName()); l < 1 || l > 255 {
+		err := AttachmentInfoValidationError{
+			field:  "FileName",
+			reason: "value length must be between 1 and 255 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetMimeType()) < 1 {
+		err := AttachmentInfoValidationError{
+			field:  "MimeType",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Size
+
+	switch m.Destination.(type) {
+
+	case *AttachmentInfo_GroupChannelName:
+
+		if l := utf8.RuneCountInString(m.GetGroupChannelName()); l < 1 || l > 128 {
+			err := AttachmentInfoValidationError{
+				field:  "GroupChannelName",
+				reason: "value length must be between 1 and 128 runes, inclusive",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+		if !_AttachmentInfo_GroupChannelName_Pattern.MatchString(m.GetGroupChannelName()) {
+			err := AttachmentInfoValidationError{
+				field:  "GroupChannelName",
+				reason: "value does not match regex pattern \"^[^\\\\s\\\\p{Cc}](?:[^\\\\p{Cc}]*[^\\\\s\\\\p{Cc}])?$\"",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	case *AttachmentInfo_Username:
+
+		if l := utf8.RuneCountInString(m.GetUsername()); l < 1 || l > 64 {
+			err := AttachmentInfoValidationError{
+				field:  "Username",
+				reason: "value length must be between 1 and 64 runes, inclusive",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+		if !_AttachmentInfo_Username_Pattern.MatchString(m.GetUsername()) {
+			err := AttachmentInfoValidationError{
+				field:  "Username",
+				reason: "value does not match regex pattern \"^[^\\\\s\\\\p{Cc}]+$\"",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	default:
+		err := AttachmentInfoValidationError{
+			field:  "Destination",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+
+	}
+
+	if len(errors) > 0 {
+		return AttachmentInfoMultiError(errors)
+	}
+
+	return nil
+}
+
+// AttachmentInfoMultiError is an error wrapping multiple validation errors
+// returned by AttachmentInfo.ValidateAll() if the designated constraints
+// aren't met.
+type AttachmentInfoMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AttachmentInfoMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AttachmentInfoMultiError) AllErrors() []error { return m }
+
+// AttachmentInfoValidationError is the validation error returned by
+// AttachmentInfo.Validate if the designated constraints aren't met.
+type AttachmentInfoValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AttachmentInfoValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AttachmentInfoValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AttachmentInfoValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AttachmentInfoValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AttachmentInfoValidationError) ErrorName() string { return "AttachmentInfoValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AttachmentInfoValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAttachmentInfo.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AttachmentInfoValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AttachmentInfoValidationError{}
+
+var _AttachmentInfo_GroupChannelName_Pattern = regexp.MustCompile("^[^\\s\\p{Cc}](?:[^\\p{Cc}]*[^\\s\\p{Cc}])?$")
+
+var _AttachmentInfo_Username_Pattern = regexp.MustCompile("^[^\\s\\p{Cc}]+$")
+
+// Validate checks the field values on Attachment with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Attachment) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Attachment with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in AttachmentMultiError, or
+// nil if none found.
+func (m *Attachment) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Attachment) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return AttachmentMultiError(errors)
+	}
+
+	return nil
+}
+
+// AttachmentMultiError is an error wrapping multiple validation errors
+// returned by Attachment.ValidateAll() if the designated constraints aren't met.
+type AttachmentMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AttachmentMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AttachmentMultiError) AllErrors() []error { return m }
+
+// AttachmentValidationError is the validation error returned by
+// Attachment.Validate if the designated constraints aren't met.
+type AttachmentValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AttachmentValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AttachmentValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AttachmentValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AttachmentValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AttachmentValidationError) ErrorName() string { return "AttachmentValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AttachmentValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAttachment.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AttachmentValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AttachmentValidationError{}
+
+// Validate checks the field values on AttachmentRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AttachmentRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AttachmentRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AttachmentRequestMultiError, or nil if none found.
+func (m *AttachmentRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AttachmentRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetId()) < 1 {
+		err := AttachmentRequestValidationError{
+			field:  "Id",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return AttachmentRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// AttachmentRequestMultiError is an error wrapping multiple validation errors
+// returned by AttachmentRequest.ValidateAll() if the designated constraints
+// aren't met.
+type AttachmentRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AttachmentRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AttachmentRequestMultiError) AllErrors() []error { return m }
+
+// AttachmentRequestValidationError is the validation error returned by
+// AttachmentRequest.Validate if the designated constraints aren't met.
+type AttachmentRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AttachmentRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AttachmentRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AttachmentRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AttachmentRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AttachmentRequestValidationError) ErrorName() string {
+	return "AttachmentRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e AttachmentRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAttachmentRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AttachmentRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AttachmentRequestValidationError{}
+
+// Validate checks the field values on AuditLogQuery with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *AuditLogQuery) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AuditLogQuery with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in AuditLogQueryMultiError, or
+// nil if none found.
+func (m *AuditLogQuery) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AuditLogQuery) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetFrom()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AuditLogQueryValidationError{
+					field:  "From",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AuditLogQueryValidationError{
+					field:  "From",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetFrom()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AuditLogQueryValidationError{
+				field:  "From",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetTo()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AuditLogQueryValidationError{
+					field:  "To",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AuditLogQueryValidationError{
+					field:  "To",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTo()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AuditLogQueryValidationError{
+				field:  "To",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Actor
+
+	if m.GetLimit() > 10000 {
+		err := AuditLogQueryValidationError{
+			field:  "Limit",
+			reason: "value must be less than or equal to 10000",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return AuditLogQueryMultiError(errors)
+	}
+
+	return nil
+}
+
+// AuditLogQueryMultiError is an error wrapping multiple validation errors
+// returned by AuditLogQuery.ValidateAll() if the designated constraints
+// aren't met.
+type AuditLogQueryMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AuditLogQueryMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AuditLogQueryMultiError) AllErrors() []error { return m }
+
+// AuditLogQueryValidationError is the validation error returned by
+// AuditLogQuery.Validate if the designated constraints aren't met.
+type AuditLogQueryValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AuditLogQueryValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AuditLogQueryValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AuditLogQueryValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AuditLogQueryValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AuditLogQueryValidationError) ErrorName() string { return "AuditLogQueryValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AuditLogQueryValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAuditLogQuery.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AuditLogQueryValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AuditLogQueryValidationError{}
+
+// Validate checks the field values on AuditRecords with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *AuditRecords) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AuditRecords with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in AuditRecordsMultiError, or
+// nil if none found.
+func (m *AuditRecords) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AuditRecords) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetItems() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AuditRecordsValidationError{
+						field:  fmt.Sprintf("Items[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AuditRecordsValidationError{
+						field:  fmt.Sprintf("Items[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AuditRecordsValidationError{
+					field:  fmt.Sprintf("Items[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return AuditRecordsMultiError(errors)
+	}
+
+	return nil
+}
+
+// AuditRecordsMultiError is an error wrapping multiple validation errors
+// returned by AuditRecords.ValidateAll() if the designated constraints aren't met.
+type AuditRecordsMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AuditRecordsMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AuditRecordsMultiError) AllErrors() []error { return m }
+
+// AuditRecordsValidationError is the validation error returned by
+// AuditRecords.Validate if the designated constraints aren't met.
+type AuditRecordsValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AuditRecordsValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AuditRecordsValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AuditRecordsValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AuditRecordsValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AuditRecordsValidationError) ErrorName() string { return "AuditRecordsValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AuditRecordsValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAuditRecords.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AuditRecordsValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AuditRecordsValidationError{}
+
+// Validate checks the field values on Sessions with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Sessions) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Sessions with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in SessionsMultiError, or nil
+// if none found.
+func (m *Sessions) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Sessions) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetItems() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SessionsValidationError{
+						field:  fmt.Sprintf("Items[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SessionsValidationError{
+						field:  fmt.Sprintf("Items[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SessionsValidationError{
+					field:  fmt.Sprintf("Items[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return SessionsMultiError(errors)
+	}
+
+	return nil
+}
+
+// SessionsMultiError is an error wrapping multiple validation errors returned
+// by Sessions.ValidateAll() if the designated constraints aren't met.
+type SessionsMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SessionsMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SessionsMultiError) AllErrors() []error { return m }
+
+// SessionsValidationError is the validation error returned by
+// Sessions.Validate if the designated constraints aren't met.
+type SessionsValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SessionsValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SessionsValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SessionsValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SessionsValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SessionsValidationError) ErrorName() string { return "SessionsValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SessionsValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSessions.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SessionsValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SessionsValidationError{}
+
+// Validate checks the field values on Group with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Group) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Group with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in GroupMultiError, or nil if none found.
+func (m *Group) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Group) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for GroupChannelName
+
+	for idx, item := range m.GetMembers() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, GroupValidationError{
+						field:  fmt.Sprintf("Members[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, GroupValidationError{
+						field:  fmt.Sprintf("Members[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GroupValidationError{
+					field:  fmt.Sprintf("Members[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return GroupMultiError(errors)
+	}
+
+	return nil
+}
+
+// GroupMultiError is an error wrapping multiple validation errors returned by
+// Group.ValidateAll() if the designated constraints aren't met.
+type GroupMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m GroupMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m GroupMultiError) AllErrors() []error { return m }
+
+// GroupValidationError is the validation error returned by Group.Validate if
+// the designated constraints aren't met.
+type GroupValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GroupValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GroupValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GroupValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GroupValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GroupValidationError) ErrorName() string { return "GroupValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GroupValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGroup.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GroupValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GroupValidationError{}
+
+// Validate checks the field values on BroadcastRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *BroadcastRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on BroadcastRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// BroadcastRequestMultiError, or nil if none found.
+func (m *BroadcastRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *BroadcastRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetMessage()); l < 1 || l > 65536 {
+		err := BroadcastRequestValidationError{
+			field:  "Message",
+			reason: "value length must be between 1 and 65536 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetStartAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, BroadcastRequestValidationError{
+					field:  "StartAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, BroadcastRequestValidationError{
+					field:  "StartAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetStartAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return BroadcastRequestValidationError{
+				field:  "StartAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetExpireAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, BroadcastRequestValidationError{
+					field:  "ExpireAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, BroadcastRequestValidationError{
+					field:  "ExpireAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetExpireAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return BroadcastRequestValidationError{
+				field:  "ExpireAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return BroadcastRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// BroadcastRequestMultiError is an error wrapping multiple validation errors
+// returned by BroadcastRequest.ValidateAll() if the designated constraints
+// aren't met.
+type BroadcastRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m BroadcastRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m BroadcastRequestMultiError) AllErrors() []error { return m }
+
+// BroadcastRequestValidationError is the validation error returned by
+// BroadcastRequest.Validate if the designated constraints aren't met.
+type BroadcastRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BroadcastRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BroadcastRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BroadcastRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BroadcastRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BroadcastRequestValidationError) ErrorName() string { return "BroadcastRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e BroadcastRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBroadcastRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BroadcastRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BroadcastRequestValidationError{}
+
+// Validate checks the field values on Announcement with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Announcement) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Announcement with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in AnnouncementMultiError, or
+// nil if none found.
+func (m *Announcement) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Announcement) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for Message
+
+	// no validation rules for Author
+
+	if all {
+		switch v := interface{}(m.GetStartAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AnnouncementValidationError{
+					field:  "StartAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AnnouncementValidationError{
+					field:  "StartAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetStartAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AnnouncementValidationError{
+				field:  "StartAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetExpireAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AnnouncementValidationError{
+					field:  "ExpireAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AnnouncementValidationError{
+					field:  "ExpireAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetExpireAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AnnouncementValidationError{
+				field:  "ExpireAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return AnnouncementMultiError(errors)
+	}
+
+	return nil
+}
+
+// AnnouncementMultiError is an error wrapping multiple validation errors
+// returned by Announcement.ValidateAll() if the designated constraints aren't met.
+type AnnouncementMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AnnouncementMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AnnouncementMultiError) AllErrors() []error { return m }
+
+// AnnouncementValidationError is the validation error returned by
+// Announcement.Validate if the designated constraints aren't met.
+type AnnouncementValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AnnouncementValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AnnouncementValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AnnouncementValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AnnouncementValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AnnouncementValidationError) ErrorName() string { return "AnnouncementValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AnnouncementValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAnnouncement.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AnnouncementValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AnnouncementValidationError{}
+
+// Validate checks the field values on Announcements with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Announcements) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Announcements with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in AnnouncementsMultiError, or
+// nil if none found.
+func (m *Announcements) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Announcements) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetItems() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AnnouncementsValidationError{
+						field:  fmt.Sprintf("Items[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AnnouncementsValidationError{
+						field:  fmt.Sprintf("Items[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AnnouncementsValidationError{
+					field:  fmt.Sprintf("Items[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return AnnouncementsMultiError(errors)
+	}
+
+	return nil
+}
+
+// AnnouncementsMultiError is an error wrapping multiple validation errors
+// returned by Announcements.ValidateAll() if the designated constraints
+// aren't met.
+type AnnouncementsMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AnnouncementsMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AnnouncementsMultiError) AllErrors() []error { return m }
+
+// AnnouncementsValidationError is the validation error returned by
+// Announcements.Validate if the designated constraints aren't met.
+type AnnouncementsValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AnnouncementsValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AnnouncementsValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AnnouncementsValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AnnouncementsValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AnnouncementsValidationError) ErrorName() string { return "AnnouncementsValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AnnouncementsValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAnnouncements.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AnnouncementsValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AnnouncementsValidationError{}
+
+// Validate checks the field values on AnnouncementRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *AnnouncementRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AnnouncementRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AnnouncementRequestMultiError, or nil if none found.
+func (m *AnnouncementRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AnnouncementRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetId()) < 1 {
+		err := AnnouncementRequestValidationError{
+			field:  "Id",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return AnnouncementRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// AnnouncementRequestMultiError is an error wrapping multiple validation
+// errors returned by AnnouncementRequest.ValidateAll() if the designated
+// constraints aren't met.
+type AnnouncementRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AnnouncementRequestMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -90,11 +3614,11 @@ func (m ConnectRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m ConnectRequestMultiError) AllErrors() []error { return m }
+func (m AnnouncementRequestMultiError) AllErrors() []error { return m }
 
-// ConnectRequestValidationError is the validation error returned by
-// ConnectRequest.Validate if the designated constraints aren't met.
-type ConnectRequestValidationError struct {
+// AnnouncementRequestValidationError is the validation error returned by
+// AnnouncementRequest.Validate if the designated constraints aren't met.
+type AnnouncementRequestValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -102,22 +3626,24 @@ type ConnectRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e ConnectRequestValidationError) Field() string { return e.field }
+func (e AnnouncementRequestValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e ConnectRequestValidationError) Reason() string { return e.reason }
+func (e AnnouncementRequestValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e ConnectRequestValidationError) Cause() error { return e.cause }
+func (e AnnouncementRequestValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e ConnectRequestValidationError) Key() bool { return e.key }
+func (e AnnouncementRequestValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e ConnectRequestValidationError) ErrorName() string { return "ConnectRequestValidationError" }
+func (e AnnouncementRequestValidationError) ErrorName() string {
+	return "AnnouncementRequestValidationError"
+}
 
 // Error satisfies the builtin error interface
-func (e ConnectRequestValidationError) Error() string {
+func (e AnnouncementRequestValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -129,14 +3655,14 @@ func (e ConnectRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sConnectRequest.%s: %s%s",
+		"invalid %sAnnouncementRequest.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = ConnectRequestValidationError{}
+var _ error = AnnouncementRequestValidationError{}
 
 var _ interface {
 	Field() string
@@ -144,55 +3670,48 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = ConnectRequestValidationError{}
+} = AnnouncementRequestValidationError{}
 
-// Validate checks the field values on GroupChannelNameRequest with the rules
-// defined in the proto definition for this message. If any rules are
-// violated, the first error encountered is returned, or nil if there are no violations.
-func (m *GroupChannelNameRequest) Validate() error {
+// Validate checks the field values on Channels_Channel with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *Channels_Channel) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on GroupChannelNameRequest with the
-// rules defined in the proto definition for this message. If any rules are
+// ValidateAll checks the field values on Channels_Channel with the rules
+// defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// GroupChannelNameRequestMultiError, or nil if none found.
-func (m *GroupChannelNameRequest) ValidateAll() error {
+// Channels_ChannelMultiError, or nil if none found.
+func (m *Channels_Channel) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *GroupChannelNameRequest) validate(all bool) error {
+func (m *Channels_Channel) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	if utf8.RuneCountInString(m.GetGroupChannelName()) < 1 {
-		err := GroupChannelNameRequestValidationError{
-			field:  "GroupChannelName",
-			reason: "value length must be at least 1 runes",
-		}
-		if !all {
-			return err
-		}
-		errors = append(errors, err)
-	}
+	// no validation rules for GroupChannelName
+
+	// no validation rules for Type
 
 	if len(errors) > 0 {
-		return GroupChannelNameRequestMultiError(errors)
+		return Channels_ChannelMultiError(errors)
 	}
 
 	return nil
 }
 
-// GroupChannelNameRequestMultiError is an error wrapping multiple validation
-// errors returned by GroupChannelNameRequest.ValidateAll() if the designated
-// constraints aren't met.
-type GroupChannelNameRequestMultiError []error
+// Channels_ChannelMultiError is an error wrapping multiple validation errors
+// returned by Channels_Channel.ValidateAll() if the designated constraints
+// aren't met.
+type Channels_ChannelMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m GroupChannelNameRequestMultiError) Error() string {
+func (m Channels_ChannelMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -201,11 +3720,11 @@ func (m GroupChannelNameRequestMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m GroupChannelNameRequestMultiError) AllErrors() []error { return m }
+func (m Channels_ChannelMultiError) AllErrors() []error { return m }
 
-// GroupChannelNameRequestValidationError is the validation error returned by
-// GroupChannelNameRequest.Validate if the designated constraints aren't met.
-type GroupChannelNameRequestValidationError struct {
+// Channels_ChannelValidationError is the validation error returned by
+// Channels_Channel.Validate if the designated constraints aren't met.
+type Channels_ChannelValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -213,24 +3732,22 @@ type GroupChannelNameRequestValidationError struct {
 }
 
 // Field function returns field value.
-func (e GroupChannelNameRequestValidationError) Field() string { return e.field }
+func (e Channels_ChannelValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e GroupChannelNameRequestValidationError) Reason() string { return e.reason }
+func (e Channels_ChannelValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e GroupChannelNameRequestValidationError) Cause() error { return e.cause }
+func (e Channels_ChannelValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e GroupChannelNameRequestValidationError) Key() bool { return e.key }
+func (e Channels_ChannelValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e GroupChannelNameRequestValidationError) ErrorName() string {
-	return "GroupChannelNameRequestValidationError"
-}
+func (e Channels_ChannelValidationError) ErrorName() string { return "Channels_ChannelValidationError" }
 
 // Error satisfies the builtin error interface
-func (e GroupChannelNameRequestValidationError) Error() string {
+func (e Channels_ChannelValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -242,14 +3759,14 @@ func (e GroupChannelNameRequestValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sGroupChannelNameRequest.%s: %s%s",
+		"invalid %sChannels_Channel.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = GroupChannelNameRequestValidationError{}
+var _ error = Channels_ChannelValidationError{}
 
 var _ interface {
 	Field() string
@@ -257,84 +3774,81 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = GroupChannelNameRequestValidationError{}
+} = Channels_ChannelValidationError{}
 
-// Validate checks the field values on ChatMessage with the rules defined in
-// the proto definition for this message. If any rules are violated, the first
-// error encountered is returned, or nil if there are no violations.
-func (m *ChatMessage) Validate() error {
+// Validate checks the field values on AuditRecords_Record with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *AuditRecords_Record) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on ChatMessage with the rules defined in
-// the proto definition for this message. If any rules are violated, the
-// result is a list of violation errors wrapped in ChatMessageMultiError, or
-// nil if none found.
-func (m *ChatMessage) ValidateAll() error {
+// ValidateAll checks the field values on AuditRecords_Record with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AuditRecords_RecordMultiError, or nil if none found.
+func (m *AuditRecords_Record) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *ChatMessage) validate(all bool) error {
+func (m *AuditRecords_Record) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	if utf8.RuneCountInString(m.GetMessage()) < 1 {
-		err := ChatMessageValidationError{
-			field:  "Message",
-			reason: "value length must be at least 1 runes",
+	if all {
+		switch v := interface{}(m.GetTime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AuditRecords_RecordValidationError{
+					field:  "Time",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AuditRecords_RecordValidationError{
+					field:  "Time",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
 		}
-		if !all {
-			return err
+	} else if v, ok := interface{}(m.GetTime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AuditRecords_RecordValidationError{
+				field:  "Time",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
 		}
-		errors = append(errors, err)
 	}
 
-	switch m.Destination.(type) {
-
-	case *ChatMessage_GroupChannelName:
-
-		if utf8.RuneCountInString(m.GetGroupChannelName()) < 1 {
-			err := ChatMessageValidationError{
-				field:  "GroupChannelName",
-				reason: "value length must be at least 1 runes",
-			}
-			if !all {
-				return err
-			}
-			errors = append(errors, err)
-		}
+	// no validation rules for RequestId
 
-	case *ChatMessage_Username:
+	// no validation rules for Actor
 
-		if utf8.RuneCountInString(m.GetUsername()) < 1 {
-			err := ChatMessageValidationError{
-				field:  "Username",
-				reason: "value length must be at least 1 runes",
-			}
-			if !all {
-				return err
-			}
-			errors = append(errors, err)
-		}
+	// no validation rules for Action
 
-	}
+	// no validation rules for Target
 
 	if len(errors) > 0 {
-		return ChatMessageMultiError(errors)
+		return AuditRecords_RecordMultiError(errors)
 	}
 
 	return nil
 }
 
-// ChatMessageMultiError is an error wrapping multiple validation errors
-// returned by ChatMessage.ValidateAll() if the designated constraints aren't met.
-type ChatMessageMultiError []error
+// AuditRecords_RecordMultiError is an error wrapping multiple validation
+// errors returned by AuditRecords_Record.ValidateAll() if the designated
+// constraints aren't met.
+type AuditRecords_RecordMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m ChatMessageMultiError) Error() string {
+func (m AuditRecords_RecordMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -343,11 +3857,11 @@ func (m ChatMessageMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m ChatMessageMultiError) AllErrors() []error { return m }
+func (m AuditRecords_RecordMultiError) AllErrors() []error { return m }
 
-// ChatMessageValidationError is the validation error returned by
-// ChatMessage.Validate if the designated constraints aren't met.
-type ChatMessageValidationError struct {
+// AuditRecords_RecordValidationError is the validation error returned by
+// AuditRecords_Record.Validate if the designated constraints aren't met.
+type AuditRecords_RecordValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -355,22 +3869,24 @@ type ChatMessageValidationError struct {
 }
 
 // Field function returns field value.
-func (e ChatMessageValidationError) Field() string { return e.field }
+func (e AuditRecords_RecordValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e ChatMessageValidationError) Reason() string { return e.reason }
+func (e AuditRecords_RecordValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e ChatMessageValidationError) Cause() error { return e.cause }
+func (e AuditRecords_RecordValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e ChatMessageValidationError) Key() bool { return e.key }
+func (e AuditRecords_RecordValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e ChatMessageValidationError) ErrorName() string { return "ChatMessageValidationError" }
+func (e AuditRecords_RecordValidationError) ErrorName() string {
+	return "AuditRecords_RecordValidationError"
+}
 
 // Error satisfies the builtin error interface
-func (e ChatMessageValidationError) Error() string {
+func (e AuditRecords_RecordValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -382,14 +3898,14 @@ func (e ChatMessageValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sChatMessage.%s: %s%s",
+		"invalid %sAuditRecords_Record.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = ChatMessageValidationError{}
+var _ error = AuditRecords_RecordValidationError{}
 
 var _ interface {
 	Field() string
@@ -397,77 +3913,79 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = ChatMessageValidationError{}
+} = AuditRecords_RecordValidationError{}
 
-// Validate checks the field values on Channels with the rules defined in the
-// proto definition for this message. If any rules are violated, the first
-// error encountered is returned, or nil if there are no violations.
-func (m *Channels) Validate() error {
+// Validate checks the field values on Sessions_Session with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *Sessions_Session) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on Channels with the rules defined in
-// the proto definition for this message. If any rules are violated, the
-// result is a list of violation errors wrapped in ChannelsMultiError, or nil
-// if none found.
-func (m *Channels) ValidateAll() error {
+// ValidateAll checks the field values on Sessions_Session with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// Sessions_SessionMultiError, or nil if none found.
+func (m *Sessions_Session) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *Channels) validate(all bool) error {
+func (m *Sessions_Session) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	for idx, item := range m.GetItems() {
-		_, _ = idx, item
+	// no validation rules for Username
 
-		if all {
-			switch v := interface{}(item).(type) {
-			case interface{ ValidateAll() error }:
-				if err := v.ValidateAll(); err != nil {
-					errors = append(errors, ChannelsValidationError{
-						field:  fmt.Sprintf("Items[%v]", idx),
-						reason: "embedded message failed validation",
-						cause:  err,
-					})
-				}
-			case interface{ Validate() error }:
-				if err := v.Validate(); err != nil {
-					errors = append(errors, ChannelsValidationError{
-						field:  fmt.Sprintf("Items[%v]", idx),
-						reason: "embedded message failed validation",
-						cause:  err,
-					})
-				}
+	if all {
+		switch v := interface{}(m.GetConnectedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, Sessions_SessionValidationError{
+					field:  "ConnectedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
 			}
-		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+		case interface{ Validate() error }:
 			if err := v.Validate(); err != nil {
-				return ChannelsValidationError{
-					field:  fmt.Sprintf("Items[%v]", idx),
+				errors = append(errors, Sessions_SessionValidationError{
+					field:  "ConnectedAt",
 					reason: "embedded message failed validation",
 					cause:  err,
-				}
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetConnectedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return Sessions_SessionValidationError{
+				field:  "ConnectedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
 			}
 		}
-
 	}
 
+	// no validation rules for QueueDepth
+
+	// no validation rules for Node
+
 	if len(errors) > 0 {
-		return ChannelsMultiError(errors)
+		return Sessions_SessionMultiError(errors)
 	}
 
 	return nil
 }
 
-// ChannelsMultiError is an error wrapping multiple validation errors returned
-// by Channels.ValidateAll() if the designated constraints aren't met.
-type ChannelsMultiError []error
+// Sessions_SessionMultiError is an error wrapping multiple validation errors
+// returned by Sessions_Session.ValidateAll() if the designated constraints
+// aren't met.
+type Sessions_SessionMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m ChannelsMultiError) Error() string {
+func (m Sessions_SessionMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -476,11 +3994,11 @@ func (m ChannelsMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m ChannelsMultiError) AllErrors() []error { return m }
+func (m Sessions_SessionMultiError) AllErrors() []error { return m }
 
-// ChannelsValidationError is the validation error returned by
-// Channels.Validate if the designated constraints aren't met.
-type ChannelsValidationError struct {
+// Sessions_SessionValidationError is the validation error returned by
+// Sessions_Session.Validate if the designated constraints aren't met.
+type Sessions_SessionValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -488,22 +4006,22 @@ type ChannelsValidationError struct {
 }
 
 // Field function returns field value.
-func (e ChannelsValidationError) Field() string { return e.field }
+func (e Sessions_SessionValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e ChannelsValidationError) Reason() string { return e.reason }
+func (e Sessions_SessionValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e ChannelsValidationError) Cause() error { return e.cause }
+func (e Sessions_SessionValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e ChannelsValidationError) Key() bool { return e.key }
+func (e Sessions_SessionValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e ChannelsValidationError) ErrorName() string { return "ChannelsValidationError" }
+func (e Sessions_SessionValidationError) ErrorName() string { return "Sessions_SessionValidationError" }
 
 // Error satisfies the builtin error interface
-func (e ChannelsValidationError) Error() string {
+func (e Sessions_SessionValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -515,14 +4033,14 @@ func (e ChannelsValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sChannels.%s: %s%s",
+		"invalid %sSessions_Session.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = ChannelsValidationError{}
+var _ error = Sessions_SessionValidationError{}
 
 var _ interface {
 	Field() string
@@ -530,48 +4048,51 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = ChannelsValidationError{}
+} = Sessions_SessionValidationError{}
 
-// Validate checks the field values on Channels_Channel with the rules defined
-// in the proto definition for this message. If any rules are violated, the
-// first error encountered is returned, or nil if there are no violations.
-func (m *Channels_Channel) Validate() error {
+// Validate checks the field values on Group_Member with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Group_Member) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on Channels_Channel with the rules
-// defined in the proto definition for this message. If any rules are
-// violated, the result is a list of violation errors wrapped in
-// Channels_ChannelMultiError, or nil if none found.
-func (m *Channels_Channel) ValidateAll() error {
+// ValidateAll checks the field values on Group_Member with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in Group_MemberMultiError, or
+// nil if none found.
+func (m *Group_Member) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *Channels_Channel) validate(all bool) error {
+func (m *Group_Member) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for GroupChannelName
+	// no validation rules for Username
 
-	// no validation rules for Type
+	// no validation rules for Connected
+
+	// no validation rules for QueueDepth
+
+	// no validation rules for Node
 
 	if len(errors) > 0 {
-		return Channels_ChannelMultiError(errors)
+		return Group_MemberMultiError(errors)
 	}
 
 	return nil
 }
 
-// Channels_ChannelMultiError is an error wrapping multiple validation errors
-// returned by Channels_Channel.ValidateAll() if the designated constraints
-// aren't met.
-type Channels_ChannelMultiError []error
+// Group_MemberMultiError is an error wrapping multiple validation errors
+// returned by Group_Member.ValidateAll() if the designated constraints aren't met.
+type Group_MemberMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m Channels_ChannelMultiError) Error() string {
+func (m Group_MemberMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -580,11 +4101,11 @@ func (m Channels_ChannelMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m Channels_ChannelMultiError) AllErrors() []error { return m }
+func (m Group_MemberMultiError) AllErrors() []error { return m }
 
-// Channels_ChannelValidationError is the validation error returned by
-// Channels_Channel.Validate if the designated constraints aren't met.
-type Channels_ChannelValidationError struct {
+// Group_MemberValidationError is the validation error returned by
+// Group_Member.Validate if the designated constraints aren't met.
+type Group_MemberValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -592,22 +4113,22 @@ type Channels_ChannelValidationError struct {
 }
 
 // Field function returns field value.
-func (e Channels_ChannelValidationError) Field() string { return e.field }
+func (e Group_MemberValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e Channels_ChannelValidationError) Reason() string { return e.reason }
+func (e Group_MemberValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e Channels_ChannelValidationError) Cause() error { return e.cause }
+func (e Group_MemberValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e Channels_ChannelValidationError) Key() bool { return e.key }
+func (e Group_MemberValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e Channels_ChannelValidationError) ErrorName() string { return "Channels_ChannelValidationError" }
+func (e Group_MemberValidationError) ErrorName() string { return "Group_MemberValidationError" }
 
 // Error satisfies the builtin error interface
-func (e Channels_ChannelValidationError) Error() string {
+func (e Group_MemberValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -619,14 +4140,14 @@ func (e Channels_ChannelValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sChannels_Channel.%s: %s%s",
+		"invalid %sGroup_Member.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = Channels_ChannelValidationError{}
+var _ error = Group_MemberValidationError{}
 
 var _ interface {
 	Field() string
@@ -634,4 +4155,4 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = Channels_ChannelValidationError{}
+} = Group_MemberValidationError{}
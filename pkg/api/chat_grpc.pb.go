@@ -29,6 +29,15 @@ type ChatClient interface {
 	LeaveGroupChat(ctx context.Context, in *GroupChannelNameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	ListChannels(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Channels, error)
 	SendMessage(ctx context.Context, in *ChatMessage, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	BlockUser(ctx context.Context, in *UsernameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UnblockUser(ctx context.Context, in *UsernameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListBlocked(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Usernames, error)
+	UploadAttachment(ctx context.Context, opts ...grpc.CallOption) (Chat_UploadAttachmentClient, error)
+	DownloadAttachment(ctx context.Context, in *AttachmentRequest, opts ...grpc.CallOption) (Chat_DownloadAttachmentClient, error)
+	// UploadKeys replaces public keys other users encrypt direct messages to the user with
+	UploadKeys(ctx context.Context, in *KeyBundle, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetKeys provides public keys of the user, a one-time prekey handed out is removed from the directory
+	GetKeys(ctx context.Context, in *UsernameRequest, opts ...grpc.CallOption) (*KeyBundle, error)
 }
 
 type chatClient struct {
@@ -116,6 +125,117 @@ func (c *chatClient) SendMessage(ctx context.Context, in *ChatMessage, opts ...g
 	return out, nil
 }
 
+func (c *chatClient) BlockUser(ctx context.Context, in *UsernameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.Chat/BlockUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) UnblockUser(ctx context.Context, in *UsernameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.Chat/UnblockUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) ListBlocked(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Usernames, error) {
+	out := new(Usernames)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.Chat/ListBlocked", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) UploadAttachment(ctx context.Context, opts ...grpc.CallOption) (Chat_UploadAttachmentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Chat_ServiceDesc.Streams[1], "/b2bchatapi.Chat/UploadAttachment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chatUploadAttachmentClient{stream}
+	return x, nil
+}
+
+type Chat_UploadAttachmentClient interface {
+	Send(*AttachmentChunk) error
+	CloseAndRecv() (*Attachment, error)
+	grpc.ClientStream
+}
+
+type chatUploadAttachmentClient struct {
+	grpc.ClientStream
+}
+
+func (x *chatUploadAttachmentClient) Send(m *AttachmentChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *chatUploadAttachmentClient) CloseAndRecv() (*Attachment, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Attachment)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *chatClient) DownloadAttachment(ctx context.Context, in *AttachmentRequest, opts ...grpc.CallOption) (Chat_DownloadAttachmentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Chat_ServiceDesc.Streams[2], "/b2bchatapi.Chat/DownloadAttachment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chatDownloadAttachmentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Chat_DownloadAttachmentClient interface {
+	Recv() (*AttachmentChunk, error)
+	grpc.ClientStream
+}
+
+type chatDownloadAttachmentClient struct {
+	grpc.ClientStream
+}
+
+func (x *chatDownloadAttachmentClient) Recv() (*AttachmentChunk, error) {
+	m := new(AttachmentChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *chatClient) UploadKeys(ctx context.Context, in *KeyBundle, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.Chat/UploadKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) GetKeys(ctx context.Context, in *UsernameRequest, opts ...grpc.CallOption) (*KeyBundle, error) {
+	out := new(KeyBundle)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.Chat/GetKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChatServer is the server API for Chat service.
 // All implementations must embed UnimplementedChatServer
 // for forward compatibility
@@ -126,6 +246,15 @@ type ChatServer interface {
 	LeaveGroupChat(context.Context, *GroupChannelNameRequest) (*emptypb.Empty, error)
 	ListChannels(context.Context, *emptypb.Empty) (*Channels, error)
 	SendMessage(context.Context, *ChatMessage) (*emptypb.Empty, error)
+	BlockUser(context.Context, *UsernameRequest) (*emptypb.Empty, error)
+	UnblockUser(context.Context, *UsernameRequest) (*emptypb.Empty, error)
+	ListBlocked(context.Context, *emptypb.Empty) (*Usernames, error)
+	UploadAttachment(Chat_UploadAttachmentServer) error
+	DownloadAttachment(*AttachmentRequest, Chat_DownloadAttachmentServer) error
+	// UploadKeys replaces public keys other users encrypt direct messages to the user with
+	UploadKeys(context.Context, *KeyBundle) (*emptypb.Empty, error)
+	// GetKeys provides public keys of the user, a one-time prekey handed out is removed from the directory
+	GetKeys(context.Context, *UsernameRequest) (*KeyBundle, error)
 	mustEmbedUnimplementedChatServer()
 }
 
@@ -151,6 +280,27 @@ func (UnimplementedChatServer) ListChannels(context.Context, *emptypb.Empty) (*C
 func (UnimplementedChatServer) SendMessage(context.Context, *ChatMessage) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
 }
+func (UnimplementedChatServer) BlockUser(context.Context, *UsernameRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockUser not implemented")
+}
+func (UnimplementedChatServer) UnblockUser(context.Context, *UsernameRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnblockUser not implemented")
+}
+func (UnimplementedChatServer) ListBlocked(context.Context, *emptypb.Empty) (*Usernames, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlocked not implemented")
+}
+func (UnimplementedChatServer) UploadAttachment(Chat_UploadAttachmentServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadAttachment not implemented")
+}
+func (UnimplementedChatServer) DownloadAttachment(*AttachmentRequest, Chat_DownloadAttachmentServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadAttachment not implemented")
+}
+func (UnimplementedChatServer) UploadKeys(context.Context, *KeyBundle) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadKeys not implemented")
+}
+func (UnimplementedChatServer) GetKeys(context.Context, *UsernameRequest) (*KeyBundle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeys not implemented")
+}
 func (UnimplementedChatServer) mustEmbedUnimplementedChatServer() {}
 
 // UnsafeChatServer may be embedded to opt out of forward compatibility for this service.
@@ -275,6 +425,143 @@ func _Chat_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Chat_BlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsernameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).BlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.Chat/BlockUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).BlockUser(ctx, req.(*UsernameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_UnblockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsernameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).UnblockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.Chat/UnblockUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).UnblockUser(ctx, req.(*UsernameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_ListBlocked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).ListBlocked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.Chat/ListBlocked",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).ListBlocked(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_UploadAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChatServer).UploadAttachment(&chatUploadAttachmentServer{stream})
+}
+
+type Chat_UploadAttachmentServer interface {
+	SendAndClose(*Attachment) error
+	Recv() (*AttachmentChunk, error)
+	grpc.ServerStream
+}
+
+type chatUploadAttachmentServer struct {
+	grpc.ServerStream
+}
+
+func (x *chatUploadAttachmentServer) SendAndClose(m *Attachment) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *chatUploadAttachmentServer) Recv() (*AttachmentChunk, error) {
+	m := new(AttachmentChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Chat_DownloadAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AttachmentRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatServer).DownloadAttachment(m, &chatDownloadAttachmentServer{stream})
+}
+
+type Chat_DownloadAttachmentServer interface {
+	Send(*AttachmentChunk) error
+	grpc.ServerStream
+}
+
+type chatDownloadAttachmentServer struct {
+	grpc.ServerStream
+}
+
+func (x *chatDownloadAttachmentServer) Send(m *AttachmentChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Chat_UploadKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyBundle)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).UploadKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.Chat/UploadKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).UploadKeys(ctx, req.(*KeyBundle))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_GetKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsernameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).GetKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.Chat/GetKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).GetKeys(ctx, req.(*UsernameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Chat_ServiceDesc is the grpc.ServiceDesc for Chat service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -302,6 +589,26 @@ var Chat_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendMessage",
 			Handler:    _Chat_SendMessage_Handler,
 		},
+		{
+			MethodName: "BlockUser",
+			Handler:    _Chat_BlockUser_Handler,
+		},
+		{
+			MethodName: "UnblockUser",
+			Handler:    _Chat_UnblockUser_Handler,
+		},
+		{
+			MethodName: "ListBlocked",
+			Handler:    _Chat_ListBlocked_Handler,
+		},
+		{
+			MethodName: "UploadKeys",
+			Handler:    _Chat_UploadKeys_Handler,
+		},
+		{
+			MethodName: "GetKeys",
+			Handler:    _Chat_GetKeys_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -309,6 +616,360 @@ var Chat_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Chat_Connect_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "UploadAttachment",
+			Handler:       _Chat_UploadAttachment_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadAttachment",
+			Handler:       _Chat_DownloadAttachment_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "chat.proto",
+}
+
+// ChatAdminClient is the client API for ChatAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ChatAdminClient interface {
+	ListSessions(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Sessions, error)
+	// DisconnectUser ends the user Connect stream with a MESSAGE_KIND_DISCONNECTED message
+	DisconnectUser(ctx context.Context, in *UsernameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetGroup(ctx context.Context, in *GroupChannelNameRequest, opts ...grpc.CallOption) (*Group, error)
+	DeleteGroup(ctx context.Context, in *GroupChannelNameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Broadcast pushes a MESSAGE_KIND_ANNOUNCEMENT message to all connected users at start_at,
+	// users connecting before expire_at receive it as well
+	Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*Announcement, error)
+	ListAnnouncements(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Announcements, error)
+	CancelAnnouncement(ctx context.Context, in *AnnouncementRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	QueryAuditLog(ctx context.Context, in *AuditLogQuery, opts ...grpc.CallOption) (*AuditRecords, error)
+}
+
+type chatAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChatAdminClient(cc grpc.ClientConnInterface) ChatAdminClient {
+	return &chatAdminClient{cc}
+}
+
+func (c *chatAdminClient) ListSessions(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Sessions, error) {
+	out := new(Sessions)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.ChatAdmin/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatAdminClient) DisconnectUser(ctx context.Context, in *UsernameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.ChatAdmin/DisconnectUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatAdminClient) GetGroup(ctx context.Context, in *GroupChannelNameRequest, opts ...grpc.CallOption) (*Group, error) {
+	out := new(Group)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.ChatAdmin/GetGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatAdminClient) DeleteGroup(ctx context.Context, in *GroupChannelNameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.ChatAdmin/DeleteGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatAdminClient) Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*Announcement, error) {
+	out := new(Announcement)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.ChatAdmin/Broadcast", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatAdminClient) ListAnnouncements(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Announcements, error) {
+	out := new(Announcements)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.ChatAdmin/ListAnnouncements", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatAdminClient) CancelAnnouncement(ctx context.Context, in *AnnouncementRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.ChatAdmin/CancelAnnouncement", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatAdminClient) QueryAuditLog(ctx context.Context, in *AuditLogQuery, opts ...grpc.CallOption) (*AuditRecords, error) {
+	out := new(AuditRecords)
+	err := c.cc.Invoke(ctx, "/b2bchatapi.ChatAdmin/QueryAuditLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChatAdminServer is the server API for ChatAdmin service.
+// All implementations must embed UnimplementedChatAdminServer
+// for forward compatibility
+type ChatAdminServer interface {
+	ListSessions(context.Context, *emptypb.Empty) (*Sessions, error)
+	// DisconnectUser ends the user Connect stream with a MESSAGE_KIND_DISCONNECTED message
+	DisconnectUser(context.Context, *UsernameRequest) (*emptypb.Empty, error)
+	GetGroup(context.Context, *GroupChannelNameRequest) (*Group, error)
+	DeleteGroup(context.Context, *GroupChannelNameRequest) (*emptypb.Empty, error)
+	// Broadcast pushes a MESSAGE_KIND_ANNOUNCEMENT message to all connected users at start_at,
+	// users connecting before expire_at receive it as well
+	Broadcast(context.Context, *BroadcastRequest) (*Announcement, error)
+	ListAnnouncements(context.Context, *emptypb.Empty) (*Announcements, error)
+	CancelAnnouncement(context.Context, *AnnouncementRequest) (*emptypb.Empty, error)
+	QueryAuditLog(context.Context, *AuditLogQuery) (*AuditRecords, error)
+	mustEmbedUnimplementedChatAdminServer()
+}
+
+// UnimplementedChatAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedChatAdminServer struct {
+}
+
+func (UnimplementedChatAdminServer) ListSessions(context.Context, *emptypb.Empty) (*Sessions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedChatAdminServer) DisconnectUser(context.Context, *UsernameRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisconnectUser not implemented")
+}
+func (UnimplementedChatAdminServer) GetGroup(context.Context, *GroupChannelNameRequest) (*Group, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (UnimplementedChatAdminServer) DeleteGroup(context.Context, *GroupChannelNameRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroup not implemented")
+}
+func (UnimplementedChatAdminServer) Broadcast(context.Context, *BroadcastRequest) (*Announcement, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
+}
+func (UnimplementedChatAdminServer) ListAnnouncements(context.Context, *emptypb.Empty) (*Announcements, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAnnouncements not implemented")
+}
+func (UnimplementedChatAdminServer) CancelAnnouncement(context.Context, *AnnouncementRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelAnnouncement not implemented")
+}
+func (UnimplementedChatAdminServer) QueryAuditLog(context.Context, *AuditLogQuery) (*AuditRecords, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAuditLog not implemented")
+}
+func (UnimplementedChatAdminServer) mustEmbedUnimplementedChatAdminServer() {}
+
+// UnsafeChatAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChatAdminServer will
+// result in compilation errors.
+type UnsafeChatAdminServer interface {
+	mustEmbedUnimplementedChatAdminServer()
+}
+
+func RegisterChatAdminServer(s grpc.ServiceRegistrar, srv ChatAdminServer) {
+	s.RegisterService(&ChatAdmin_ServiceDesc, srv)
+}
+
+func _ChatAdmin_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatAdminServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.ChatAdmin/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatAdminServer).ListSessions(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatAdmin_DisconnectUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsernameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatAdminServer).DisconnectUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.ChatAdmin/DisconnectUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatAdminServer).DisconnectUser(ctx, req.(*UsernameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatAdmin_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupChannelNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatAdminServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.ChatAdmin/GetGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatAdminServer).GetGroup(ctx, req.(*GroupChannelNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatAdmin_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupChannelNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatAdminServer).DeleteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.ChatAdmin/DeleteGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatAdminServer).DeleteGroup(ctx, req.(*GroupChannelNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatAdmin_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatAdminServer).Broadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.ChatAdmin/Broadcast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatAdminServer).Broadcast(ctx, req.(*BroadcastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatAdmin_ListAnnouncements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatAdminServer).ListAnnouncements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.ChatAdmin/ListAnnouncements",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatAdminServer).ListAnnouncements(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatAdmin_CancelAnnouncement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnouncementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatAdminServer).CancelAnnouncement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.ChatAdmin/CancelAnnouncement",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatAdminServer).CancelAnnouncement(ctx, req.(*AnnouncementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatAdmin_QueryAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditLogQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatAdminServer).QueryAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/b2bchatapi.ChatAdmin/QueryAuditLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatAdminServer).QueryAuditLog(ctx, req.(*AuditLogQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ChatAdmin_ServiceDesc is the grpc.ServiceDesc for ChatAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ChatAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "b2bchatapi.ChatAdmin",
+	HandlerType: (*ChatAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSessions",
+			Handler:    _ChatAdmin_ListSessions_Handler,
+		},
+		{
+			MethodName: "DisconnectUser",
+			Handler:    _ChatAdmin_DisconnectUser_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _ChatAdmin_GetGroup_Handler,
+		},
+		{
+			MethodName: "DeleteGroup",
+			Handler:    _ChatAdmin_DeleteGroup_Handler,
+		},
+		{
+			MethodName: "Broadcast",
+			Handler:    _ChatAdmin_Broadcast_Handler,
+		},
+		{
+			MethodName: "ListAnnouncements",
+			Handler:    _ChatAdmin_ListAnnouncements_Handler,
+		},
+		{
+			MethodName: "CancelAnnouncement",
+			Handler:    _ChatAdmin_CancelAnnouncement_Handler,
+		},
+		{
+			MethodName: "QueryAuditLog",
+			Handler:    _ChatAdmin_QueryAuditLog_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "chat.proto",
 }
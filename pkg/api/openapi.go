@@ -0,0 +1,8 @@
+package chat
+
+import _ "embed"
+
+// OpenAPI is a specification of Chat REST facade generated from http annotations
+//
+//go:embed chat.swagger.json
+var OpenAPI []byte
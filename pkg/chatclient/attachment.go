@@ -0,0 +1,88 @@
+package chatclient
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+)
+
+// chunkSize is a size of attachment upload chunks
+const chunkSize = 64 * 1024
+
+// Upload posts content read from r as an attachment, returns attachment id to be sent along with a message
+func (c *Client) Upload(ctx context.Context, to Destination, fileName, mimeType string, r io.Reader) (string, error) {
+	info := &chatApi.AttachmentInfo{FileName: fileName, MimeType: mimeType}
+	if err := to.setAttachment(info); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.chat.UploadAttachment(c.outgoing(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	if err = stream.Send(&chatApi.AttachmentChunk{Payload: &chatApi.AttachmentChunk_Info{Info: info}}); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&chatApi.AttachmentChunk{Payload: &chatApi.AttachmentChunk_Data{Data: buf[:n]}}); sendErr != nil {
+				return "", sendErr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	attachment, err := stream.CloseAndRecv()
+	if err != nil {
+		return "", err
+	}
+
+	return attachment.GetId(), nil
+}
+
+// Download writes attachment content to w, returns attachment info
+func (c *Client) Download(ctx context.Context, id string, w io.Writer) (*chatApi.AttachmentInfo, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.chat.DownloadAttachment(c.outgoing(ctx), &chatApi.AttachmentRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return first.GetInfo(), nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = w.Write(chunk.GetData()); err != nil {
+			return nil, err
+		}
+	}
+}
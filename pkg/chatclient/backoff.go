@@ -0,0 +1,47 @@
+package chatclient
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// jitter is seeded, so clients started together don't share their delays
+var jitter = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// DefaultBackoff starts reconnects after half a second and slows them down to one per 30 seconds
+var DefaultBackoff = Backoff{
+	Base:       500 * time.Millisecond,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// Backoff is a policy of reconnect delays, a delay grows by Multiplier from Base up to Max. Jitter is a share
+// of the delay it's randomly changed by, so clients dropped at once don't reconnect at once
+type Backoff struct {
+	Base       time.Duration
+	Max        time.Duration
+	Multiplier float64
+	Jitter     float64
+}
+
+// Delay provides a delay before the reconnect attempt, attempts start from 0
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := float64(b.Base) * math.Pow(b.Multiplier, float64(attempt))
+	if delay > float64(b.Max) || math.IsInf(delay, 0) || math.IsNaN(delay) {
+		delay = float64(b.Max)
+	}
+
+	if b.Jitter > 0 {
+		jitter.Lock()
+		delay *= 1 + b.Jitter*(2*jitter.Float64()-1)
+		jitter.Unlock()
+	}
+
+	return time.Duration(delay)
+}
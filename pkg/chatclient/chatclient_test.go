@@ -0,0 +1,304 @@
+//go:build unit_tests
+// +build unit_tests
+
+package chatclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"github.com/ITheCorgi/grpc-chat-room/pkg/e2ee"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// fakeChat serves Connect streams by connects in order, every call records authorization metadata. Sent
+// messages are kept, direct ones are rejected while the recipient is offline
+type fakeChat struct {
+	chatApi.UnimplementedChatServer
+
+	mu       sync.Mutex
+	users    []string
+	connects []func(chatApi.Chat_ConnectServer) error
+	keys     *chatApi.KeyBundle
+	offline  bool
+	sent     []*chatApi.ChatMessage
+}
+
+func (f *fakeChat) Connect(_ *chatApi.ConnectRequest, stream chatApi.Chat_ConnectServer) error {
+	f.record(stream.Context())
+
+	f.mu.Lock()
+	connect := f.connects[0]
+	if len(f.connects) > 1 {
+		f.connects = f.connects[1:]
+	}
+	f.mu.Unlock()
+
+	return connect(stream)
+}
+
+func (f *fakeChat) JoinGroupChat(ctx context.Context, _ *chatApi.GroupChannelNameRequest) (*emptypb.Empty, error) {
+	f.record(ctx)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeChat) SendMessage(ctx context.Context, msg *chatApi.ChatMessage) (*emptypb.Empty, error) {
+	f.record(ctx)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.offline {
+		return nil, status.Error(codes.NotFound, "recipient is offline, direct message is not delivered")
+	}
+
+	f.sent = append(f.sent, msg)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeChat) GetKeys(ctx context.Context, _ *chatApi.UsernameRequest) (*chatApi.KeyBundle, error) {
+	f.record(ctx)
+
+	return f.keys, nil
+}
+
+func (f *fakeChat) record(ctx context.Context) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.users = append(f.users, md.Get("authorization")...)
+}
+
+func Test_Listen(t *testing.T) {
+	t.Run("test stream is resumed until session is closed by server", func(t *testing.T) {
+		chat := &fakeChat{connects: []func(chatApi.Chat_ConnectServer) error{
+			func(chatApi.Chat_ConnectServer) error {
+				return status.Error(codes.Unavailable, "server is shutting down")
+			},
+			func(stream chatApi.Chat_ConnectServer) error {
+				stream.SendHeader(metadata.MD{})
+				stream.Send(&chatApi.ChatMessage{Content: &chatApi.ChatMessage_Message{Message: "hello"}})
+
+				return stream.Send(&chatApi.ChatMessage{Kind: chatApi.MessageKind_MESSAGE_KIND_SERVER_GOING_AWAY})
+			},
+			func(stream chatApi.Chat_ConnectServer) error {
+				stream.SendHeader(metadata.MD{})
+				stream.Send(&chatApi.ChatMessage{Kind: chatApi.MessageKind_MESSAGE_KIND_BACKLOG})
+
+				return stream.Send(&chatApi.ChatMessage{Kind: chatApi.MessageKind_MESSAGE_KIND_DISCONNECTED})
+			},
+		}}
+
+		client := newTestClient(t, chat)
+
+		var events []Event
+		err := client.Listen(context.Background(), func(e Event) {
+			events = append(events, e)
+		})
+		if !errors.Is(err, ErrDisconnected) {
+			t.Fatalf("error mismatch: exp: %v, act: %v", ErrDisconnected, err)
+		}
+
+		exp := []EventType{EventReconnecting, EventConnected, EventMessage, EventReconnecting, EventConnected, EventBacklog}
+		if len(events) != len(exp) {
+			t.Fatalf("events amount mismatch: exp: %d, act: %d", len(exp), len(events))
+		}
+
+		for i := range exp {
+			if events[i].Type != exp[i] {
+				t.Errorf("event %d type mismatch: exp: %d, act: %d", i, exp[i], events[i].Type)
+			}
+		}
+
+		if status.Code(events[0].Err) != codes.Unavailable || !errors.Is(events[3].Err, ErrGoingAway) {
+			t.Errorf("got wrong reconnect reasons: %v, %v", events[0].Err, events[3].Err)
+		}
+
+		for _, user := range chat.users {
+			if user != "alice" {
+				t.Errorf("authorization mismatch: exp: alice, act: %s", user)
+			}
+		}
+	})
+
+	t.Run("test rejected stream is not resumed", func(t *testing.T) {
+		chat := &fakeChat{connects: []func(chatApi.Chat_ConnectServer) error{
+			func(chatApi.Chat_ConnectServer) error {
+				return status.Error(codes.Unauthenticated, "unknown user")
+			},
+		}}
+
+		err := newTestClient(t, chat).Listen(context.Background(), func(Event) {})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("error mismatch: exp: %v, act: %v", codes.Unauthenticated, err)
+		}
+	})
+
+	t.Run("test listen stops once ctx is done", func(t *testing.T) {
+		chat := &fakeChat{connects: []func(chatApi.Chat_ConnectServer) error{
+			func(chatApi.Chat_ConnectServer) error {
+				return status.Error(codes.Unavailable, "server is shutting down")
+			},
+		}}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		if err := newTestClient(t, chat).Listen(ctx, func(Event) {}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func Test_Join(t *testing.T) {
+	t.Run("test calls carry user name", func(t *testing.T) {
+		chat := &fakeChat{}
+
+		if err := newTestClient(t, chat).Join(context.Background(), "group"); err != nil {
+			t.Fatalf("failed to join: %v", err)
+		}
+
+		if len(chat.users) != 1 || chat.users[0] != "alice" {
+			t.Errorf("authorization mismatch: exp: [alice], act: %v", chat.users)
+		}
+	})
+}
+
+func Test_SendMessage(t *testing.T) {
+	t.Run("test direct message to offline user is reported to sender", func(t *testing.T) {
+		chat := &fakeChat{offline: true}
+		client := newTestClient(t, chat)
+
+		if err := client.Send(context.Background(), ToUser("bob"), "hi"); !errors.Is(err, ErrRecipientOffline) {
+			t.Errorf("error mismatch: exp: %v, act: %v", ErrRecipientOffline, err)
+		}
+
+		chat.offline = false
+		if err := client.Send(context.Background(), ToUser("bob"), "hi"); err != nil {
+			t.Errorf("failed to send message once recipient is back: %v", err)
+		}
+	})
+}
+
+func Test_SendEncrypted(t *testing.T) {
+	newBundle := func(t *testing.T) (*e2ee.Keyring, *chatApi.KeyBundle) {
+		t.Helper()
+
+		keyring, err := e2ee.NewKeyring()
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		bundle, err := keyring.NewBundle(1)
+		if err != nil {
+			t.Fatalf("failed to create bundle: %v", err)
+		}
+
+		return keyring, bundle
+	}
+
+	alice, err := e2ee.NewKeyring()
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	bob, bobBundle := newBundle(t)
+	chat := &fakeChat{keys: bobBundle}
+	client := newTestClient(t, chat, WithKeyring(alice, filepath.Join(t.TempDir(), "keys.json")))
+
+	t.Run("test message is sealed for the recipient", func(t *testing.T) {
+		if err := client.SendEncrypted(context.Background(), "bob", "hi"); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+
+		if len(chat.sent) != 1 {
+			t.Fatalf("expected a message to be sent, got %d", len(chat.sent))
+		}
+
+		opened, err := bob.Open(chat.sent[0])
+		if err != nil || opened.GetMessage() != "hi" {
+			t.Errorf("failed to open message: %v %v", opened, err)
+		}
+	})
+
+	t.Run("test message is not sent for a substituted identity key", func(t *testing.T) {
+		_, chat.keys = newBundle(t)
+
+		if err := client.SendEncrypted(context.Background(), "bob", "hi"); !errors.Is(err, e2ee.ErrIdentityKeyChanged) {
+			t.Errorf("error mismatch: exp: %v, act: %v", e2ee.ErrIdentityKeyChanged, err)
+		}
+
+		if len(chat.sent) != 1 {
+			t.Errorf("expected message not to be sent, got %d messages", len(chat.sent))
+		}
+	})
+}
+
+func Test_Backoff(t *testing.T) {
+	b := Backoff{Base: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}
+
+	tcs := []struct {
+		attempt int
+		exp     time.Duration
+	}{
+		{attempt: 0, exp: 100 * time.Millisecond},
+		{attempt: 2, exp: 400 * time.Millisecond},
+		{attempt: 4, exp: time.Second},
+		{attempt: 1000, exp: time.Second},
+	}
+
+	for _, tc := range tcs {
+		if act := b.Delay(tc.attempt); act != tc.exp {
+			t.Errorf("delay of attempt %d mismatch: exp: %s, act: %s", tc.attempt, tc.exp, act)
+		}
+	}
+
+	b.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if act := b.Delay(0); act < 50*time.Millisecond || act > 150*time.Millisecond {
+			t.Fatalf("delay is out of jitter range: %s", act)
+		}
+	}
+}
+
+func newTestClient(t *testing.T, chat chatApi.ChatServer, opts ...Option) *Client {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+
+	server := grpc.NewServer()
+	chatApi.RegisterChatServer(server, chat)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	opts = append([]Option{WithBackoff(Backoff{Base: time.Millisecond, Max: time.Millisecond, Multiplier: 2})}, opts...)
+
+	return New(conn, "alice", opts...)
+}
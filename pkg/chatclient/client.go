@@ -0,0 +1,274 @@
+// Package chatclient is a client of the chat server for services embedding chat.
+//
+// Client attaches the user name to every call, keeps the message stream of the user open by reconnecting with
+// exponential backoff, and opens end-to-end encrypted direct messages once a keyring is set.
+package chatclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"github.com/ITheCorgi/grpc-chat-room/pkg/e2ee"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	ErrInvalidDestination = errors.New("destination must be either a user or a group channel")
+	// ErrRecipientOffline is returned for a direct message to a user without an open stream, it's not delivered
+	ErrRecipientOffline = errors.New("recipient is offline")
+)
+
+type (
+	// Client is a chat client of a single user, it is safe for concurrent use
+	Client struct {
+		conn     *grpc.ClientConn
+		ownsConn bool
+		chat     chatApi.ChatClient
+		user     string
+		opts     options
+	}
+
+	// Destination is a user or a group channel a message is sent to, exactly one of them is set
+	Destination struct {
+		User  string
+		Group string
+	}
+
+	// Option configures a client
+	Option func(*options)
+
+	options struct {
+		tlsConfig *tls.Config
+		dialOpts  []grpc.DialOption
+		backoff   Backoff
+		keyring   *e2ee.Keyring
+		keysFile  string
+	}
+)
+
+// ToUser is a direct message destination
+func ToUser(name string) Destination {
+	return Destination{User: name}
+}
+
+// ToGroup is a group channel destination
+func ToGroup(name string) Destination {
+	return Destination{Group: name}
+}
+
+// WithTLS connects to the server over TLS, a client certificate of the config is presented for mutual TLS.
+// Plaintext connection is used by default
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = cfg
+	}
+}
+
+// WithDialOptions adds grpc dial options, e.g. tracing interceptors
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOpts = append(o.dialOpts, opts...)
+	}
+}
+
+// WithBackoff sets a policy of message stream reconnects, DefaultBackoff is used by default
+func WithBackoff(b Backoff) Option {
+	return func(o *options) {
+		o.backoff = b
+	}
+}
+
+// WithKeyring enables end-to-end encrypted direct messages. Keyring is saved to keysFile whenever its prekeys
+// or pinned identity keys of peers change, empty keysFile keeps it in memory only
+func WithKeyring(keyring *e2ee.Keyring, keysFile string) Option {
+	return func(o *options) {
+		o.keyring = keyring
+		o.keysFile = keysFile
+	}
+}
+
+// Dial connects to the server at target as the user, connection is closed by Close
+func Dial(target, user string, opts ...Option) (*Client, error) {
+	o := newOptions(opts)
+
+	creds := insecure.NewCredentials()
+	if o.tlsConfig != nil {
+		creds = credentials.NewTLS(o.tlsConfig)
+	}
+
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		// pings detect a dead server while the message stream is idle, server allows them every 30 seconds
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                30 * time.Second,
+			Timeout:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
+	}, o.dialOpts...)
+
+	conn, err := grpc.Dial(target, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newClient(conn, user, o)
+	c.ownsConn = true
+
+	return c, nil
+}
+
+// New creates a client of the user calling the server through conn, conn is not closed by Close. TLS and dial
+// options are ignored, since conn is already set up
+func New(conn *grpc.ClientConn, user string, opts ...Option) *Client {
+	return newClient(conn, user, newOptions(opts))
+}
+
+func newOptions(opts []Option) options {
+	o := options{backoff: DefaultBackoff}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+func newClient(conn *grpc.ClientConn, user string, o options) *Client {
+	return &Client{
+		conn: conn,
+		chat: chatApi.NewChatClient(conn),
+		user: user,
+		opts: o,
+	}
+}
+
+// User provides a name of the client user
+func (c *Client) User() string {
+	return c.user
+}
+
+// Close closes connection opened by Dial
+func (c *Client) Close() error {
+	if !c.ownsConn {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+// Send sends a text message
+func (c *Client) Send(ctx context.Context, to Destination, text string) error {
+	msg := &chatApi.ChatMessage{Content: &chatApi.ChatMessage_Message{Message: text}}
+	if err := to.setMessage(msg); err != nil {
+		return err
+	}
+
+	return c.SendMessage(ctx, msg)
+}
+
+// SendMessage sends a message of any content, msg must have its destination set. Direct messages are not kept
+// for offline users, ErrRecipientOffline is returned then, e.g. while the recipient is reconnecting
+func (c *Client) SendMessage(ctx context.Context, msg *chatApi.ChatMessage) error {
+	_, err := c.chat.SendMessage(c.outgoing(ctx), msg)
+	if msg.GetUsername() != "" && status.Code(err) == codes.NotFound {
+		return fmt.Errorf("%w: %s", ErrRecipientOffline, status.Convert(err).Message())
+	}
+
+	return err
+}
+
+// Create creates a group channel with the user as its first member
+func (c *Client) Create(ctx context.Context, group string) error {
+	_, err := c.chat.CreateGroupChat(c.outgoing(ctx), &chatApi.GroupChannelNameRequest{GroupChannelName: group})
+
+	return err
+}
+
+// Join adds the user to a group channel
+func (c *Client) Join(ctx context.Context, group string) error {
+	_, err := c.chat.JoinGroupChat(c.outgoing(ctx), &chatApi.GroupChannelNameRequest{GroupChannelName: group})
+
+	return err
+}
+
+// Leave removes the user from a group channel
+func (c *Client) Leave(ctx context.Context, group string) error {
+	_, err := c.chat.LeaveGroupChat(c.outgoing(ctx), &chatApi.GroupChannelNameRequest{GroupChannelName: group})
+
+	return err
+}
+
+// List provides existing channels
+func (c *Client) List(ctx context.Context) ([]*chatApi.Channels_Channel, error) {
+	channels, err := c.chat.ListChannels(c.outgoing(ctx), &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	return channels.GetItems(), nil
+}
+
+// Block drops direct messages of a user to the client user
+func (c *Client) Block(ctx context.Context, user string) error {
+	_, err := c.chat.BlockUser(c.outgoing(ctx), &chatApi.UsernameRequest{Username: user})
+
+	return err
+}
+
+// Unblock allows direct messages of a blocked user again
+func (c *Client) Unblock(ctx context.Context, user string) error {
+	_, err := c.chat.UnblockUser(c.outgoing(ctx), &chatApi.UsernameRequest{Username: user})
+
+	return err
+}
+
+// Blocked provides users blocked by the client user
+func (c *Client) Blocked(ctx context.Context) ([]string, error) {
+	blocked, err := c.chat.ListBlocked(c.outgoing(ctx), &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	return blocked.GetItems(), nil
+}
+
+// outgoing attaches user name to a call
+func (c *Client) outgoing(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", c.user)
+}
+
+func (d Destination) setMessage(msg *chatApi.ChatMessage) error {
+	switch {
+	case d.User != "" && d.Group == "":
+		msg.Destination = &chatApi.ChatMessage_Username{Username: d.User}
+	case d.Group != "" && d.User == "":
+		msg.Destination = &chatApi.ChatMessage_GroupChannelName{GroupChannelName: d.Group}
+	default:
+		return ErrInvalidDestination
+	}
+
+	return nil
+}
+
+func (d Destination) setAttachment(info *chatApi.AttachmentInfo) error {
+	switch {
+	case d.User != "" && d.Group == "":
+		info.Destination = &chatApi.AttachmentInfo_Username{Username: d.User}
+	case d.Group != "" && d.User == "":
+		info.Destination = &chatApi.AttachmentInfo_GroupChannelName{GroupChannelName: d.Group}
+	default:
+		return ErrInvalidDestination
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package chatclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+)
+
+var ErrNoKeyring = errors.New("end-to-end encryption is not enabled")
+
+// UploadPrekeys adds n prekeys to the keyring and uploads its public keys, so other users can send encrypted
+// messages to the user. Keyring is saved first, so messages sealed with uploaded prekeys can be opened after
+// restart
+func (c *Client) UploadPrekeys(ctx context.Context, n int) error {
+	if c.opts.keyring == nil {
+		return ErrNoKeyring
+	}
+
+	bundle, err := c.opts.keyring.NewBundle(n)
+	if err != nil {
+		return err
+	}
+
+	if err = c.saveKeyring(); err != nil {
+		return err
+	}
+
+	_, err = c.chat.UploadKeys(c.outgoing(ctx), bundle)
+
+	return err
+}
+
+// SendEncrypted sends a text direct message encrypted for the recipient by keys taken from the key directory.
+// Identity key of the recipient is trusted on first use, e2ee.ErrIdentityKeyChanged is returned and nothing is
+// sent once the directory hands out another one
+func (c *Client) SendEncrypted(ctx context.Context, user, text string) error {
+	if c.opts.keyring == nil {
+		return ErrNoKeyring
+	}
+
+	keys, err := c.chat.GetKeys(c.outgoing(ctx), &chatApi.UsernameRequest{Username: user})
+	if err != nil {
+		return err
+	}
+
+	msg, err := c.opts.keyring.Seal(&chatApi.ChatMessage{
+		Destination: &chatApi.ChatMessage_Username{Username: user},
+		Content:     &chatApi.ChatMessage_Message{Message: text},
+	}, keys)
+	if err != nil {
+		return err
+	}
+
+	// the identity key pinned on first use is kept
+	if err = c.saveKeyring(); err != nil {
+		return fmt.Errorf("save keyring: %w", err)
+	}
+
+	return c.SendMessage(ctx, msg)
+}
+
+// open decrypts a direct message, identity key of the sender is trusted on first use and saved with the keyring
+func (c *Client) open(msg *chatApi.ChatMessage) Event {
+	if c.opts.keyring == nil {
+		return Event{Type: EventMessage, Message: msg, Err: ErrNoKeyring}
+	}
+
+	opened, err := c.opts.keyring.Open(msg)
+	if err != nil {
+		return Event{Type: EventMessage, Message: msg, Err: err}
+	}
+
+	// identity key is pinned once a message proves the sender holds it
+	isChanged := c.opts.keyring.Pin(msg.GetUsername(), msg.GetCiphertext().GetSenderIdentityKey())
+
+	// the prekey used or the ephemeral key seen is kept in the keyring along with the pinned identity key
+	if err = c.saveKeyring(); err != nil {
+		err = fmt.Errorf("save keyring: %w", err)
+	}
+
+	return Event{Type: EventMessage, Message: opened, Encrypted: true, IdentityChanged: isChanged, Err: err}
+}
+
+func (c *Client) saveKeyring() error {
+	if c.opts.keysFile == "" {
+		return nil
+	}
+
+	return c.opts.keyring.Save(c.opts.keysFile)
+}
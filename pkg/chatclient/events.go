@@ -0,0 +1,172 @@
+package chatclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// EventMessage is a message sent to the user or to a group of the user
+	EventMessage EventType = iota
+	// EventBacklog is a group message sent while the user was offline, it's delivered once the stream is open
+	EventBacklog
+	// EventActivitySummary lists groups with messages missed while the user was offline
+	EventActivitySummary
+	// EventAnnouncement is a system message broadcast by an operator
+	EventAnnouncement
+	// EventConnected tells the stream is open, it comes again after every reconnect
+	EventConnected
+	// EventReconnecting tells the stream is lost, Err is a reason and Delay is a time before the next attempt
+	EventReconnecting
+)
+
+var (
+	// ErrDisconnected is returned by Listen once server closes the session, e.g. the user has connected elsewhere
+	ErrDisconnected = errors.New("session is closed by server")
+	// ErrGoingAway is a reconnect reason of a server shutting down
+	ErrGoingAway = errors.New("server is going away")
+	// ErrStreamEnded is a reconnect reason of a stream ended without a reason
+	ErrStreamEnded = errors.New("message stream has ended")
+)
+
+type (
+	// EventType is a kind of message stream event
+	EventType uint8
+
+	// Event is a message or a state change of the message stream
+	Event struct {
+		Type EventType
+		// Message is set for message, backlog, activity summary and announcement events
+		Message *chatApi.ChatMessage
+		// Encrypted tells Message is decrypted from an end-to-end encrypted one
+		Encrypted bool
+		// IdentityChanged tells identity key of an encrypted message sender differs from the one pinned before
+		IdentityChanged bool
+		// Err is a reason of a reconnect or an error of an encrypted message. Message is left encrypted when it
+		// can't be opened, a keyring which can't be saved comes along with the opened message
+		Err error
+		// Delay is a time before the next reconnect attempt
+		Delay time.Duration
+	}
+)
+
+// Listen opens the message stream of the user and calls handler with its events until ctx is done. A lost
+// stream is opened again with backoff, group messages missed meanwhile come as backlog events when server keeps
+// backlog. Handler is called from a single goroutine, a slow handler holds the stream back, so server may close
+// the session. Listen returns nil once ctx is done, ErrDisconnected once server closes the session, or an error
+// which can't be resolved by a reconnect
+func (c *Client) Listen(ctx context.Context, handler func(Event)) error {
+	attempt := 0
+
+	for {
+		isConnected, err := c.listen(ctx, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if isConnected {
+			attempt = 0
+		}
+
+		if !isRetryable(err) {
+			return err
+		}
+
+		delay := c.opts.backoff.Delay(attempt)
+		attempt++
+
+		handler(Event{Type: EventReconnecting, Err: err, Delay: delay})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// listen serves a single stream until it fails, isConnected tells server has registered the session
+func (c *Client) listen(ctx context.Context, handler func(Event)) (isConnected bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.chat.Connect(c.outgoing(ctx), &chatApi.ConnectRequest{Username: c.user})
+	if err != nil {
+		return false, err
+	}
+
+	// server sends headers once the session is registered, a stream failed before has no headers
+	if md, err := stream.Header(); err != nil || md == nil {
+		_, err = stream.Recv()
+		return false, streamError(err)
+	}
+
+	handler(Event{Type: EventConnected})
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return true, streamError(err)
+		}
+
+		switch msg.GetKind() {
+		case chatApi.MessageKind_MESSAGE_KIND_SERVER_GOING_AWAY:
+			return true, ErrGoingAway
+		case chatApi.MessageKind_MESSAGE_KIND_DISCONNECTED:
+			return true, ErrDisconnected
+		}
+
+		handler(c.event(msg))
+	}
+}
+
+func (c *Client) event(msg *chatApi.ChatMessage) Event {
+	switch msg.GetKind() {
+	case chatApi.MessageKind_MESSAGE_KIND_BACKLOG:
+		return Event{Type: EventBacklog, Message: msg}
+	case chatApi.MessageKind_MESSAGE_KIND_ACTIVITY_SUMMARY:
+		return Event{Type: EventActivitySummary, Message: msg}
+	case chatApi.MessageKind_MESSAGE_KIND_ANNOUNCEMENT:
+		return Event{Type: EventAnnouncement, Message: msg}
+	}
+
+	if msg.GetCiphertext() != nil {
+		return c.open(msg)
+	}
+
+	return Event{Type: EventMessage, Message: msg}
+}
+
+func streamError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return ErrStreamEnded
+	}
+
+	return err
+}
+
+// isRetryable tells whether a stream error may be resolved by a reconnect, errors of a request server rejects
+// are returned as they are
+func isRetryable(err error) bool {
+	switch {
+	case errors.Is(err, ErrGoingAway), errors.Is(err, ErrStreamEnded):
+		return true
+	case errors.Is(err, ErrDisconnected):
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.DeadlineExceeded:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,404 @@
+// Package e2ee encrypts direct messages end to end, so the chat server relays them without being able to read them.
+//
+// A message key is derived by HKDF-SHA256 from two X25519 agreements: a one-time ephemeral key of the sender with
+// the recipient prekey, or with the recipient identity key once the recipient has no prekeys left, and the sender
+// identity key with the recipient identity key, which proves the sender holds its identity key. Message content
+// is sealed by ChaCha20-Poly1305. A prekey is removed once used, ephemeral keys of messages sealed with the identity
+// key are remembered instead, so a message relayed again is not opened twice.
+package e2ee
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/ITheCorgi/grpc-chat-room/internal/fsutil"
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+	"google.golang.org/protobuf/proto"
+)
+
+const keySize = curve25519.ScalarSize
+
+// maxSeen is an amount of ephemeral keys remembered, the oldest ones are forgotten first
+const maxSeen = 4096
+
+// info binds derived keys to the protocol version
+var info = []byte("grpc-chat-room e2ee v1")
+
+var (
+	ErrNotEncrypted   = errors.New("message is not encrypted")
+	ErrUnknownPrekey  = errors.New("prekey is unknown or already used")
+	ErrDecrypt        = errors.New("message can't be decrypted")
+	ErrReplayed       = errors.New("message is already opened")
+	ErrNotDirect      = errors.New("only direct messages may be encrypted")
+	ErrInvalidKeySize = errors.New("invalid key size")
+	// ErrIdentityKeyChanged is returned for a recipient identity key other than the pinned one, the key directory
+	// may have been tampered with. A changed key is trusted once a message of the peer proves it holds the key
+	ErrIdentityKeyChanged = errors.New("identity key of the peer has changed")
+)
+
+type (
+	// Keyring keeps private keys of a user, it is safe for concurrent use
+	Keyring struct {
+		mu       sync.Mutex
+		identity []byte
+		// prekeys maps a prekey id to its private key
+		prekeys map[string][]byte
+		// peers maps a user to the public identity key trusted on first use
+		peers map[string][]byte
+		// seen keeps ephemeral keys of opened messages sealed without a prekey in order of opening
+		seen [][]byte
+		// isSeen indexes seen by key
+		isSeen map[string]bool
+	}
+
+	// keyringFile is a stored keyring, json encodes keys in base64
+	keyringFile struct {
+		Identity []byte            `json:"identity"`
+		Prekeys  map[string][]byte `json:"prekeys"`
+		Peers    map[string][]byte `json:"peers,omitempty"`
+		Seen     [][]byte          `json:"seen,omitempty"`
+	}
+)
+
+// NewKeyring generates a keyring with a new identity key
+func NewKeyring() (*Keyring, error) {
+	identity, err := newPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keyring{
+		identity: identity,
+		prekeys:  make(map[string][]byte),
+		peers:    make(map[string][]byte),
+		isSeen:   make(map[string]bool),
+	}, nil
+}
+
+// LoadKeyring reads a keyring saved before
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f keyringFile
+	if err = json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode keyring: %w", err)
+	}
+
+	if len(f.Identity) != keySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	for _, key := range f.Prekeys {
+		if len(key) != keySize {
+			return nil, ErrInvalidKeySize
+		}
+	}
+
+	if f.Prekeys == nil {
+		f.Prekeys = make(map[string][]byte)
+	}
+
+	if f.Peers == nil {
+		f.Peers = make(map[string][]byte)
+	}
+
+	k := &Keyring{identity: f.Identity, prekeys: f.Prekeys, peers: f.Peers, isSeen: make(map[string]bool, len(f.Seen))}
+	for _, key := range f.Seen {
+		k.remember(key)
+	}
+
+	return k, nil
+}
+
+// Save writes the keyring readable by the owner only, the file is replaced at once
+func (k *Keyring) Save(path string) error {
+	k.mu.Lock()
+	data, err := json.Marshal(keyringFile{Identity: k.identity, Prekeys: k.prekeys, Peers: k.peers, Seen: k.seen})
+	k.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return fsutil.WriteAtomic(path, data)
+}
+
+// Pin trusts the first identity key of the user and reports whether a key trusted before has changed. The new
+// key is trusted from then on. Pinned keys are saved along with the keyring, so a change is noticed after restart
+func (k *Keyring) Pin(user string, identityKey []byte) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	known, ok := k.peers[user]
+	if ok && bytes.Equal(known, identityKey) {
+		return false
+	}
+
+	k.peers[user] = append([]byte{}, identityKey...)
+
+	return ok
+}
+
+// Verify trusts the first identity key of the user, unlike Pin it keeps the key trusted before and returns
+// ErrIdentityKeyChanged for another key, so keys taken from the key directory never replace pinned ones
+func (k *Keyring) Verify(user string, identityKey []byte) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	known, ok := k.peers[user]
+	if !ok {
+		k.peers[user] = append([]byte{}, identityKey...)
+		return nil
+	}
+
+	if !bytes.Equal(known, identityKey) {
+		return ErrIdentityKeyChanged
+	}
+
+	return nil
+}
+
+// IdentityKey provides the public identity key
+func (k *Keyring) IdentityKey() []byte {
+	public, _ := curve25519.X25519(k.identity, curve25519.Basepoint)
+	return public
+}
+
+// NewBundle generates n prekeys and provides public keys to upload to the key directory. Prekeys generated
+// before are kept, so messages encrypted with them can still be opened
+func (k *Keyring) NewBundle(n int) (*chatApi.KeyBundle, error) {
+	bundle := &chatApi.KeyBundle{IdentityKey: k.IdentityKey()}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		private, err := newPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+
+		public, err := curve25519.X25519(private, curve25519.Basepoint)
+		if err != nil {
+			return nil, err
+		}
+
+		id := make([]byte, 12)
+		if _, err = io.ReadFull(rand.Reader, id); err != nil {
+			return nil, err
+		}
+
+		prekey := &chatApi.Prekey{Id: base64.RawURLEncoding.EncodeToString(id), PublicKey: public}
+		k.prekeys[prekey.Id] = private
+		bundle.Prekeys = append(bundle.Prekeys, prekey)
+	}
+
+	return bundle, nil
+}
+
+// Seal encrypts content of a direct message to the recipient keys taken from the key directory, it provides
+// a copy of the message carrying ciphertext instead of content. Recipient identity key is verified as Verify
+// does, so nothing is sealed for a key other than the pinned one
+func (k *Keyring) Seal(msg *chatApi.ChatMessage, recipient *chatApi.KeyBundle) (*chatApi.ChatMessage, error) {
+	if msg.GetUsername() == "" {
+		return nil, ErrNotDirect
+	}
+
+	recipientIdentity := recipient.GetIdentityKey()
+	if len(recipientIdentity) != keySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	if err := k.Verify(msg.GetUsername(), recipientIdentity); err != nil {
+		return nil, err
+	}
+
+	// directory hands out one prekey at most, identity key takes its place once prekeys are exhausted
+	var (
+		prekeyID  string
+		agreedKey = recipientIdentity
+	)
+
+	if prekeys := recipient.GetPrekeys(); len(prekeys) > 0 {
+		prekeyID, agreedKey = prekeys[0].GetId(), prekeys[0].GetPublicKey()
+	}
+
+	ephemeral, err := newPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralPublic, err := curve25519.X25519(ephemeral, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ct := &chatApi.Ciphertext{
+		SenderIdentityKey: k.IdentityKey(),
+		EphemeralKey:      ephemeralPublic,
+		PrekeyId:          prekeyID,
+		Nonce:             make([]byte, chacha20poly1305.NonceSize),
+	}
+
+	key, err := deriveKey(ephemeral, agreedKey, k.identity, recipientIdentity)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = io.ReadFull(rand.Reader, ct.Nonce); err != nil {
+		return nil, err
+	}
+
+	plaintext, err := proto.Marshal(&chatApi.ChatMessage{Content: msg.Content})
+	if err != nil {
+		return nil, err
+	}
+
+	ct.Payload = aead.Seal(nil, ct.Nonce, plaintext, additionalData(ct, recipientIdentity))
+
+	res := proto.Clone(msg).(*chatApi.ChatMessage)
+	res.Content = &chatApi.ChatMessage_Ciphertext{Ciphertext: ct}
+
+	return res, nil
+}
+
+// Open decrypts a direct message, it provides a copy of the message with content restored. The prekey used
+// is removed, so the same ciphertext can't be opened twice, ErrReplayed is returned for a message sealed with
+// the identity key which has been opened already. Callers should check the sender identity key
+// before trusting the content, since the key directory is served by the chat server
+func (k *Keyring) Open(msg *chatApi.ChatMessage) (*chatApi.ChatMessage, error) {
+	ct := msg.GetCiphertext()
+	if ct == nil {
+		return nil, ErrNotEncrypted
+	}
+
+	if len(ct.GetSenderIdentityKey()) != keySize || len(ct.GetEphemeralKey()) != keySize ||
+		len(ct.GetNonce()) != chacha20poly1305.NonceSize {
+		return nil, ErrDecrypt
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if ct.GetPrekeyId() == "" && k.isSeen[string(ct.GetEphemeralKey())] {
+		return nil, ErrReplayed
+	}
+
+	agreedKey := k.identity
+	if ct.GetPrekeyId() != "" {
+		prekey, ok := k.prekeys[ct.GetPrekeyId()]
+		if !ok {
+			return nil, ErrUnknownPrekey
+		}
+
+		agreedKey = prekey
+	}
+
+	key, err := deriveKey(agreedKey, ct.GetEphemeralKey(), k.identity, ct.GetSenderIdentityKey())
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := curve25519.X25519(k.identity, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, ct.GetNonce(), ct.GetPayload(), additionalData(ct, identity))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	var content chatApi.ChatMessage
+	if err = proto.Unmarshal(plaintext, &content); err != nil || content.Content == nil {
+		return nil, ErrDecrypt
+	}
+
+	if ct.GetPrekeyId() == "" {
+		k.remember(ct.GetEphemeralKey())
+	}
+
+	delete(k.prekeys, ct.GetPrekeyId())
+
+	res := proto.Clone(msg).(*chatApi.ChatMessage)
+	res.Content = content.Content
+
+	return res, nil
+}
+
+// remember keeps an ephemeral key of an opened message, the oldest key is forgotten once there are maxSeen
+func (k *Keyring) remember(ephemeralKey []byte) {
+	if len(k.seen) >= maxSeen {
+		delete(k.isSeen, string(k.seen[0]))
+		k.seen = k.seen[1:]
+	}
+
+	k.seen = append(k.seen, append([]byte{}, ephemeralKey...))
+	k.isSeen[string(ephemeralKey)] = true
+}
+
+// deriveKey derives a message key from both agreements, arguments of sender and recipient are mirrored,
+// so they get the same key
+func deriveKey(private, public, identity, peerIdentity []byte) ([]byte, error) {
+	ephemeralSecret, err := curve25519.X25519(private, public)
+	if err != nil {
+		return nil, err
+	}
+
+	identitySecret, err := curve25519.X25519(identity, peerIdentity)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	r := hkdf.New(sha256.New, append(ephemeralSecret, identitySecret...), nil, info)
+
+	if _, err = io.ReadFull(r, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// additionalData binds the payload to the keys it is sealed with
+func additionalData(ct *chatApi.Ciphertext, recipientIdentity []byte) []byte {
+	res := make([]byte, 0, 3*keySize+len(ct.GetPrekeyId()))
+	res = append(res, ct.GetSenderIdentityKey()...)
+	res = append(res, recipientIdentity...)
+	res = append(res, ct.GetEphemeralKey()...)
+
+	return append(res, ct.GetPrekeyId()...)
+}
+
+func newPrivateKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
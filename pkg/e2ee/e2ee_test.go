@@ -0,0 +1,273 @@
+//go:build unit_tests
+// +build unit_tests
+
+package e2ee
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	chatApi "github.com/ITheCorgi/grpc-chat-room/pkg/api"
+	"google.golang.org/protobuf/proto"
+)
+
+func Test_SealOpen(t *testing.T) {
+	alice, err := NewKeyring()
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	bob, err := NewKeyring()
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	bundle, err := bob.NewBundle(2)
+	if err != nil {
+		t.Fatalf("failed to create bundle: %v", err)
+	}
+
+	msg := &chatApi.ChatMessage{
+		Destination: &chatApi.ChatMessage_Username{Username: "bob"},
+		Content:     &chatApi.ChatMessage_Code{Code: &chatApi.CodeBlock{Language: "go", Code: "fmt.Println()"}},
+	}
+
+	t.Run("test message sealed with a prekey is opened once", func(t *testing.T) {
+		// directory hands out a single prekey
+		sealed, err := alice.Seal(msg, &chatApi.KeyBundle{IdentityKey: bundle.IdentityKey, Prekeys: bundle.Prekeys[:1]})
+		if err != nil {
+			t.Fatalf("failed to seal: %v", err)
+		}
+
+		if sealed.GetCode() != nil || sealed.GetCiphertext().GetPrekeyId() != bundle.Prekeys[0].Id {
+			t.Fatalf("message is not sealed with prekey: %v", sealed)
+		}
+
+		opened, err := bob.Open(sealed)
+		if err != nil {
+			t.Fatalf("failed to open: %v", err)
+		}
+
+		if !proto.Equal(opened, msg) {
+			t.Errorf("content mismatch: exp: %v, act: %v", msg, opened)
+		}
+
+		if _, err = bob.Open(sealed); !errors.Is(err, ErrUnknownPrekey) {
+			t.Errorf("expected prekey to be used once, got: %v", err)
+		}
+	})
+
+	t.Run("test message sealed with identity key when prekeys are exhausted is opened once", func(t *testing.T) {
+		sealed, err := alice.Seal(msg, &chatApi.KeyBundle{IdentityKey: bundle.IdentityKey})
+		if err != nil {
+			t.Fatalf("failed to seal: %v", err)
+		}
+
+		opened, err := bob.Open(sealed)
+		if err != nil {
+			t.Fatalf("failed to open: %v", err)
+		}
+
+		if !proto.Equal(opened, msg) {
+			t.Errorf("content mismatch: exp: %v, act: %v", msg, opened)
+		}
+
+		if _, err = bob.Open(sealed); !errors.Is(err, ErrReplayed) {
+			t.Errorf("expected replayed message to be rejected, got: %v", err)
+		}
+	})
+
+	t.Run("test message is not sealed for identity key other than the pinned one", func(t *testing.T) {
+		mallory, err := NewKeyring()
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		if _, err = alice.Seal(msg, &chatApi.KeyBundle{IdentityKey: mallory.IdentityKey()}); !errors.Is(err, ErrIdentityKeyChanged) {
+			t.Errorf("error mismatch: exp: %v, act: %v", ErrIdentityKeyChanged, err)
+		}
+	})
+
+	t.Run("test tampered message is not opened", func(t *testing.T) {
+		sealed, err := alice.Seal(msg, &chatApi.KeyBundle{IdentityKey: bundle.IdentityKey, Prekeys: bundle.Prekeys[1:]})
+		if err != nil {
+			t.Fatalf("failed to seal: %v", err)
+		}
+
+		mallory, err := NewKeyring()
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		// a replaced sender identity changes the key
+		forged := proto.Clone(sealed).(*chatApi.ChatMessage)
+		forged.GetCiphertext().SenderIdentityKey = mallory.IdentityKey()
+
+		if _, err = bob.Open(forged); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("expected decrypt error, got: %v", err)
+		}
+
+		if _, err = mallory.Open(sealed); !errors.Is(err, ErrUnknownPrekey) {
+			t.Errorf("expected unknown prekey error, got: %v", err)
+		}
+
+		if _, err = bob.Open(sealed); err != nil {
+			t.Errorf("failed to open: %v", err)
+		}
+	})
+
+	t.Run("test group message is not sealed", func(t *testing.T) {
+		group := &chatApi.ChatMessage{
+			Destination: &chatApi.ChatMessage_GroupChannelName{GroupChannelName: "group"},
+			Content:     &chatApi.ChatMessage_Message{Message: "hi"},
+		}
+
+		if _, err = alice.Seal(group, bundle); !errors.Is(err, ErrNotDirect) {
+			t.Errorf("expected not direct error, got: %v", err)
+		}
+	})
+}
+
+func Test_Keyring(t *testing.T) {
+	t.Run("test saved keyring opens messages sealed before", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys.json")
+
+		bob, err := NewKeyring()
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		bundle, err := bob.NewBundle(1)
+		if err != nil {
+			t.Fatalf("failed to create bundle: %v", err)
+		}
+
+		if err = bob.Save(path); err != nil {
+			t.Fatalf("failed to save keyring: %v", err)
+		}
+
+		alice, err := NewKeyring()
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		sealed, err := alice.Seal(&chatApi.ChatMessage{
+			Destination: &chatApi.ChatMessage_Username{Username: "bob"},
+			Content:     &chatApi.ChatMessage_Message{Message: "hi"},
+		}, bundle)
+		if err != nil {
+			t.Fatalf("failed to seal: %v", err)
+		}
+
+		loaded, err := LoadKeyring(path)
+		if err != nil {
+			t.Fatalf("failed to load keyring: %v", err)
+		}
+
+		opened, err := loaded.Open(sealed)
+		if err != nil {
+			t.Fatalf("failed to open: %v", err)
+		}
+
+		if opened.GetMessage() != "hi" {
+			t.Errorf("content mismatch: exp: hi, act: %v", opened)
+		}
+	})
+
+	t.Run("test messages opened before are rejected after restart", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys.json")
+
+		bob, err := NewKeyring()
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		alice, err := NewKeyring()
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		sealed, err := alice.Seal(&chatApi.ChatMessage{
+			Destination: &chatApi.ChatMessage_Username{Username: "bob"},
+			Content:     &chatApi.ChatMessage_Message{Message: "hi"},
+		}, &chatApi.KeyBundle{IdentityKey: bob.IdentityKey()})
+		if err != nil {
+			t.Fatalf("failed to seal: %v", err)
+		}
+
+		if _, err = bob.Open(sealed); err != nil {
+			t.Fatalf("failed to open: %v", err)
+		}
+
+		if err = bob.Save(path); err != nil {
+			t.Fatalf("failed to save keyring: %v", err)
+		}
+
+		loaded, err := LoadKeyring(path)
+		if err != nil {
+			t.Fatalf("failed to load keyring: %v", err)
+		}
+
+		if _, err = loaded.Open(sealed); !errors.Is(err, ErrReplayed) {
+			t.Errorf("expected replayed message to be rejected, got: %v", err)
+		}
+	})
+
+	t.Run("test oldest seen ephemeral keys are forgotten", func(t *testing.T) {
+		bob, err := NewKeyring()
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		for i := 0; i <= maxSeen; i++ {
+			bob.remember([]byte{byte(i), byte(i >> 8)})
+		}
+
+		if len(bob.seen) != maxSeen || bob.isSeen[string([]byte{0, 0})] || !bob.isSeen[string([]byte{1, 0})] {
+			t.Errorf("expected the oldest key only to be forgotten, got %d keys", len(bob.seen))
+		}
+	})
+
+	t.Run("test pinned identity keys are kept after restart", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys.json")
+
+		bob, err := NewKeyring()
+		if err != nil {
+			t.Fatalf("failed to create keyring: %v", err)
+		}
+
+		if bob.Pin("alice", []byte("identity1")) {
+			t.Error("first identity key is reported as changed")
+		}
+
+		if err = bob.Save(path); err != nil {
+			t.Fatalf("failed to save keyring: %v", err)
+		}
+
+		loaded, err := LoadKeyring(path)
+		if err != nil {
+			t.Fatalf("failed to load keyring: %v", err)
+		}
+
+		if loaded.Pin("alice", []byte("identity1")) {
+			t.Error("pinned identity key is reported as changed")
+		}
+
+		if !loaded.Pin("alice", []byte("identity2")) {
+			t.Error("expected changed identity key to be reported")
+		}
+
+		if loaded.Pin("alice", []byte("identity2")) {
+			t.Error("identity key trusted after change is reported as changed")
+		}
+
+		if err = loaded.Verify("alice", []byte("identity3")); !errors.Is(err, ErrIdentityKeyChanged) {
+			t.Errorf("error mismatch: exp: %v, act: %v", ErrIdentityKeyChanged, err)
+		}
+
+		if err = loaded.Verify("alice", []byte("identity2")); err != nil {
+			t.Errorf("expected pinned identity key to be kept, got: %v", err)
+		}
+	})
+}